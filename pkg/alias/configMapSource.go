@@ -0,0 +1,51 @@
+package alias
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// ConfigMapSource provides host aliases from a dedicated config map. Every entry of the config map maps a host name
+// to an ip.
+type ConfigMapSource struct {
+	clientSet kubernetes.Interface
+	namespace string
+	name      string
+}
+
+// NewConfigMapSource creates a source for the config map with the given name.
+func NewConfigMapSource(clientSet kubernetes.Interface, namespace string, name string) *ConfigMapSource {
+	return &ConfigMapSource{clientSet: clientSet, namespace: namespace, name: name}
+}
+
+// Name returns the name of the source, e.g. 'configmap/additional-hosts'.
+func (s *ConfigMapSource) Name() string {
+	return "configmap/" + s.name
+}
+
+// HostAliases returns the host aliases of the config map ordered by their host names. It fails if the config map does
+// not exist or contains an invalid ip.
+func (s *ConfigMapSource) HostAliases(ctx context.Context) ([]v1.HostAlias, error) {
+	configMap, err := s.clientSet.CoreV1().ConfigMaps(s.namespace).Get(ctx, s.name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config map '%s': %w", s.name, err)
+	}
+
+	hostNames := sortedKeys(configMap.Data)
+
+	hostAliases := make([]v1.HostAlias, 0, len(hostNames))
+	for _, hostName := range hostNames {
+		ip := configMap.Data[hostName]
+		if net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("value '%s' of host '%s' in config map '%s' is not a valid ip", ip, hostName, s.name)
+		}
+		hostAliases = append(hostAliases, v1.HostAlias{IP: ip, Hostnames: []string{hostName}})
+	}
+
+	return hostAliases, nil
+}
@@ -0,0 +1,63 @@
+package alias
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestConfigMapSource_HostAliases(t *testing.T) {
+	const testNamespace = "ecosystem"
+
+	t.Run("should return entries of the config map", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "additional-hosts", Namespace: testNamespace},
+			Data:       map[string]string{"mail.local": "10.0.0.3", "git.local": "fd00::2"},
+		})
+		source := NewConfigMapSource(clientSet, testNamespace, "additional-hosts")
+
+		// when
+		aliases, err := source.HostAliases(context.TODO())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "configmap/additional-hosts", source.Name())
+		assert.Equal(t, []v1.HostAlias{
+			{IP: "fd00::2", Hostnames: []string{"git.local"}},
+			{IP: "10.0.0.3", Hostnames: []string{"mail.local"}},
+		}, aliases)
+	})
+	t.Run("should fail on missing config map", func(t *testing.T) {
+		// given
+		source := NewConfigMapSource(fake.NewSimpleClientset(), testNamespace, "additional-hosts")
+
+		// when
+		_, err := source.HostAliases(context.TODO())
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to get config map 'additional-hosts'")
+	})
+	t.Run("should fail on invalid ip", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(&v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "additional-hosts", Namespace: testNamespace},
+			Data:       map[string]string{"git.local": "git"},
+		})
+		source := NewConfigMapSource(clientSet, testNamespace, "additional-hosts")
+
+		// when
+		_, err := source.HostAliases(context.TODO())
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "value 'git' of host 'git.local' in config map 'additional-hosts' is not a valid ip")
+	})
+}
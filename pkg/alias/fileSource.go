@@ -0,0 +1,41 @@
+package alias
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	v1 "k8s.io/api/core/v1"
+
+	"github.com/cloudogu/k8s-host-change/pkg/hostsfile"
+)
+
+// FileSource provides host aliases from a file on disk in hosts file syntax.
+type FileSource struct {
+	path string
+}
+
+// NewFileSource creates a source for the file at the given path.
+func NewFileSource(path string) *FileSource {
+	return &FileSource{path: path}
+}
+
+// Name returns the name of the source, e.g. 'file:/etc/k8s-host-change/hosts'.
+func (s *FileSource) Name() string {
+	return "file:" + s.path
+}
+
+// HostAliases reads and parses the file.
+func (s *FileSource) HostAliases(_ context.Context) ([]v1.HostAlias, error) {
+	content, err := os.ReadFile(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file '%s': %w", s.path, err)
+	}
+
+	hostAliases, err := hostsfile.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse file '%s': %w", s.path, err)
+	}
+
+	return hostAliases, nil
+}
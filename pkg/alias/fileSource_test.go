@@ -0,0 +1,54 @@
+package alias
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestFileSource_HostAliases(t *testing.T) {
+	t.Run("should return entries of the file", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "hosts")
+		require.NoError(t, os.WriteFile(path, []byte("# network team\n10.0.0.3 mail.local smtp.local\n"), 0600))
+		source := NewFileSource(path)
+
+		// when
+		aliases, err := source.HostAliases(context.TODO())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "file:"+path, source.Name())
+		assert.Equal(t, []v1.HostAlias{{IP: "10.0.0.3", Hostnames: []string{"mail.local", "smtp.local"}}}, aliases)
+	})
+	t.Run("should fail on missing file", func(t *testing.T) {
+		// given
+		source := NewFileSource(filepath.Join(t.TempDir(), "hosts"))
+
+		// when
+		_, err := source.HostAliases(context.TODO())
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to read file")
+	})
+	t.Run("should fail on invalid file", func(t *testing.T) {
+		// given
+		path := filepath.Join(t.TempDir(), "hosts")
+		require.NoError(t, os.WriteFile(path, []byte("mail.local 10.0.0.3\n"), 0600))
+		source := NewFileSource(path)
+
+		// when
+		_, err := source.HostAliases(context.TODO())
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "line 1: 'mail.local' is not a valid ip")
+	})
+}
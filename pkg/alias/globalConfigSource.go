@@ -0,0 +1,48 @@
+package alias
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// GlobalConfigSourceName identifies the global config in the origins of the generated host aliases.
+const GlobalConfigSourceName = "global-config"
+
+// globalConfigSource provides the host aliases configured by the internal ip, the fqdn and the additional hosts in
+// the global config.
+type globalConfigSource struct {
+	globalConfigGetter globalConfigGetter
+}
+
+// Name returns the name of the global config source.
+func (s *globalConfigSource) Name() string {
+	return GlobalConfigSourceName
+}
+
+// HostAliases returns the host aliases configured in the global config. The additional hosts are ordered by their
+// host names.
+func (s *globalConfigSource) HostAliases(ctx context.Context) ([]v1.HostAlias, error) {
+	cfg, err := s.getGeneratorConfig(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	return cfg.HostAliases(), nil
+}
+
+// getGeneratorConfig reads and validates the host keys of the global configuration.
+func (s *globalConfigSource) getGeneratorConfig(ctx context.Context) (*HostsConfig, error) {
+	globalCfg, err := s.globalConfigGetter.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get global config: %w", err)
+	}
+
+	hostsConfig, err := ParseHostsConfig(globalCfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return hostsConfig, hostsConfig.Validate()
+}
@@ -3,8 +3,6 @@ package alias
 import (
 	"context"
 	"fmt"
-	"github.com/cloudogu/k8s-registry-lib/config"
-	"net"
 	"strconv"
 	"strings"
 
@@ -16,132 +14,74 @@ const (
 	internalIPKey         = "k8s/internal_ip"
 	fqdnKey               = "fqdn"
 	additionalHostsPrefix = "containers/additional_hosts/"
+	// confirmAliasRemovalKey confirms that a host change may remove host aliases from the dogus.
+	confirmAliasRemovalKey = "k8s/confirm_alias_removal"
 )
 
-type generatorConfig struct {
-	fqdn            string
-	useInternalIP   bool
-	internalIP      net.IP
-	additionalHosts map[string]string
-}
-
 type HostAliasGenerator struct {
 	globalConfigGetter globalConfigGetter
+	sources            []Source
 }
 
 // NewHostAliasGenerator creates a generator with the ability to return host aliases from the configured internal ip, additional hosts and fqdn.
-func NewHostAliasGenerator(globalConfigGetter globalConfigGetter) *HostAliasGenerator {
+// The host aliases of the given sources are merged on top of the global config. Later sources take precedence over
+// earlier ones.
+func NewHostAliasGenerator(globalConfigGetter globalConfigGetter, sources ...Source) *HostAliasGenerator {
 	return &HostAliasGenerator{
 		globalConfigGetter: globalConfigGetter,
+		sources:            sources,
 	}
 }
 
 // Generate patches the given deployment with the host configuration provided.
 func (d *HostAliasGenerator) Generate(ctx context.Context) (hostAliases []v1.HostAlias, err error) {
-	cfg, err := d.getGeneratorConfig(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read config: %w", err)
-	}
-
-	if cfg.useInternalIP {
-		splitDnsHostAlias := v1.HostAlias{
-			IP:        cfg.internalIP.String(),
-			Hostnames: []string{cfg.fqdn},
-		}
-		hostAliases = append(hostAliases, splitDnsHostAlias)
-	}
-
-	for hostName, ip := range cfg.additionalHosts {
-		addHostAlias := v1.HostAlias{
-			IP:        ip,
-			Hostnames: []string{hostName},
-		}
-		hostAliases = append(hostAliases, addHostAlias)
-	}
+	hostAliases, _, err = d.GenerateWithOrigins(ctx)
+	return hostAliases, err
+}
 
-	return hostAliases, nil
+// GenerateWithOrigins works like Generate and additionally returns the origin of every generated host name.
+func (d *HostAliasGenerator) GenerateWithOrigins(ctx context.Context) ([]v1.HostAlias, []Origin, error) {
+	sources := append([]Source{&globalConfigSource{globalConfigGetter: d.globalConfigGetter}}, d.sources...)
+	return merge(ctx, sources)
 }
 
-// getGeneratorConfig reads hosts-specific keys from the global configuration and creates a generatorConfig object.
-func (d *HostAliasGenerator) getGeneratorConfig(ctx context.Context) (*generatorConfig, error) {
+// IsAliasRemovalConfirmed checks whether the removal of host aliases from the dogus is confirmed in the global configuration.
+// A missing key means that the removal is not confirmed.
+func (d *HostAliasGenerator) IsAliasRemovalConfirmed(ctx context.Context) (bool, error) {
 	globalCfg, err := d.globalConfigGetter.Get(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get global config: %w", err)
-	}
-
-	fqdn, err := d.getFQDN(globalCfg)
-	if err != nil {
-		return nil, err
-	}
-
-	hostsConfig := &generatorConfig{
-		fqdn: fqdn,
-	}
-
-	hostsConfig.useInternalIP, err = d.isInternalIPUsed(globalCfg)
-	if err != nil {
-		return nil, err
+		return false, fmt.Errorf("failed to get global config: %w", err)
 	}
 
-	if hostsConfig.useInternalIP {
-		hostsConfig.internalIP, err = d.getInternalIP(globalCfg)
-		if err != nil {
-			return nil, err
-		}
-	}
-
-	hostsConfig.additionalHosts = d.retrieveAdditionalHosts(globalCfg)
-
-	return hostsConfig, nil
-}
-
-func (d *HostAliasGenerator) getFQDN(globalCfg config.GlobalConfig) (string, error) {
-	fqdn, ok := globalCfg.Get(fqdnKey)
-	if !ok {
-		return "", fmt.Errorf("key: %s does not exist in global config", fqdnKey)
-	}
-
-	return fqdn.String(), nil
-}
-
-func (d *HostAliasGenerator) isInternalIPUsed(globalCfg config.GlobalConfig) (useInternalIP bool, err error) {
-	useInternalIPRaw, ok := globalCfg.Get(useInternalIPKey)
+	confirmedRaw, ok := globalCfg.Get(confirmAliasRemovalKey)
 	if !ok {
-		return false, fmt.Errorf("key: %s does not exist in global config", useInternalIPKey)
+		return false, nil
 	}
 
-	useInternalIP, err = strconv.ParseBool(useInternalIPRaw.String())
+	confirmed, err := strconv.ParseBool(confirmedRaw.String())
 	if err != nil {
-		return false, fmt.Errorf("failed to parse value '%s' of field '%s' in global config: %w", useInternalIPRaw, useInternalIPKey, err)
+		return false, fmt.Errorf("failed to parse value '%s' of field '%s' in global config: %w", confirmedRaw, confirmAliasRemovalKey, err)
 	}
 
-	return useInternalIP, nil
+	return confirmed, nil
 }
 
-func (d *HostAliasGenerator) getInternalIP(globalCfg config.GlobalConfig) (net.IP, error) {
-	internalIPRaw, ok := globalCfg.Get(internalIPKey)
-	if !ok {
-		return nil, fmt.Errorf("key: %s does not exist in global config", internalIPKey)
-	}
-
-	ip := net.ParseIP(internalIPRaw.String())
-	if ip == nil {
-		return nil, fmt.Errorf("failed to parse value '%s' of field '%s' in global config: not a valid ip", internalIPRaw, internalIPKey)
+// GlobalConfigValues returns the values of all global config keys the host aliases are generated from.
+// Keys which are not set are omitted.
+func (d *HostAliasGenerator) GlobalConfigValues(ctx context.Context) (map[string]string, error) {
+	globalCfg, err := d.globalConfigGetter.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get global config: %w", err)
 	}
 
-	return ip, nil
-}
-
-func (d *HostAliasGenerator) retrieveAdditionalHosts(globalCfg config.GlobalConfig) map[string]string {
-	globalCfgEntries := globalCfg.GetAll()
-
-	additionalHosts := map[string]string{}
-	for key, value := range globalCfgEntries {
-		if strings.HasPrefix(key.String(), additionalHostsPrefix) {
-			hostName := strings.TrimPrefix(key.String(), additionalHostsPrefix)
-			additionalHosts[hostName] = value.String()
+	values := map[string]string{}
+	for key, value := range globalCfg.GetAll() {
+		switch {
+		case key.String() == fqdnKey, key.String() == useInternalIPKey, key.String() == internalIPKey,
+			key.String() == confirmAliasRemovalKey, strings.HasPrefix(key.String(), additionalHostsPrefix):
+			values[key.String()] = value.String()
 		}
 	}
 
-	return additionalHosts
+	return values, nil
 }
@@ -19,15 +19,15 @@ func Test_hostAliasGenerator_Generate(t *testing.T) {
 		fqdn := "ecosystem.cloudogu.com"
 		internalIP := "23.24.12.99"
 
-		additionalHostOne := "prod.cloudogu.com"
+		additionalHostOne := "11.11.11.11"
 		additionalHostTwo := "11.11.11.22"
 
 		entries := config.Entries{
 			"fqdn":                                 config.Value(fqdn),
 			"k8s/use_internal_ip":                  config.Value("true"),
 			"k8s/internal_ip":                      config.Value(internalIP),
-			"containers/additional_hosts/host_one": config.Value(additionalHostOne),
-			"containers/additional_hosts/host_two": config.Value(additionalHostTwo),
+			"containers/additional_hosts/host-one": config.Value(additionalHostOne),
+			"containers/additional_hosts/host-two": config.Value(additionalHostTwo),
 		}
 
 		globalConfigRepoMock := newMockGlobalConfigGetter(t)
@@ -38,8 +38,8 @@ func Test_hostAliasGenerator_Generate(t *testing.T) {
 		}
 
 		aliasFqdn := v1.HostAlias{IP: internalIP, Hostnames: []string{fqdn}}
-		aliasOne := v1.HostAlias{IP: additionalHostOne, Hostnames: []string{"host_one"}}
-		aliasTwo := v1.HostAlias{IP: additionalHostTwo, Hostnames: []string{"host_two"}}
+		aliasOne := v1.HostAlias{IP: additionalHostOne, Hostnames: []string{"host-one"}}
+		aliasTwo := v1.HostAlias{IP: additionalHostTwo, Hostnames: []string{"host-two"}}
 
 		// when
 		aliases, err := generator.Generate(context.TODO())
@@ -56,14 +56,14 @@ func Test_hostAliasGenerator_Generate(t *testing.T) {
 		// given
 		fqdn := "ecosystem.cloudogu.com"
 
-		additionalHostOne := "prod.cloudogu.com"
+		additionalHostOne := "11.11.11.11"
 		additionalHostTwo := "11.11.11.22"
 
 		entries := config.Entries{
 			"fqdn":                                 config.Value(fqdn),
 			"k8s/use_internal_ip":                  config.Value("false"),
-			"containers/additional_hosts/host_one": config.Value(additionalHostOne),
-			"containers/additional_hosts/host_two": config.Value(additionalHostTwo),
+			"containers/additional_hosts/host-one": config.Value(additionalHostOne),
+			"containers/additional_hosts/host-two": config.Value(additionalHostTwo),
 		}
 
 		globalConfigRepoMock := newMockGlobalConfigGetter(t)
@@ -73,8 +73,8 @@ func Test_hostAliasGenerator_Generate(t *testing.T) {
 			globalConfigGetter: globalConfigRepoMock,
 		}
 
-		aliasOne := v1.HostAlias{IP: additionalHostOne, Hostnames: []string{"host_one"}}
-		aliasTwo := v1.HostAlias{IP: additionalHostTwo, Hostnames: []string{"host_two"}}
+		aliasOne := v1.HostAlias{IP: additionalHostOne, Hostnames: []string{"host-one"}}
+		aliasTwo := v1.HostAlias{IP: additionalHostTwo, Hostnames: []string{"host-two"}}
 
 		// when
 		aliases, err := generator.Generate(context.TODO())
@@ -86,9 +86,12 @@ func Test_hostAliasGenerator_Generate(t *testing.T) {
 		assert.True(t, hasAlias(aliases, aliasTwo))
 	})
 
-	t.Run("should fail on query fqdn error ", func(t *testing.T) {
+	t.Run("should fail on missing fqdn if internalIP is used", func(t *testing.T) {
 		// given
-		entries := config.Entries{}
+		entries := config.Entries{
+			"k8s/use_internal_ip": config.Value("true"),
+			"k8s/internal_ip":     config.Value("23.24.12.99"),
+		}
 
 		globalConfigRepoMock := newMockGlobalConfigGetter(t)
 		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(entries), nil)
@@ -105,12 +108,13 @@ func Test_hostAliasGenerator_Generate(t *testing.T) {
 		assert.ErrorContains(t, err, "fqdn does not exist in global config")
 	})
 
-	t.Run("should fail on query internalIP flag error", func(t *testing.T) {
+	t.Run("should not use the internalIP if the flag is missing", func(t *testing.T) {
 		// given
 		fqdn := "ecosystem.cloudogu.com"
 
 		entries := config.Entries{
-			"fqdn": config.Value(fqdn),
+			"fqdn":            config.Value(fqdn),
+			"k8s/internal_ip": config.Value("23.24.12.99"),
 		}
 
 		globalConfigRepoMock := newMockGlobalConfigGetter(t)
@@ -121,11 +125,11 @@ func Test_hostAliasGenerator_Generate(t *testing.T) {
 		}
 
 		// when
-		_, err := generator.Generate(context.TODO())
+		aliases, err := generator.Generate(context.TODO())
 
 		// then
-		require.Error(t, err)
-		assert.ErrorContains(t, err, "k8s/use_internal_ip does not exist in global config")
+		require.NoError(t, err)
+		assert.Empty(t, aliases)
 	})
 
 	t.Run("should fail on parse internalIP flag error", func(t *testing.T) {
@@ -236,3 +240,97 @@ func TestNewHostAliasGenerator(t *testing.T) {
 	// then
 	require.NotNil(t, generator)
 }
+
+func TestHostAliasGenerator_IsAliasRemovalConfirmed(t *testing.T) {
+	tests := []struct {
+		name    string
+		entries config.Entries
+		want    bool
+		wantErr string
+	}{
+		{name: "should not be confirmed without key", entries: config.Entries{}, want: false},
+		{name: "should be confirmed", entries: config.Entries{"k8s/confirm_alias_removal": "true"}, want: true},
+		{name: "should not be confirmed", entries: config.Entries{"k8s/confirm_alias_removal": "false"}, want: false},
+		{
+			name:    "should fail on invalid value",
+			entries: config.Entries{"k8s/confirm_alias_removal": "yes please"},
+			wantErr: "failed to parse value 'yes please' of field 'k8s/confirm_alias_removal' in global config",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			globalConfigRepoMock := newMockGlobalConfigGetter(t)
+			globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(tt.entries), nil)
+			generator := HostAliasGenerator{globalConfigGetter: globalConfigRepoMock}
+
+			// when
+			confirmed, err := generator.IsAliasRemovalConfirmed(context.TODO())
+
+			// then
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, confirmed)
+		})
+	}
+
+	t.Run("should fail on query global config", func(t *testing.T) {
+		// given
+		globalConfigRepoMock := newMockGlobalConfigGetter(t)
+		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(config.GlobalConfig{}, assert.AnError)
+		generator := HostAliasGenerator{globalConfigGetter: globalConfigRepoMock}
+
+		// when
+		_, err := generator.IsAliasRemovalConfirmed(context.TODO())
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to get global config")
+	})
+}
+
+func TestHostAliasGenerator_GlobalConfigValues(t *testing.T) {
+	t.Run("should return host related values", func(t *testing.T) {
+		// given
+		entries := config.Entries{
+			"fqdn":                                 "ces.example.com",
+			"k8s/use_internal_ip":                  "true",
+			"k8s/internal_ip":                      "10.0.0.1",
+			"containers/additional_hosts/host-one": "10.0.0.2",
+			"admin_group":                          "cesAdmin",
+		}
+		globalConfigRepoMock := newMockGlobalConfigGetter(t)
+		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(entries), nil)
+		generator := HostAliasGenerator{globalConfigGetter: globalConfigRepoMock}
+
+		// when
+		values, err := generator.GlobalConfigValues(context.TODO())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{
+			"fqdn":                                 "ces.example.com",
+			"k8s/use_internal_ip":                  "true",
+			"k8s/internal_ip":                      "10.0.0.1",
+			"containers/additional_hosts/host-one": "10.0.0.2",
+		}, values)
+	})
+	t.Run("should fail on query global config", func(t *testing.T) {
+		// given
+		globalConfigRepoMock := newMockGlobalConfigGetter(t)
+		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(config.GlobalConfig{}, assert.AnError)
+		generator := HostAliasGenerator{globalConfigGetter: globalConfigRepoMock}
+
+		// when
+		_, err := generator.GlobalConfigValues(context.TODO())
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
@@ -0,0 +1,137 @@
+package alias
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	"github.com/cloudogu/k8s-registry-lib/config"
+	v1 "k8s.io/api/core/v1"
+)
+
+// HostsConfig is the typed form of the global config keys the host aliases are generated from.
+type HostsConfig struct {
+	// FQDN is the fully qualified domain name of the ecosystem (key fqdn).
+	FQDN string
+	// UseInternalIP resolves the FQDN to InternalIP in all dogus (key k8s/use_internal_ip). It defaults to false.
+	UseInternalIP bool
+	// InternalIP is the ip the FQDN is resolved to (key k8s/internal_ip). It may be set while UseInternalIP is false. An
+	// invalid value is ignored as long as UseInternalIP is false.
+	InternalIP net.IP
+	// AdditionalHosts maps host names to ips (keys below containers/additional_hosts/).
+	AdditionalHosts map[string]string
+}
+
+// ParseHostsConfig reads the host keys from the given global config. Missing keys keep their zero value, so that a
+// fresh installation without k8s/use_internal_ip does not use an internal ip. It fails on values which cannot be
+// parsed, except for a leftover k8s/internal_ip while the internal ip is not used. ParseHostsConfig does not validate
+// the combination of the values, see Validate.
+func ParseHostsConfig(globalCfg config.GlobalConfig) (*HostsConfig, error) {
+	hostsConfig := &HostsConfig{AdditionalHosts: map[string]string{}}
+
+	fqdn, ok := globalCfg.Get(fqdnKey)
+	if ok {
+		hostsConfig.FQDN = fqdn.String()
+	}
+
+	useInternalIPRaw, ok := globalCfg.Get(useInternalIPKey)
+	if ok {
+		useInternalIP, err := strconv.ParseBool(useInternalIPRaw.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse value '%s' of field '%s' in global config: %w", useInternalIPRaw, useInternalIPKey, err)
+		}
+		hostsConfig.UseInternalIP = useInternalIP
+	}
+
+	internalIPRaw, ok := globalCfg.Get(internalIPKey)
+	if ok {
+		hostsConfig.InternalIP = net.ParseIP(internalIPRaw.String())
+		if hostsConfig.InternalIP == nil && hostsConfig.UseInternalIP {
+			return nil, fmt.Errorf("failed to parse value '%s' of field '%s' in global config: not a valid ip", internalIPRaw, internalIPKey)
+		}
+	}
+
+	for key, value := range globalCfg.GetAll() {
+		hostName, isAdditionalHost := strings.CutPrefix(key.String(), additionalHostsPrefix)
+		if isAdditionalHost {
+			hostsConfig.AdditionalHosts[hostName] = value.String()
+		}
+	}
+
+	return hostsConfig, nil
+}
+
+// HostEntries returns the entries of the given global config which ParseHostsConfig reads.
+func HostEntries(globalCfg config.GlobalConfig) config.Entries {
+	entries := config.Entries{}
+	for key, value := range globalCfg.GetAll() {
+		switch {
+		case key == fqdnKey, key == useInternalIPKey, key == internalIPKey, strings.HasPrefix(key.String(), additionalHostsPrefix):
+			entries[key] = value
+		}
+	}
+
+	return entries
+}
+
+// Validate checks that the host aliases can be generated from the config: the internal ip requires the FQDN and the
+// ip, and the additional hosts need valid host names and ips.
+func (c *HostsConfig) Validate() error {
+	if c.UseInternalIP {
+		if c.FQDN == "" {
+			return fmt.Errorf("key: %s does not exist in global config", fqdnKey)
+		}
+		if c.InternalIP == nil {
+			return fmt.Errorf("key: %s does not exist in global config", internalIPKey)
+		}
+	}
+
+	for _, hostName := range sortedKeys(c.AdditionalHosts) {
+		err := validateHostName(hostName)
+		if err != nil {
+			return fmt.Errorf("invalid key '%s%s' in global config: %w", additionalHostsPrefix, hostName, err)
+		}
+
+		ip := c.AdditionalHosts[hostName]
+		if net.ParseIP(ip) == nil {
+			return fmt.Errorf("failed to parse value '%s' of field '%s%s' in global config: not a valid ip", ip, additionalHostsPrefix, hostName)
+		}
+	}
+
+	return nil
+}
+
+// HostAliases returns the host aliases of the config: the FQDN pointing to the internal ip if it is used, followed by
+// the additional hosts ordered by their host names.
+func (c *HostsConfig) HostAliases() []v1.HostAlias {
+	var hostAliases []v1.HostAlias
+	if c.UseInternalIP {
+		hostAliases = append(hostAliases, v1.HostAlias{IP: c.InternalIP.String(), Hostnames: []string{c.FQDN}})
+	}
+
+	for _, hostName := range sortedKeys(c.AdditionalHosts) {
+		hostAliases = append(hostAliases, v1.HostAlias{IP: c.AdditionalHosts[hostName], Hostnames: []string{hostName}})
+	}
+
+	return hostAliases
+}
+
+// Entries serializes the config into global config entries. Empty values are omitted; k8s/use_internal_ip is always
+// written.
+func (c *HostsConfig) Entries() config.Entries {
+	entries := config.Entries{
+		useInternalIPKey: config.Value(strconv.FormatBool(c.UseInternalIP)),
+	}
+	if c.FQDN != "" {
+		entries[fqdnKey] = config.Value(c.FQDN)
+	}
+	if c.InternalIP != nil {
+		entries[internalIPKey] = config.Value(c.InternalIP.String())
+	}
+	for hostName, ip := range c.AdditionalHosts {
+		entries[config.Key(additionalHostsPrefix+hostName)] = config.Value(ip)
+	}
+
+	return entries
+}
@@ -0,0 +1,159 @@
+package alias
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	cesErrors "github.com/cloudogu/ces-commons-lib/errors"
+	"github.com/cloudogu/k8s-registry-lib/config"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/client-go/util/retry"
+)
+
+// AdditionalHost maps a host name to an ip in the global config.
+type AdditionalHost struct {
+	Hostname string `json:"hostname"`
+	IP       string `json:"ip"`
+}
+
+// HostsConfigWriter changes the internal ip and the additional hosts in the global config. Every change is validated
+// before it is written and retried if another writer changed the global config in the meantime.
+type HostsConfigWriter struct {
+	globalConfigRepository globalConfigRepository
+}
+
+// NewHostsConfigWriter creates a writer which writes through the given global config repository.
+func NewHostsConfigWriter(globalConfigRepository globalConfigRepository) *HostsConfigWriter {
+	return &HostsConfigWriter{globalConfigRepository: globalConfigRepository}
+}
+
+// SetInternalIP enables the internal ip and sets it to the given ip.
+func (w *HostsConfigWriter) SetInternalIP(ctx context.Context, ip string) error {
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return fmt.Errorf("'%s' is not a valid ip", ip)
+	}
+
+	return updateGlobalConfig(ctx, w.globalConfigRepository, func(cfg config.Config) (config.Config, bool, error) {
+		cfg, err := cfg.Set(useInternalIPKey, config.Value(strconv.FormatBool(true)))
+		if err != nil {
+			return cfg, false, fmt.Errorf("failed to set key '%s' in global config: %w", useInternalIPKey, err)
+		}
+
+		cfg, err = cfg.Set(internalIPKey, config.Value(parsed.String()))
+		if err != nil {
+			return cfg, false, fmt.Errorf("failed to set key '%s' in global config: %w", internalIPKey, err)
+		}
+
+		return cfg, true, nil
+	})
+}
+
+// DisableInternalIP disables the internal ip. The ip is kept so that it can be enabled again.
+func (w *HostsConfigWriter) DisableInternalIP(ctx context.Context) error {
+	return updateGlobalConfig(ctx, w.globalConfigRepository, func(cfg config.Config) (config.Config, bool, error) {
+		cfg, err := cfg.Set(useInternalIPKey, config.Value(strconv.FormatBool(false)))
+		if err != nil {
+			return cfg, false, fmt.Errorf("failed to set key '%s' in global config: %w", useInternalIPKey, err)
+		}
+
+		return cfg, true, nil
+	})
+}
+
+// AddHost maps the given host name to the given ip. An existing additional host with the same host name is
+// overwritten.
+func (w *HostsConfigWriter) AddHost(ctx context.Context, hostName string, ip string) error {
+	err := validateHostName(hostName)
+	if err != nil {
+		return err
+	}
+
+	parsed := net.ParseIP(ip)
+	if parsed == nil {
+		return fmt.Errorf("'%s' is not a valid ip", ip)
+	}
+
+	key := config.Key(additionalHostsPrefix + hostName)
+	return updateGlobalConfig(ctx, w.globalConfigRepository, func(cfg config.Config) (config.Config, bool, error) {
+		cfg, err := cfg.Set(key, config.Value(parsed.String()))
+		if err != nil {
+			return cfg, false, fmt.Errorf("failed to set key '%s' in global config: %w", key, err)
+		}
+
+		return cfg, true, nil
+	})
+}
+
+// RemoveHost removes the additional host with the given host name. It fails if the host does not exist.
+func (w *HostsConfigWriter) RemoveHost(ctx context.Context, hostName string) error {
+	key := config.Key(additionalHostsPrefix + hostName)
+	return updateGlobalConfig(ctx, w.globalConfigRepository, func(cfg config.Config) (config.Config, bool, error) {
+		_, found := cfg.Get(key)
+		if !found {
+			return cfg, false, fmt.Errorf("additional host '%s' does not exist", hostName)
+		}
+
+		return cfg.Delete(key), true, nil
+	})
+}
+
+// ListHosts returns the additional hosts of the global config ordered by their host names.
+func (w *HostsConfigWriter) ListHosts(ctx context.Context) ([]AdditionalHost, error) {
+	globalCfg, err := w.globalConfigRepository.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get global config: %w", err)
+	}
+
+	hosts := map[string]string{}
+	for key, value := range globalCfg.GetAll() {
+		hostName, isAdditionalHost := strings.CutPrefix(key.String(), additionalHostsPrefix)
+		if isAdditionalHost {
+			hosts[hostName] = value.String()
+		}
+	}
+
+	additionalHosts := make([]AdditionalHost, 0, len(hosts))
+	for _, hostName := range sortedKeys(hosts) {
+		additionalHosts = append(additionalHosts, AdditionalHost{Hostname: hostName, IP: hosts[hostName]})
+	}
+
+	return additionalHosts, nil
+}
+
+// updateGlobalConfig reads the global config, applies the given change and writes the result. If the update conflicts
+// with another writer, the change is applied again to the current global config. Nothing is written if the change
+// reports no modification.
+func updateGlobalConfig(ctx context.Context, repo globalConfigRepository, change func(cfg config.Config) (config.Config, bool, error)) error {
+	return retry.OnError(retry.DefaultRetry, cesErrors.IsConflictError, func() error {
+		globalCfg, err := repo.Get(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to get global config: %w", err)
+		}
+
+		cfg, modified, err := change(globalCfg.Config)
+		if err != nil || !modified {
+			return err
+		}
+
+		_, err = repo.Update(ctx, config.GlobalConfig{Config: cfg})
+		if err != nil {
+			return fmt.Errorf("failed to update global config: %w", err)
+		}
+
+		return nil
+	})
+}
+
+// validateHostName checks that the given host name can be used as host alias and as key of the global config.
+func validateHostName(hostName string) error {
+	errs := validation.IsDNS1123Subdomain(hostName)
+	if len(errs) > 0 {
+		return fmt.Errorf("invalid host name '%s': %s", hostName, strings.Join(errs, ", "))
+	}
+
+	return nil
+}
@@ -0,0 +1,179 @@
+package alias
+
+import (
+	"context"
+	"testing"
+
+	cesErrors "github.com/cloudogu/ces-commons-lib/errors"
+	"github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHostsConfigWriter_SetInternalIP(t *testing.T) {
+	t.Run("should enable and set internal ip", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		repoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(config.Entries{"k8s/use_internal_ip": "false"}), nil)
+		repoMock.EXPECT().Update(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, cfg config.GlobalConfig) (config.GlobalConfig, error) {
+			assert.Equal(t, config.Entries{"k8s/use_internal_ip": "true", "k8s/internal_ip": "fd00::1"}, cfg.GetAll())
+			return cfg, nil
+		})
+
+		// when
+		err := NewHostsConfigWriter(repoMock).SetInternalIP(context.TODO(), "fd00:0::1")
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should retry on conflict", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		repoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(config.Entries{}), nil).Twice()
+		repoMock.EXPECT().Update(mock.Anything, mock.Anything).Return(config.GlobalConfig{}, cesErrors.NewConflictError(assert.AnError)).Once()
+		repoMock.EXPECT().Update(mock.Anything, mock.Anything).Return(config.GlobalConfig{}, nil).Once()
+
+		// when
+		err := NewHostsConfigWriter(repoMock).SetInternalIP(context.TODO(), "10.0.0.1")
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should not retry on other errors", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		repoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(config.Entries{}), nil).Once()
+		repoMock.EXPECT().Update(mock.Anything, mock.Anything).Return(config.GlobalConfig{}, assert.AnError).Once()
+
+		// when
+		err := NewHostsConfigWriter(repoMock).SetInternalIP(context.TODO(), "10.0.0.1")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to update global config")
+	})
+	t.Run("should fail on invalid ip", func(t *testing.T) {
+		// when
+		err := NewHostsConfigWriter(newMockGlobalConfigRepository(t)).SetInternalIP(context.TODO(), "10.0.0")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "'10.0.0' is not a valid ip")
+	})
+}
+
+func TestHostsConfigWriter_DisableInternalIP(t *testing.T) {
+	t.Run("should disable internal ip and keep the ip", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		repoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(config.Entries{"k8s/use_internal_ip": "true", "k8s/internal_ip": "10.0.0.1"}), nil)
+		repoMock.EXPECT().Update(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, cfg config.GlobalConfig) (config.GlobalConfig, error) {
+			assert.Equal(t, config.Entries{"k8s/use_internal_ip": "false", "k8s/internal_ip": "10.0.0.1"}, cfg.GetAll())
+			return cfg, nil
+		})
+
+		// when
+		err := NewHostsConfigWriter(repoMock).DisableInternalIP(context.TODO())
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should fail to get global config", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		repoMock.EXPECT().Get(mock.Anything).Return(config.GlobalConfig{}, assert.AnError)
+
+		// when
+		err := NewHostsConfigWriter(repoMock).DisableInternalIP(context.TODO())
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to get global config")
+	})
+}
+
+func TestHostsConfigWriter_AddHost(t *testing.T) {
+	t.Run("should set additional host", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		repoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(config.Entries{}), nil)
+		repoMock.EXPECT().Update(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, cfg config.GlobalConfig) (config.GlobalConfig, error) {
+			assert.Equal(t, config.Entries{"containers/additional_hosts/git.local": "10.0.0.2"}, cfg.GetAll())
+			return cfg, nil
+		})
+
+		// when
+		err := NewHostsConfigWriter(repoMock).AddHost(context.TODO(), "git.local", "10.0.0.2")
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should fail on invalid host name", func(t *testing.T) {
+		// when
+		err := NewHostsConfigWriter(newMockGlobalConfigRepository(t)).AddHost(context.TODO(), "Git_Local", "10.0.0.2")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid host name 'Git_Local'")
+	})
+	t.Run("should fail on invalid ip", func(t *testing.T) {
+		// when
+		err := NewHostsConfigWriter(newMockGlobalConfigRepository(t)).AddHost(context.TODO(), "git.local", "git")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "'git' is not a valid ip")
+	})
+}
+
+func TestHostsConfigWriter_RemoveHost(t *testing.T) {
+	t.Run("should remove additional host", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		repoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(config.Entries{"containers/additional_hosts/git.local": "10.0.0.2"}), nil)
+		repoMock.EXPECT().Update(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, cfg config.GlobalConfig) (config.GlobalConfig, error) {
+			assert.Empty(t, cfg.GetAll())
+			return cfg, nil
+		})
+
+		// when
+		err := NewHostsConfigWriter(repoMock).RemoveHost(context.TODO(), "git.local")
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should fail on unknown host", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		repoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(config.Entries{}), nil)
+
+		// when
+		err := NewHostsConfigWriter(repoMock).RemoveHost(context.TODO(), "git.local")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "additional host 'git.local' does not exist")
+	})
+}
+
+func TestHostsConfigWriter_ListHosts(t *testing.T) {
+	t.Run("should return additional hosts ordered by host name", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		repoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(config.Entries{
+			"fqdn":                                   "ces.example.com",
+			"containers/additional_hosts/mail.local": "10.0.0.3",
+			"containers/additional_hosts/git.local":  "10.0.0.2",
+		}), nil)
+
+		// when
+		hosts, err := NewHostsConfigWriter(repoMock).ListHosts(context.TODO())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []AdditionalHost{{Hostname: "git.local", IP: "10.0.0.2"}, {Hostname: "mail.local", IP: "10.0.0.3"}}, hosts)
+	})
+}
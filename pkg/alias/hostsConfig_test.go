@@ -0,0 +1,209 @@
+package alias
+
+import (
+	"net"
+	"testing"
+
+	"github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestParseHostsConfig(t *testing.T) {
+	t.Run("should parse all host keys", func(t *testing.T) {
+		// given
+		globalCfg := config.CreateGlobalConfig(config.Entries{
+			"fqdn":                                 "ces.example.com",
+			"k8s/use_internal_ip":                  "true",
+			"k8s/internal_ip":                      "10.0.0.1",
+			"containers/additional_hosts/host-one": "10.0.0.2",
+			"admin_group":                          "admins",
+		})
+
+		// when
+		actual, err := ParseHostsConfig(globalCfg)
+
+		// then
+		require.NoError(t, err)
+		expected := &HostsConfig{
+			FQDN:            "ces.example.com",
+			UseInternalIP:   true,
+			InternalIP:      net.ParseIP("10.0.0.1"),
+			AdditionalHosts: map[string]string{"host-one": "10.0.0.2"},
+		}
+		assert.Equal(t, expected, actual)
+	})
+	t.Run("should use defaults for missing keys", func(t *testing.T) {
+		// when
+		actual, err := ParseHostsConfig(config.CreateGlobalConfig(config.Entries{}))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &HostsConfig{AdditionalHosts: map[string]string{}}, actual)
+	})
+	t.Run("should fail on invalid internal ip flag", func(t *testing.T) {
+		// when
+		_, err := ParseHostsConfig(config.CreateGlobalConfig(config.Entries{"k8s/use_internal_ip": "yes please"}))
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to parse value 'yes please' of field 'k8s/use_internal_ip' in global config")
+	})
+	t.Run("should fail on invalid internal ip", func(t *testing.T) {
+		// when
+		_, err := ParseHostsConfig(config.CreateGlobalConfig(config.Entries{"k8s/use_internal_ip": "true", "k8s/internal_ip": "10.0.0"}))
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to parse value '10.0.0' of field 'k8s/internal_ip' in global config: not a valid ip")
+	})
+	t.Run("should ignore invalid internal ip if it is not used", func(t *testing.T) {
+		// when
+		actual, err := ParseHostsConfig(config.CreateGlobalConfig(config.Entries{"k8s/use_internal_ip": "false", "k8s/internal_ip": "garbage"}))
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, actual.UseInternalIP)
+		assert.Nil(t, actual.InternalIP)
+		assert.NoError(t, actual.Validate())
+	})
+	t.Run("should ignore invalid internal ip without internal ip flag", func(t *testing.T) {
+		// when
+		actual, err := ParseHostsConfig(config.CreateGlobalConfig(config.Entries{"k8s/internal_ip": "garbage"}))
+
+		// then
+		require.NoError(t, err)
+		assert.Nil(t, actual.InternalIP)
+	})
+}
+
+func TestHostEntries(t *testing.T) {
+	t.Run("should only return host keys", func(t *testing.T) {
+		// given
+		globalCfg := config.CreateGlobalConfig(config.Entries{
+			"fqdn":                                 "ces.example.com",
+			"k8s/use_internal_ip":                  "true",
+			"k8s/internal_ip":                      "10.0.0.1",
+			"containers/additional_hosts/host-one": "10.0.0.2",
+			"admin_group":                          "admins",
+			"k8s/confirm_alias_removal":            "true",
+		})
+
+		// when
+		actual := HostEntries(globalCfg)
+
+		// then
+		assert.Equal(t, config.Entries{
+			"fqdn":                                 "ces.example.com",
+			"k8s/use_internal_ip":                  "true",
+			"k8s/internal_ip":                      "10.0.0.1",
+			"containers/additional_hosts/host-one": "10.0.0.2",
+		}, actual)
+	})
+}
+
+func TestHostsConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name        string
+		hostsConfig HostsConfig
+		wantErr     string
+	}{
+		{name: "should accept empty config", hostsConfig: HostsConfig{}},
+		{name: "should accept missing internal ip if it is not used", hostsConfig: HostsConfig{FQDN: "ces.example.com"}},
+		{
+			name:        "should require fqdn if internal ip is used",
+			hostsConfig: HostsConfig{UseInternalIP: true, InternalIP: net.ParseIP("10.0.0.1")},
+			wantErr:     "key: fqdn does not exist in global config",
+		},
+		{
+			name:        "should require internal ip if it is used",
+			hostsConfig: HostsConfig{FQDN: "ces.example.com", UseInternalIP: true},
+			wantErr:     "key: k8s/internal_ip does not exist in global config",
+		},
+		{
+			name:        "should reject invalid host name",
+			hostsConfig: HostsConfig{AdditionalHosts: map[string]string{"host_one": "10.0.0.2"}},
+			wantErr:     "invalid key 'containers/additional_hosts/host_one' in global config",
+		},
+		{
+			name:        "should reject invalid ip of additional host",
+			hostsConfig: HostsConfig{AdditionalHosts: map[string]string{"host-one": "prod.example.com"}},
+			wantErr:     "failed to parse value 'prod.example.com' of field 'containers/additional_hosts/host-one' in global config: not a valid ip",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// when
+			err := tt.hostsConfig.Validate()
+
+			// then
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func TestHostsConfig_HostAliases(t *testing.T) {
+	t.Run("should return fqdn and sorted additional hosts", func(t *testing.T) {
+		// given
+		hostsConfig := HostsConfig{
+			FQDN:            "ces.example.com",
+			UseInternalIP:   true,
+			InternalIP:      net.ParseIP("10.0.0.1"),
+			AdditionalHosts: map[string]string{"host-two": "10.0.0.3", "host-one": "10.0.0.2"},
+		}
+
+		// when
+		actual := hostsConfig.HostAliases()
+
+		// then
+		expected := []v1.HostAlias{
+			{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}},
+			{IP: "10.0.0.2", Hostnames: []string{"host-one"}},
+			{IP: "10.0.0.3", Hostnames: []string{"host-two"}},
+		}
+		assert.Equal(t, expected, actual)
+	})
+	t.Run("should omit fqdn if internal ip is not used", func(t *testing.T) {
+		// given
+		hostsConfig := HostsConfig{FQDN: "ces.example.com", InternalIP: net.ParseIP("10.0.0.1")}
+
+		// when
+		actual := hostsConfig.HostAliases()
+
+		// then
+		assert.Empty(t, actual)
+	})
+}
+
+func TestHostsConfig_Entries(t *testing.T) {
+	t.Run("should round trip through the global config", func(t *testing.T) {
+		// given
+		entries := config.Entries{
+			"fqdn":                                 "ces.example.com",
+			"k8s/use_internal_ip":                  "true",
+			"k8s/internal_ip":                      "fd00::1",
+			"containers/additional_hosts/host-one": "10.0.0.2",
+		}
+		hostsConfig, err := ParseHostsConfig(config.CreateGlobalConfig(entries))
+		require.NoError(t, err)
+
+		// when
+		actual := hostsConfig.Entries()
+
+		// then
+		assert.Equal(t, entries, actual)
+	})
+	t.Run("should write internal ip flag for empty config", func(t *testing.T) {
+		// when
+		actual := (&HostsConfig{}).Entries()
+
+		// then
+		assert.Equal(t, config.Entries{"k8s/use_internal_ip": "false"}, actual)
+	})
+}
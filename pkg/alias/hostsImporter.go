@@ -0,0 +1,143 @@
+package alias
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/cloudogu/k8s-registry-lib/config"
+	v1 "k8s.io/api/core/v1"
+)
+
+// ImportOptions configures how host aliases are imported into the global config.
+type ImportOptions struct {
+	// Replace removes all additional hosts which are not imported.
+	Replace bool
+	// DryRun computes the changes without writing the global config.
+	DryRun bool
+}
+
+// ImportResult lists the host names whose additional hosts an import added, changed, removed or left unchanged. Skipped
+// lists the host names which are not imported because they are the FQDN of the ecosystem.
+type ImportResult struct {
+	Added     []string `json:"added"`
+	Changed   []string `json:"changed"`
+	Removed   []string `json:"removed"`
+	Unchanged []string `json:"unchanged"`
+	Skipped   []string `json:"skipped,omitempty"`
+}
+
+// HostsImporter writes host aliases as additional hosts into the global config.
+type HostsImporter struct {
+	globalConfigRepository globalConfigRepository
+}
+
+// NewHostsImporter creates an importer which writes through the given global config repository.
+func NewHostsImporter(globalConfigRepository globalConfigRepository) *HostsImporter {
+	return &HostsImporter{globalConfigRepository: globalConfigRepository}
+}
+
+// Import writes every host name of the given host aliases as key below containers/additional_hosts/ with its ip as
+// value. It fails without changing anything if a host name is invalid or mapped to different ips. The FQDN of the
+// ecosystem is skipped because it is generated from the internal ip, e.g. in the output of export --hosts-file.
+func (i *HostsImporter) Import(ctx context.Context, hostAliases []v1.HostAlias, opts ImportOptions) (*ImportResult, error) {
+	hosts, err := additionalHosts(hostAliases)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *ImportResult
+	err = updateGlobalConfig(ctx, i.globalConfigRepository, func(cfg config.Config) (config.Config, bool, error) {
+		result = &ImportResult{}
+		cfg, err := importHosts(cfg, hosts, opts.Replace, result)
+		if err != nil {
+			return cfg, false, err
+		}
+
+		modified := len(result.Added)+len(result.Changed)+len(result.Removed) > 0
+		return cfg, modified && !opts.DryRun, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// importHosts sets the given hosts except the FQDN in the global config and records the changes in the given result.
+// With replace, additional hosts which are not given are removed.
+func importHosts(cfg config.Config, hosts map[string]string, replace bool, result *ImportResult) (config.Config, error) {
+	var err error
+	fqdn, _ := cfg.Get(fqdnKey)
+	for _, hostName := range sortedKeys(hosts) {
+		if hostName == fqdn.String() {
+			result.Skipped = append(result.Skipped, hostName)
+			continue
+		}
+
+		key := config.Key(additionalHostsPrefix + hostName)
+		current, found := cfg.Get(key)
+		switch {
+		case !found:
+			result.Added = append(result.Added, hostName)
+		case current.String() != hosts[hostName]:
+			result.Changed = append(result.Changed, hostName)
+		default:
+			result.Unchanged = append(result.Unchanged, hostName)
+			continue
+		}
+
+		cfg, err = cfg.Set(key, config.Value(hosts[hostName]))
+		if err != nil {
+			return cfg, fmt.Errorf("failed to set key '%s' in global config: %w", key, err)
+		}
+	}
+
+	if replace {
+		for key := range cfg.GetAll() {
+			hostName, isAdditionalHost := strings.CutPrefix(key.String(), additionalHostsPrefix)
+			if !isAdditionalHost {
+				continue
+			}
+			if _, imported := hosts[hostName]; !imported {
+				result.Removed = append(result.Removed, hostName)
+				cfg = cfg.Delete(key)
+			}
+		}
+		slices.Sort(result.Removed)
+	}
+
+	return cfg, nil
+}
+
+// additionalHosts maps the host names of the given host aliases to their ips.
+func additionalHosts(hostAliases []v1.HostAlias) (map[string]string, error) {
+	hosts := map[string]string{}
+	for _, alias := range hostAliases {
+		for _, hostName := range alias.Hostnames {
+			err := validateHostName(hostName)
+			if err != nil {
+				return nil, err
+			}
+
+			ip, found := hosts[hostName]
+			if found && ip != alias.IP {
+				return nil, fmt.Errorf("host name '%s' is mapped to both '%s' and '%s'", hostName, ip, alias.IP)
+			}
+			hosts[hostName] = alias.IP
+		}
+	}
+
+	return hosts, nil
+}
+
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+
+	return keys
+}
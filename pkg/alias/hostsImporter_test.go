@@ -0,0 +1,148 @@
+package alias
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestHostsImporter_Import(t *testing.T) {
+	entries := func() config.Entries {
+		return config.Entries{
+			"fqdn":                                   "ces.example.com",
+			"containers/additional_hosts/git.local":  "10.0.0.2",
+			"containers/additional_hosts/mail.local": "10.0.0.3",
+			"containers/additional_hosts/old.local":  "10.0.0.4",
+		}
+	}
+	hostAliases := []v1.HostAlias{
+		{IP: "10.0.0.2", Hostnames: []string{"git.local"}},
+		{IP: "fd00::3", Hostnames: []string{"mail.local", "smtp.local"}},
+	}
+
+	t.Run("should add and change additional hosts", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		repoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(entries()), nil)
+		repoMock.EXPECT().Update(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, cfg config.GlobalConfig) (config.GlobalConfig, error) {
+			assert.Equal(t, config.Entries{
+				"fqdn":                                   "ces.example.com",
+				"containers/additional_hosts/git.local":  "10.0.0.2",
+				"containers/additional_hosts/mail.local": "fd00::3",
+				"containers/additional_hosts/old.local":  "10.0.0.4",
+				"containers/additional_hosts/smtp.local": "fd00::3",
+			}, cfg.GetAll())
+			return cfg, nil
+		})
+
+		// when
+		result, err := NewHostsImporter(repoMock).Import(context.TODO(), hostAliases, ImportOptions{})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &ImportResult{
+			Added:     []string{"smtp.local"},
+			Changed:   []string{"mail.local"},
+			Unchanged: []string{"git.local"},
+		}, result)
+	})
+	t.Run("should remove hosts which are not imported on replace", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		repoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(entries()), nil)
+		repoMock.EXPECT().Update(mock.Anything, mock.Anything).RunAndReturn(func(_ context.Context, cfg config.GlobalConfig) (config.GlobalConfig, error) {
+			_, found := cfg.Get("containers/additional_hosts/old.local")
+			assert.False(t, found)
+			return cfg, nil
+		})
+
+		// when
+		result, err := NewHostsImporter(repoMock).Import(context.TODO(), hostAliases, ImportOptions{Replace: true})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"old.local"}, result.Removed)
+	})
+	t.Run("should not write on dry run", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		repoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(entries()), nil)
+
+		// when
+		result, err := NewHostsImporter(repoMock).Import(context.TODO(), hostAliases, ImportOptions{Replace: true, DryRun: true})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"smtp.local"}, result.Added)
+		assert.Equal(t, []string{"old.local"}, result.Removed)
+	})
+	t.Run("should not write without changes", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		repoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(entries()), nil)
+
+		// when
+		result, err := NewHostsImporter(repoMock).Import(context.TODO(), []v1.HostAlias{{IP: "10.0.0.2", Hostnames: []string{"git.local"}}}, ImportOptions{})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"git.local"}, result.Unchanged)
+	})
+	t.Run("should skip the fqdn", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		repoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(entries()), nil)
+		aliases := []v1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}}, {IP: "10.0.0.2", Hostnames: []string{"git.local"}}}
+
+		// when
+		result, err := NewHostsImporter(repoMock).Import(context.TODO(), aliases, ImportOptions{})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &ImportResult{Unchanged: []string{"git.local"}, Skipped: []string{"ces.example.com"}}, result)
+	})
+	t.Run("should fail on host name mapped to different ips", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		aliases := []v1.HostAlias{{IP: "10.0.0.2", Hostnames: []string{"git.local"}}, {IP: "10.0.0.3", Hostnames: []string{"git.local"}}}
+
+		// when
+		_, err := NewHostsImporter(repoMock).Import(context.TODO(), aliases, ImportOptions{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "host name 'git.local' is mapped to both '10.0.0.2' and '10.0.0.3'")
+	})
+	t.Run("should fail on invalid host name", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		aliases := []v1.HostAlias{{IP: "10.0.0.2", Hostnames: []string{"git/local"}}}
+
+		// when
+		_, err := NewHostsImporter(repoMock).Import(context.TODO(), aliases, ImportOptions{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid host name 'git/local'")
+	})
+	t.Run("should fail on update error", func(t *testing.T) {
+		// given
+		repoMock := newMockGlobalConfigRepository(t)
+		repoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(entries()), nil)
+		repoMock.EXPECT().Update(mock.Anything, mock.Anything).Return(config.GlobalConfig{}, assert.AnError)
+
+		// when
+		_, err := NewHostsImporter(repoMock).Import(context.TODO(), hostAliases, ImportOptions{})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to update global config")
+	})
+}
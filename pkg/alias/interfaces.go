@@ -8,3 +8,8 @@ import (
 type globalConfigGetter interface {
 	Get(ctx context.Context) (config.GlobalConfig, error)
 }
+
+type globalConfigRepository interface {
+	Get(ctx context.Context) (config.GlobalConfig, error)
+	Update(ctx context.Context, globalConfig config.GlobalConfig) (config.GlobalConfig, error)
+}
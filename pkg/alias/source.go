@@ -0,0 +1,68 @@
+package alias
+
+import (
+	"context"
+	"fmt"
+
+	v1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Source provides host aliases from a single place, e.g. the global config or a file.
+type Source interface {
+	// Name identifies the source in the origins of the generated host aliases.
+	Name() string
+	// HostAliases returns the host aliases provided by the source.
+	HostAliases(ctx context.Context) ([]v1.HostAlias, error)
+}
+
+// Origin traces a generated host name back to the source it was taken from.
+type Origin struct {
+	Hostname string `json:"hostname"`
+	IP       string `json:"ip"`
+	// Source is the name of the source with the highest precedence which provides the host name.
+	Source string `json:"source"`
+	// Overridden are the names of the sources with lower precedence which provide the host name as well.
+	Overridden []string `json:"overridden,omitempty"`
+}
+
+// merge reads the host aliases of all sources. If several sources provide the same host name, the ip of the last
+// source wins. Every host name results in its own host alias, ordered by the first occurrence of the host name.
+func merge(ctx context.Context, sources []Source) ([]v1.HostAlias, []Origin, error) {
+	logger := log.FromContext(ctx)
+
+	var origins []Origin
+	index := map[string]int{}
+	for _, source := range sources {
+		aliases, err := source.HostAliases(ctx)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read host aliases from source '%s': %w", source.Name(), err)
+		}
+
+		for _, alias := range aliases {
+			for _, hostname := range alias.Hostnames {
+				i, found := index[hostname]
+				if !found {
+					index[hostname] = len(origins)
+					origins = append(origins, Origin{Hostname: hostname, IP: alias.IP, Source: source.Name()})
+					continue
+				}
+
+				if origins[i].Source != source.Name() {
+					logger.Info("Override host name", "hostname", hostname, "source", source.Name(),
+						"overriddenSource", origins[i].Source)
+					origins[i].Overridden = append(origins[i].Overridden, origins[i].Source)
+				}
+				origins[i].IP = alias.IP
+				origins[i].Source = source.Name()
+			}
+		}
+	}
+
+	var hostAliases []v1.HostAlias
+	for _, origin := range origins {
+		hostAliases = append(hostAliases, v1.HostAlias{IP: origin.IP, Hostnames: []string{origin.Hostname}})
+	}
+
+	return hostAliases, origins, nil
+}
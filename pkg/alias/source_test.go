@@ -0,0 +1,100 @@
+package alias
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudogu/k8s-registry-lib/config"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+func TestHostAliasGenerator_GenerateWithOrigins(t *testing.T) {
+	entries := config.Entries{
+		"fqdn":                                   "ces.example.com",
+		"k8s/use_internal_ip":                    "true",
+		"k8s/internal_ip":                        "10.0.0.1",
+		"containers/additional_hosts/git.local":  "10.0.0.2",
+		"containers/additional_hosts/mail.local": "10.0.0.3",
+	}
+
+	t.Run("should merge sources with increasing precedence", func(t *testing.T) {
+		// given
+		globalConfigRepoMock := newMockGlobalConfigGetter(t)
+		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(entries), nil)
+		fromFlags := NewStaticSource("flags", map[string]string{"git.local": "10.0.0.9", "ldap.local": "10.0.0.4"})
+		generator := NewHostAliasGenerator(globalConfigRepoMock, fromFlags)
+
+		// when
+		aliases, origins, err := generator.GenerateWithOrigins(context.TODO())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []v1.HostAlias{
+			{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}},
+			{IP: "10.0.0.9", Hostnames: []string{"git.local"}},
+			{IP: "10.0.0.3", Hostnames: []string{"mail.local"}},
+			{IP: "10.0.0.4", Hostnames: []string{"ldap.local"}},
+		}, aliases)
+		assert.Equal(t, []Origin{
+			{Hostname: "ces.example.com", IP: "10.0.0.1", Source: GlobalConfigSourceName},
+			{Hostname: "git.local", IP: "10.0.0.9", Source: "flags", Overridden: []string{GlobalConfigSourceName}},
+			{Hostname: "mail.local", IP: "10.0.0.3", Source: GlobalConfigSourceName},
+			{Hostname: "ldap.local", IP: "10.0.0.4", Source: "flags"},
+		}, origins)
+	})
+	t.Run("should split host aliases with multiple host names", func(t *testing.T) {
+		// given
+		globalConfigRepoMock := newMockGlobalConfigGetter(t)
+		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(config.Entries{
+			"fqdn":                "ces.example.com",
+			"k8s/use_internal_ip": "false",
+		}), nil)
+		source := newTestSource("file", []v1.HostAlias{{IP: "10.0.0.5", Hostnames: []string{"a.local", "b.local"}}})
+		generator := NewHostAliasGenerator(globalConfigRepoMock, source)
+
+		// when
+		aliases, err := generator.Generate(context.TODO())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []v1.HostAlias{
+			{IP: "10.0.0.5", Hostnames: []string{"a.local"}},
+			{IP: "10.0.0.5", Hostnames: []string{"b.local"}},
+		}, aliases)
+	})
+	t.Run("should fail if a source fails", func(t *testing.T) {
+		// given
+		globalConfigRepoMock := newMockGlobalConfigGetter(t)
+		globalConfigRepoMock.EXPECT().Get(mock.Anything).Return(config.CreateGlobalConfig(entries), nil)
+		fromFlags := NewStaticSource("flags", map[string]string{"git.local": "not-an-ip"})
+		generator := NewHostAliasGenerator(globalConfigRepoMock, fromFlags)
+
+		// when
+		_, _, err := generator.GenerateWithOrigins(context.TODO())
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to read host aliases from source 'flags': value 'not-an-ip' of host 'git.local' is not a valid ip")
+	})
+}
+
+type testSource struct {
+	name        string
+	hostAliases []v1.HostAlias
+}
+
+func newTestSource(name string, hostAliases []v1.HostAlias) *testSource {
+	return &testSource{name: name, hostAliases: hostAliases}
+}
+
+func (s *testSource) Name() string {
+	return s.name
+}
+
+func (s *testSource) HostAliases(context.Context) ([]v1.HostAlias, error) {
+	return s.hostAliases, nil
+}
@@ -0,0 +1,41 @@
+package alias
+
+import (
+	"context"
+	"fmt"
+	"net"
+
+	v1 "k8s.io/api/core/v1"
+)
+
+// StaticSource provides fixed host aliases, e.g. from environment variables or command line flags.
+type StaticSource struct {
+	name  string
+	hosts map[string]string
+}
+
+// NewStaticSource creates a source with the given name which maps host names to ips.
+func NewStaticSource(name string, hosts map[string]string) *StaticSource {
+	return &StaticSource{name: name, hosts: hosts}
+}
+
+// Name returns the name of the source.
+func (s *StaticSource) Name() string {
+	return s.name
+}
+
+// HostAliases returns the configured host aliases ordered by their host names. It fails on an invalid ip.
+func (s *StaticSource) HostAliases(_ context.Context) ([]v1.HostAlias, error) {
+	hostNames := sortedKeys(s.hosts)
+
+	hostAliases := make([]v1.HostAlias, 0, len(hostNames))
+	for _, hostName := range hostNames {
+		ip := s.hosts[hostName]
+		if net.ParseIP(ip) == nil {
+			return nil, fmt.Errorf("value '%s' of host '%s' is not a valid ip", ip, hostName)
+		}
+		hostAliases = append(hostAliases, v1.HostAlias{IP: ip, Hostnames: []string{hostName}})
+	}
+
+	return hostAliases, nil
+}
@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+
+	"github.com/spf13/cobra"
+)
+
+func newApplyCommand(opts *globalOptions) *cobra.Command {
+	var confirmAliasRemoval bool
+	var failurePolicy string
+	var backend string
+	var reportFile string
+	var terminationMessagePath string
+	var verifyPods bool
+	var podVerificationTimeout time.Duration
+	var verifyHostsFile bool
+	cmd := &cobra.Command{
+		Use:   "apply",
+		Short: "Update the host aliases of all dogu deployments",
+		Long: "Generates the host aliases from the global config and writes them into all dogu deployments or, with the " +
+			"backend coredns, into the CoreDNS configuration without restarting the dogus. " +
+			"The behaviour is configured by the configuration file, environment variables and flags, see the operations documentation.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			change, err := newHostChange(opts)
+			if err != nil {
+				return err
+			}
+			change.settings.AliasRemoval.Confirmed = change.settings.AliasRemoval.Confirmed || confirmAliasRemoval
+			if cmd.Flags().Changed("failure-policy") {
+				change.settings.FailurePolicy = failurePolicy
+			}
+			if cmd.Flags().Changed("backend") {
+				change.settings.Backend = backend
+			}
+			if cmd.Flags().Changed("report-file") {
+				change.settings.Report.File = reportFile
+			}
+			if cmd.Flags().Changed("termination-message-path") {
+				change.settings.Report.TerminationMessagePath = terminationMessagePath
+			}
+			if cmd.Flags().Changed("verify-pods") {
+				change.settings.Pods.Verify = verifyPods
+			}
+			if cmd.Flags().Changed("pod-verification-timeout") {
+				change.settings.Pods.Timeout = podVerificationTimeout
+			}
+			if cmd.Flags().Changed("verify-hosts-file") {
+				change.settings.Pods.VerifyHostsFile = verifyHostsFile
+			}
+
+			err = validateSettings(change.settings)
+			if err != nil {
+				return err
+			}
+
+			return applyHostChange(cmd, opts, change)
+		},
+	}
+
+	cmd.Flags().BoolVar(&confirmAliasRemoval, "confirm-alias-removal", false, "confirm the removal of host names beyond the allowed share")
+	cmd.Flags().StringVar(&reportFile, "report-file", "", "write the result report to this file; YAML for .yaml and .yml files, JSON otherwise")
+	cmd.Flags().StringVar(&terminationMessagePath, "termination-message-path", "", "write the result report as compact JSON to this container termination message file")
+	cmd.Flags().BoolVar(&verifyPods, "verify-pods", false, "check that the running pods of the updated dogu deployments carry the new host aliases")
+	cmd.Flags().DurationVar(&podVerificationTimeout, "pod-verification-timeout", 0, "wait this long for pods without the new host aliases to be replaced; 0 only reports them")
+	cmd.Flags().BoolVar(&verifyHostsFile, "verify-hosts-file", false, "read /etc/hosts of a pod of every updated dogu deployment via exec and compare it with the new host aliases")
+	cmd.Flags().StringVar(&failurePolicy, "failure-policy", "", "policy for failed dogu deployments: rollback-all, fail-fast or continue-on-error")
+	cmd.Flags().StringVar(&backend, "backend", "", "publish the host aliases in the dogu deployments (host-aliases) or in the CoreDNS configuration without restarting the dogus (coredns)")
+
+	return cmd
+}
+
+// applyHostChange publishes the host aliases with the configured backend, records the run history and writes the result
+// report.
+func applyHostChange(cmd *cobra.Command, opts *globalOptions, change *hostChange) error {
+	backend, err := change.backend()
+	if err != nil {
+		return err
+	}
+
+	// cancel the update on timeout so that the rollback and cleanups like deactivating the maintenance mode
+	// still run
+	ctx := cmd.Context()
+	if change.settings.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, change.settings.Timeout)
+		defer cancel()
+	}
+
+	globalConfig, err := change.generator.GlobalConfigValues(ctx)
+	if err != nil {
+		logger.Error(err, "Failed to read global config values for the report")
+	}
+
+	result, err := backend.UpdateHostsWithResult(ctx, change.namespace)
+	result.GlobalConfig = globalConfig
+
+	historyErr := change.recordHistory(cmd.Context(), result)
+	if historyErr != nil {
+		err = multierror.Append(err, historyErr)
+	}
+
+	reportErr := writeReport(cmd.OutOrStdout(), opts.output, result, change.settings.Report)
+	if reportErr != nil {
+		err = multierror.Append(err, reportErr)
+	}
+
+	return err
+}
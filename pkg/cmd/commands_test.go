@@ -0,0 +1,751 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/cloudogu/k8s-host-change/pkg/coredns"
+	"github.com/cloudogu/k8s-host-change/pkg/deployment"
+	"github.com/cloudogu/k8s-host-change/pkg/hostsfile"
+)
+
+const withoutInternalIP = `fqdn: ces.example.com
+k8s:
+  use_internal_ip: "false"
+`
+
+func Test_applyCommand(t *testing.T) {
+	t.Run("should update dogu deployments", func(t *testing.T) {
+		// given
+		clientSet := setUpCluster(t, doguDeployment("cas", nil))
+
+		// when
+		_, err := execute(t, "apply")
+
+		// then
+		require.NoError(t, err)
+		actual, err := clientSet.AppsV1().Deployments(testNamespace).Get(context.TODO(), "cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, expectedHostAliases, actual.Spec.Template.Spec.HostAliases)
+		assert.Equal(t, "[]", actual.Annotations[deployment.PreviousHostAliasesAnnotation])
+	})
+	t.Run("should refuse to remove all host aliases without confirmation", func(t *testing.T) {
+		// given
+		setUpClusterWithConfig(t, withoutInternalIP, doguDeployment("cas", expectedHostAliases))
+
+		// when
+		_, err := execute(t, "apply")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "refusing to remove all 1 host names")
+	})
+	t.Run("should remove all host aliases with confirmation flag", func(t *testing.T) {
+		// given
+		clientSet := setUpClusterWithConfig(t, withoutInternalIP, doguDeployment("cas", expectedHostAliases))
+
+		// when
+		_, err := execute(t, "apply", "--confirm-alias-removal")
+
+		// then
+		require.NoError(t, err)
+		actual, err := clientSet.AppsV1().Deployments(testNamespace).Get(context.TODO(), "cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, actual.Spec.Template.Spec.HostAliases)
+	})
+	t.Run("should remove all host aliases with confirmation from configuration file", func(t *testing.T) {
+		// given
+		clientSet := setUpClusterWithConfig(t, withoutInternalIP, doguDeployment("cas", expectedHostAliases))
+		path := writeConfig(t, "apiVersion: k8s.cloudogu.com/v1\nkind: HostChangeConfiguration\naliasRemoval:\n  confirmed: true\n")
+
+		// when
+		_, err := execute(t, "apply", "--config", path)
+
+		// then
+		require.NoError(t, err)
+		actual, err := clientSet.AppsV1().Deployments(testNamespace).Get(context.TODO(), "cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, actual.Spec.Template.Spec.HostAliases)
+	})
+	t.Run("should override failure policy with flag", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil))
+		t.Setenv("FAILURE_POLICY", "fail-fast")
+
+		// when
+		_, err := execute(t, "apply", "--failure-policy", "ignore")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "unknown failure policy 'ignore'")
+	})
+	t.Run("should publish host aliases to CoreDNS without changing dogu deployments", func(t *testing.T) {
+		// given
+		clientSet := setUpCluster(t, doguDeployment("cas", nil))
+
+		// when
+		out, err := execute(t, "apply", "--backend", "coredns")
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, out, "(backend 'coredns')\n  published 1 host aliases to config map kube-system/coredns-custom\n")
+		corednsCustom, err := clientSet.CoreV1().ConfigMaps(coredns.DefaultNamespace).Get(context.TODO(), coredns.DefaultConfigMap, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, coredns.RenderServerBlock(testNamespace, expectedHostAliases), corednsCustom.Data[coredns.Key(testNamespace)])
+		actual, err := clientSet.AppsV1().Deployments(testNamespace).Get(context.TODO(), "cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, actual.Spec.Template.Spec.HostAliases)
+		assert.Empty(t, actual.Annotations[deployment.PreviousHostAliasesAnnotation])
+	})
+	t.Run("should remove host aliases from dogu deployments when switching to CoreDNS", func(t *testing.T) {
+		// given
+		clientSet := setUpCluster(t, doguDeployment("cas", expectedHostAliases))
+
+		// when
+		out, err := execute(t, "apply", "--backend", "coredns")
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, out, "  cas: updated\n")
+		actual, err := clientSet.AppsV1().Deployments(testNamespace).Get(context.TODO(), "cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, actual.Spec.Template.Spec.HostAliases)
+	})
+	t.Run("should fail on unknown backend", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil))
+		t.Setenv("BACKEND", "etc-hosts")
+
+		// when
+		_, err := execute(t, "apply")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "unknown backend 'etc-hosts'")
+	})
+}
+
+func Test_planCommand(t *testing.T) {
+	t.Run("should print planned changes", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil), doguDeployment("ldap", expectedHostAliases))
+
+		// when
+		out, err := execute(t, "plan")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Host aliases:\n10.0.0.1\tces.example.com\n\n"+
+			"1 of 2 dogu deployments would be updated:\n  cas: update\n  ldap: unchanged\n", out)
+	})
+	t.Run("should print planned changes as json", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil))
+
+		// when
+		out, err := execute(t, "plan", "-o", "json")
+
+		// then
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"hostAliases": [{"ip": "10.0.0.1", "hostnames": ["ces.example.com"]}],
+			"deployments": [{"name": "cas", "currentHostAliases": null, "changed": true}]
+		}`, out)
+	})
+}
+
+func Test_previewCommand(t *testing.T) {
+	t.Run("should print diff of changed hosts files", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil), doguDeployment("ldap", expectedHostAliases))
+
+		// when
+		out, err := execute(t, "preview")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "--- cas:/etc/hosts (current)\n"+
+			"+++ cas:/etc/hosts (new)\n"+
+			"@@ -6,3 +6,6 @@\n"+
+			" fe00::1\tip6-allnodes\n"+
+			" fe00::2\tip6-allrouters\n"+
+			" <pod-ip>\t<pod-name>\n"+
+			"+\n"+
+			"+# Entries added by HostAliases.\n"+
+			"+10.0.0.1\tces.example.com\n"+
+			"\n"+
+			"ldap: unchanged\n", out)
+	})
+	t.Run("should print complete hosts file of given deployment", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil), doguDeployment("ldap", nil))
+
+		// when
+		out, err := execute(t, "preview", "ldap", "--full")
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, strings.HasPrefix(out, "ldap:/etc/hosts\n# Kubernetes-managed hosts file.\n"))
+		assert.True(t, strings.HasSuffix(out, "# Entries added by HostAliases.\n10.0.0.1\tces.example.com\n\n"))
+		assert.NotContains(t, out, "cas:/etc/hosts")
+	})
+	t.Run("should print previews as json", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", expectedHostAliases))
+
+		// when
+		out, err := execute(t, "preview", "-o", "json")
+
+		// then
+		require.NoError(t, err)
+		var previews []hostsFilePreview
+		require.NoError(t, json.Unmarshal([]byte(out), &previews))
+		require.Len(t, previews, 1)
+		assert.Equal(t, "cas", previews[0].Name)
+		assert.False(t, previews[0].Changed)
+		assert.Equal(t, previews[0].Current, previews[0].New)
+		assert.Empty(t, previews[0].Diff)
+	})
+	t.Run("should fail on unknown deployment", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil))
+
+		// when
+		_, err := execute(t, "preview", "redmine")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "dogu deployment 'redmine' not found")
+	})
+}
+
+func Test_verifyCommand(t *testing.T) {
+	t.Run("should succeed without drift", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", expectedHostAliases))
+
+		// when
+		out, err := execute(t, "verify")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "All 1 dogu deployments carry the expected host aliases\n", out)
+	})
+	t.Run("should fail on drift", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", expectedHostAliases), doguDeployment("ldap", nil))
+
+		// when
+		out, err := execute(t, "verify", "-o", "yaml")
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, exitCodeDrift, exitCode(err))
+		assert.ErrorContains(t, err, "1 dogu deployments do not carry the expected host aliases: [ldap]")
+		assert.Equal(t, "drifted:\n- missingHostAliases:\n  - hostnames:\n    - ces.example.com\n    ip: 10.0.0.1\n  name: ldap\nverified: 2\n", out)
+	})
+	t.Run("should print missing and unexpected host names", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", []corev1.HostAlias{{IP: "10.0.0.9", Hostnames: []string{"ces.example.com"}}}))
+
+		// when
+		out, err := execute(t, "verify")
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, exitCodeDrift, exitCode(err))
+		assert.Equal(t, "1 of 1 dogu deployments drifted:\n"+
+			"  cas\n"+
+			"    missing: 10.0.0.1 ces.example.com\n"+
+			"    unexpected: 10.0.0.9 ces.example.com\n", out)
+	})
+}
+
+func Test_verifyCommand_coreDNS(t *testing.T) {
+	t.Run("should succeed if the config map contains the server block", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil), &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: coredns.DefaultConfigMap, Namespace: coredns.DefaultNamespace},
+			Data:       map[string]string{coredns.Key(testNamespace): coredns.RenderServerBlock(testNamespace, expectedHostAliases)},
+		})
+		t.Setenv("BACKEND", "coredns")
+
+		// when
+		out, err := execute(t, "verify")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Config map kube-system/coredns-custom contains the expected server block with 1 host aliases\n", out)
+	})
+	t.Run("should fail on drift if the config map does not contain the server block", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil))
+		t.Setenv("BACKEND", "coredns")
+
+		// when
+		out, err := execute(t, "verify")
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, exitCodeDrift, exitCode(err))
+		assert.ErrorContains(t, err, "config map kube-system/coredns-custom does not contain the expected server block of the ecosystem")
+		assert.Equal(t, "Config map kube-system/coredns-custom does not contain the expected server block; "+
+			"run apply to publish these host aliases:\n  10.0.0.1 ces.example.com\n", out)
+	})
+	t.Run("should reject checking pods", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil))
+		t.Setenv("BACKEND", "coredns")
+
+		// when
+		_, err := execute(t, "verify", "--pods")
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, exitCodeFailure, exitCode(err))
+		assert.ErrorContains(t, err, "--pods and --hosts-file check the host aliases of the dogu deployments, which the backend 'coredns' does not write")
+	})
+}
+
+func Test_verifyCommand_pods(t *testing.T) {
+	t.Run("should succeed if all running pods carry the expected host aliases", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", expectedHostAliases), doguPod("cas-new", "cas", expectedHostAliases))
+
+		// when
+		out, err := execute(t, "verify", "--pods")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "All 1 dogu deployments carry the expected host aliases\n", out)
+	})
+	t.Run("should fail on stale pods", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", expectedHostAliases),
+			doguPod("cas-new", "cas", expectedHostAliases),
+			doguPod("cas-old", "cas", []corev1.HostAlias{{IP: "10.0.0.9", Hostnames: []string{"ces.example.com"}}}))
+
+		// when
+		out, err := execute(t, "verify", "--pods")
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, exitCodeDrift, exitCode(err))
+		assert.ErrorContains(t, err, "1 running pods do not carry the expected host aliases: [cas-old]")
+		assert.Equal(t, "All 1 dogu deployments carry the expected host aliases\n"+
+			"1 running pods do not carry the expected host aliases:\n"+
+			"  cas-old (deployment cas)\n", out)
+	})
+}
+
+func Test_applyCommand_verifyPods(t *testing.T) {
+	t.Run("should report stale pods", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil), doguPod("cas-old", "cas", nil))
+
+		// when
+		out, err := execute(t, "apply", "--verify-pods")
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, out, "  cas: updated\n    stale pods: cas-old\n")
+	})
+	t.Run("should fail on pods which are still stale after the timeout", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil), doguPod("cas-old", "cas", nil))
+
+		// when
+		_, err := execute(t, "apply", "--verify-pods", "--pod-verification-timeout", "10ms")
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, exitCodeFailure, exitCode(err))
+		assert.ErrorContains(t, err, "1 running pods do not carry the new host aliases: 'cas-old' of deployment 'cas'")
+	})
+}
+
+func Test_verifyCommand_hostsFile(t *testing.T) {
+	t.Run("should succeed if hosts files contain the expected host aliases", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", expectedHostAliases), doguPod("cas-new", "cas", expectedHostAliases))
+		setUpPodExecutor(t, map[string]string{"cas-new": "# Entries added by HostAliases.\n10.0.0.1\tces.example.com\n"})
+
+		// when
+		out, err := execute(t, "verify", "--hosts-file")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "All 1 dogu deployments carry the expected host aliases\n", out)
+	})
+	t.Run("should fail on hosts file mismatch", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", expectedHostAliases), doguPod("cas-new", "cas", expectedHostAliases))
+		setUpPodExecutor(t, map[string]string{"cas-new": "127.0.0.1\tlocalhost\n"})
+
+		// when
+		out, err := execute(t, "verify", "--hosts-file")
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, exitCodeDrift, exitCode(err))
+		assert.ErrorContains(t, err, "hosts files of 1 dogu deployments do not contain the expected host aliases: [cas]")
+		assert.Equal(t, "All 1 dogu deployments carry the expected host aliases\n"+
+			"Hosts files of 1 dogu deployments do not contain the expected host aliases:\n"+
+			"  cas: hosts file of pod 'cas-new': missing 10.0.0.1 ces.example.com\n", out)
+	})
+}
+
+func Test_applyCommand_verifyHostsFile(t *testing.T) {
+	t.Run("should fail on hosts file mismatch", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil), doguPod("cas-new", "cas", expectedHostAliases))
+		setUpPodExecutor(t, map[string]string{"cas-new": "127.0.0.1\tlocalhost\n"})
+
+		// when
+		out, err := execute(t, "apply", "--verify-hosts-file")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "hosts files of 1 dogu deployments do not contain the new host aliases")
+		assert.Contains(t, out, "  cas: updated\n    hosts file of pod 'cas-new': missing 10.0.0.1 ces.example.com\n")
+	})
+}
+
+// testPodExecutor stands in for the exec transport and returns the hosts file of the pod with the given name.
+type testPodExecutor struct {
+	hostsFiles map[string]string
+}
+
+func (e *testPodExecutor) Exec(_ context.Context, _ string, pod string, _ string, _ []string) ([]byte, error) {
+	content, found := e.hostsFiles[pod]
+	if !found {
+		return nil, fmt.Errorf("pod '%s' not found", pod)
+	}
+
+	return []byte(content), nil
+}
+
+func setUpPodExecutor(t *testing.T, hostsFiles map[string]string) {
+	t.Helper()
+	original := newPodExecutor
+	t.Cleanup(func() { newPodExecutor = original })
+	newPodExecutor = func(*rest.Config, kubernetes.Interface) hostsfile.PodExecutor {
+		return &testPodExecutor{hostsFiles: hostsFiles}
+	}
+}
+
+func doguPod(name string, dogu string, aliases []corev1.HostAlias) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, Labels: map[string]string{"dogu.name": dogu}},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: dogu}}, HostAliases: aliases},
+		Status:     corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
+
+func Test_rollbackCommand(t *testing.T) {
+	t.Run("should restore previous host aliases", func(t *testing.T) {
+		// given
+		previous := []corev1.HostAlias{{IP: "10.0.0.9", Hostnames: []string{"ces.example.com"}}}
+		cas := doguDeployment("cas", expectedHostAliases)
+		cas.Annotations = map[string]string{deployment.PreviousHostAliasesAnnotation: `[{"ip":"10.0.0.9","hostnames":["ces.example.com"]}]`}
+		clientSet := setUpCluster(t, cas, doguDeployment("ldap", expectedHostAliases))
+
+		// when
+		out, err := execute(t, "rollback")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Restored host aliases of 1 dogu deployments: [cas]\n", out)
+		actual, err := clientSet.AppsV1().Deployments(testNamespace).Get(context.TODO(), "cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, previous, actual.Spec.Template.Spec.HostAliases)
+		assert.NotContains(t, actual.Annotations, deployment.PreviousHostAliasesAnnotation)
+	})
+	t.Run("should report nothing to roll back", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", expectedHostAliases))
+
+		// when
+		out, err := execute(t, "rollback")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "No host change to roll back\n", out)
+	})
+	t.Run("should fail with the backend coredns", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", expectedHostAliases))
+		t.Setenv("BACKEND", "coredns")
+
+		// when
+		_, err := execute(t, "rollback")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "the dogu deployments do not carry host aliases with the backend 'coredns'")
+	})
+}
+
+func Test_exportCommand(t *testing.T) {
+	t.Run("should print host aliases", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+
+		// when
+		out, err := execute(t, "export")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.1\tces.example.com\n", out)
+	})
+	t.Run("should fail to generate host aliases", func(t *testing.T) {
+		// given
+		setUpClusterWithConfig(t, "k8s:\n  use_internal_ip: \"true\"\n  internal_ip: 10.0.0.1\n")
+
+		// when
+		_, err := execute(t, "export")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to generate host aliases")
+	})
+	t.Run("should merge alias sources and print origins", func(t *testing.T) {
+		// given
+		// the name sorts after the global config because the fake client ignores the field selector of the global
+		// config repository and returns the first config map
+		setUpCluster(t, &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "host-aliases", Namespace: testNamespace},
+			Data:       map[string]string{"git.local": "10.0.0.2", "ldap.local": "10.0.0.3"},
+		})
+		path := filepath.Join(t.TempDir(), "hosts")
+		require.NoError(t, os.WriteFile(path, []byte("10.0.0.4 ldap.local\n"), 0600))
+		t.Setenv("ADDITIONAL_HOSTS", "")
+
+		// when
+		out, err := execute(t, "export", "--show-origins", "--alias-config-map", "host-aliases",
+			"--alias-file", path, "--additional-host", "ldap.local=10.0.0.5")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.1\tces.example.com\t# global-config\n"+
+			"10.0.0.2\tgit.local\t# configmap/host-aliases\n"+
+			"10.0.0.5\tldap.local\t# additional-hosts (overrides configmap/host-aliases, file:"+path+")\n", out)
+	})
+	t.Run("should print host aliases in hosts file syntax", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+
+		// when
+		out, err := execute(t, "export", "--hosts-file")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "# Host aliases of the dogus in namespace ecosystem generated by k8s-host-change\n"+
+			"10.0.0.1\tces.example.com\n", out)
+	})
+	t.Run("should print origins as json", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+
+		// when
+		out, err := execute(t, "export", "--show-origins", "-o", "json")
+
+		// then
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"hostname": "ces.example.com", "ip": "10.0.0.1", "source": "global-config"}]`, out)
+	})
+}
+
+func Test_importCommand(t *testing.T) {
+	const hostsFile = `# from the network team
+10.0.0.2	git.local mail.local
+fd00::3	ldap.local
+`
+
+	t.Run("should write additional hosts into global config", func(t *testing.T) {
+		// given
+		clientSet := setUpCluster(t)
+		path := filepath.Join(t.TempDir(), "hosts")
+		require.NoError(t, os.WriteFile(path, []byte(hostsFile), 0600))
+
+		// when
+		out, err := execute(t, "import", path)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Imported additional hosts: 3 added, 0 changed, 0 removed, 0 unchanged\n"+
+			"  + git.local ldap.local mail.local\n", out)
+		out, err = execute(t, "export")
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.1\tces.example.com\n10.0.0.2\tgit.local\nfd00::3\tldap.local\n10.0.0.2\tmail.local\n", out)
+		globalConfig, err := clientSet.CoreV1().ConfigMaps(testNamespace).Get(context.TODO(), "global-config", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Contains(t, globalConfig.Data["config.yaml"], "ldap.local: fd00::3")
+	})
+	t.Run("should read hosts file from stdin and not write on dry run", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+		root := NewRootCommand()
+		out := &bytes.Buffer{}
+		root.SetOut(out)
+		root.SetIn(strings.NewReader(hostsFile))
+		root.SetArgs([]string{"import", "-", "--dry-run"})
+
+		// when
+		err := root.ExecuteContext(context.TODO())
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Would import additional hosts: 3 added, 0 changed, 0 removed, 0 unchanged\n"+
+			"  + git.local ldap.local mail.local\n", out.String())
+		exported, err := execute(t, "export")
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.1\tces.example.com\n", exported)
+	})
+	t.Run("should import exported hosts file without changes", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+		_, err := execute(t, "add-host", "git.local", "10.0.0.2")
+		require.NoError(t, err)
+		exported, err := execute(t, "export", "--hosts-file")
+		require.NoError(t, err)
+		path := filepath.Join(t.TempDir(), "hosts")
+		require.NoError(t, os.WriteFile(path, []byte(exported), 0600))
+
+		// when
+		out, err := execute(t, "import", path, "--replace")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Imported additional hosts: 0 added, 0 changed, 0 removed, 1 unchanged\n"+
+			"Skipped the FQDN ces.example.com which is generated from the internal ip\n", out)
+		out, err = execute(t, "list-hosts", "-o", "json")
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"hostname": "git.local", "ip": "10.0.0.2"}]`, out)
+	})
+	t.Run("should fail on invalid hosts file", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+		path := filepath.Join(t.TempDir(), "hosts")
+		require.NoError(t, os.WriteFile(path, []byte("git.local 10.0.0.2\n"), 0600))
+
+		// when
+		_, err := execute(t, "import", path)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "line 1: 'git.local' is not a valid ip")
+	})
+}
+
+func Test_hostsConfigCommands(t *testing.T) {
+	t.Run("should add and list additional hosts", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+
+		// when
+		out, err := execute(t, "add-host", "git.local", "10.0.0.2")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Added host git.local with ip 10.0.0.2\n", out)
+		out, err = execute(t, "list-hosts", "-o", "json")
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"hostname": "git.local", "ip": "10.0.0.2"}]`, out)
+	})
+	t.Run("should remove additional host", func(t *testing.T) {
+		// given
+		setUpClusterWithConfig(t, defaultGlobalConfig+"containers:\n  additional_hosts:\n    git.local: 10.0.0.2\n")
+
+		// when
+		out, err := execute(t, "remove-host", "git.local")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Removed host git.local\n", out)
+		out, err = execute(t, "list-hosts")
+		require.NoError(t, err)
+		assert.Empty(t, out)
+	})
+	t.Run("should fail to remove unknown host", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+
+		// when
+		_, err := execute(t, "remove-host", "git.local")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to remove host 'git.local': additional host 'git.local' does not exist")
+	})
+	t.Run("should set internal ip and apply", func(t *testing.T) {
+		// given
+		clientSet := setUpClusterWithConfig(t, withoutInternalIP, doguDeployment("cas", nil))
+
+		// when
+		out, err := execute(t, "set-internal-ip", "10.0.0.7", "--apply")
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, out, "Set internal ip to 10.0.0.7\n")
+		actual, err := clientSet.AppsV1().Deployments(testNamespace).Get(context.TODO(), "cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []corev1.HostAlias{{IP: "10.0.0.7", Hostnames: []string{"ces.example.com"}}}, actual.Spec.Template.Spec.HostAliases)
+	})
+	t.Run("should disable internal ip without apply", func(t *testing.T) {
+		// given
+		clientSet := setUpCluster(t, doguDeployment("cas", expectedHostAliases))
+
+		// when
+		out, err := execute(t, "disable-internal-ip")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "Disabled internal ip\n", out)
+		out, err = execute(t, "export")
+		require.NoError(t, err)
+		assert.Empty(t, out)
+		actual, err := clientSet.AppsV1().Deployments(testNamespace).Get(context.TODO(), "cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, expectedHostAliases, actual.Spec.Template.Spec.HostAliases)
+	})
+	t.Run("should fail on invalid ip", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+
+		// when
+		_, err := execute(t, "set-internal-ip", "localhost")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to set internal ip: 'localhost' is not a valid ip")
+	})
+}
+
+func Test_webhookCommand(t *testing.T) {
+	t.Run("should fail without serving certificate", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+
+		// when
+		_, err := execute(t, "webhook", "--cert-dir", t.TempDir(), "--port", "19443")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to serve webhook")
+	})
+}
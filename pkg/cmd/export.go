@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudogu/k8s-host-change/pkg/alias"
+	"github.com/cloudogu/k8s-host-change/pkg/hostsfile"
+)
+
+func newExportCommand(opts *globalOptions) *cobra.Command {
+	var showOrigins bool
+	var hostsFile bool
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Print the host aliases generated from the global config",
+		Long: "Prints the host aliases generated from the global config and the additional alias sources. " +
+			"With --show-origins every host name is printed with the source it was taken from. " +
+			"With --hosts-file the host aliases are printed in hosts file syntax which import accepts.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			change, err := newHostChange(opts)
+			if err != nil {
+				return err
+			}
+
+			hostAliases, origins, err := change.generator.GenerateWithOrigins(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to generate host aliases: %w", err)
+			}
+
+			if hostsFile {
+				header := fmt.Sprintf("Host aliases of the dogus in namespace %s generated by k8s-host-change", change.namespace)
+				_, err = fmt.Fprint(cmd.OutOrStdout(), hostsfile.Format(header, hostAliases))
+				return err
+			}
+
+			if showOrigins {
+				return printResult(cmd.OutOrStdout(), opts.output, origins, func(w io.Writer) {
+					printOrigins(w, origins)
+				})
+			}
+
+			return printResult(cmd.OutOrStdout(), opts.output, hostAliases, func(w io.Writer) {
+				if len(hostAliases) > 0 {
+					_, _ = fmt.Fprintln(w, formatHostAliases(hostAliases))
+				}
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&showOrigins, "show-origins", false, "print the source of every host name")
+	cmd.Flags().BoolVar(&hostsFile, "hosts-file", false, "print the host aliases in hosts file syntax with one line per ip")
+	cmd.MarkFlagsMutuallyExclusive("show-origins", "hosts-file")
+
+	return cmd
+}
+
+func printOrigins(w io.Writer, origins []alias.Origin) {
+	for _, origin := range origins {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t# %s", origin.IP, origin.Hostname, origin.Source)
+		if len(origin.Overridden) > 0 {
+			_, _ = fmt.Fprintf(w, " (overrides %s)", strings.Join(origin.Overridden, ", "))
+		}
+		_, _ = fmt.Fprintln(w)
+	}
+}
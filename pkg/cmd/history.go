@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudogu/k8s-host-change/pkg/history"
+)
+
+func newHistoryCommand(opts *globalOptions) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show past host changes recorded in the cluster",
+		Long: "Every apply records its result in the config map " + history.ConfigMapName + ". " +
+			"The number of recorded host changes is limited by the history retention.",
+	}
+
+	cmd.AddCommand(newHistoryListCommand(opts), newHistoryShowCommand(opts))
+
+	return cmd
+}
+
+func newHistoryListCommand(opts *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List past host changes, the latest first",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			change, err := newHostChange(opts)
+			if err != nil {
+				return err
+			}
+
+			entries, err := history.NewStore(change.clientSet, change.settings.History.Retention).List(cmd.Context(), change.namespace)
+			if err != nil {
+				return err
+			}
+
+			return printResult(cmd.OutOrStdout(), opts.output, entries, func(w io.Writer) {
+				printHistory(w, entries)
+			})
+		},
+	}
+}
+
+func printHistory(w io.Writer, entries []history.Entry) {
+	if len(entries) == 0 {
+		_, _ = fmt.Fprintln(w, "No host changes recorded")
+		return
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "RUN ID\tSTART TIME\tDURATION\tSTATUS\tTRIGGERED BY\tDEPLOYMENTS")
+	for _, entry := range entries {
+		triggeredBy := entry.TriggeredBy
+		if triggeredBy == "" {
+			triggeredBy = "<unknown>"
+		}
+		_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\n", entry.RunID, entry.StartTime.UTC().Format("2006-01-02T15:04:05Z"),
+			entry.Duration.Duration, entry.Status, triggeredBy, len(entry.Deployments))
+	}
+	_ = tw.Flush()
+}
+
+func newHistoryShowCommand(opts *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "show RUN_ID",
+		Short: "Show the details of a past host change",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			change, err := newHostChange(opts)
+			if err != nil {
+				return err
+			}
+
+			entry, err := history.NewStore(change.clientSet, change.settings.History.Retention).Get(cmd.Context(), change.namespace, args[0])
+			if err != nil {
+				return err
+			}
+
+			return printResult(cmd.OutOrStdout(), opts.output, entry, func(w io.Writer) {
+				printHistoryEntry(w, entry)
+			})
+		},
+	}
+}
+
+func printHistoryEntry(w io.Writer, entry *history.Entry) {
+	_, _ = fmt.Fprintf(w, "Run ID:         %s\n", entry.RunID)
+	_, _ = fmt.Fprintf(w, "Start time:     %s\n", entry.StartTime.UTC().Format("2006-01-02T15:04:05Z"))
+	_, _ = fmt.Fprintf(w, "Duration:       %s\n", entry.Duration.Duration)
+	_, _ = fmt.Fprintf(w, "Status:         %s\n", entry.Status)
+	_, _ = fmt.Fprintf(w, "Triggered by:   %s\n", entry.TriggeredBy)
+	_, _ = fmt.Fprintf(w, "Failure policy: %s\n", entry.FailurePolicy)
+	if entry.Error != "" {
+		_, _ = fmt.Fprintf(w, "Error:          %s\n", entry.Error)
+	}
+
+	_, _ = fmt.Fprintln(w, "\nGlobal config:")
+	for _, key := range slices.Sorted(maps.Keys(entry.GlobalConfig)) {
+		_, _ = fmt.Fprintf(w, "  %s: %s\n", key, entry.GlobalConfig[key])
+	}
+
+	_, _ = fmt.Fprintln(w, "\nHost aliases:")
+	if len(entry.HostAliases) > 0 {
+		_, _ = fmt.Fprintln(w, formatHostAliases(entry.HostAliases))
+	}
+
+	_, _ = fmt.Fprintln(w, "\nDogu deployments:")
+	printDeploymentResults(w, entry.Deployments)
+}
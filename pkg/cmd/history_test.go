@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudogu/k8s-host-change/pkg/history"
+	"github.com/cloudogu/k8s-host-change/pkg/hosts"
+)
+
+func Test_historyCommand(t *testing.T) {
+	t.Run("should list recorded host changes", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil))
+		t.Setenv("TRIGGERED_BY", "admin")
+		_, err := execute(t, "apply")
+		require.NoError(t, err)
+
+		// when
+		out, err := execute(t, "history", "list")
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, out, "RUN ID")
+		assert.Contains(t, out, "succeeded")
+		assert.Contains(t, out, "admin")
+	})
+	t.Run("should show recorded host change", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil))
+		t.Setenv("TRIGGERED_BY", "admin")
+		out, err := execute(t, "apply", "-o", "json")
+		require.NoError(t, err)
+		result := &hosts.Result{}
+		require.NoError(t, json.Unmarshal([]byte(out), result))
+
+		// when
+		out, err = execute(t, "history", "show", result.RunID, "-o", "json")
+
+		// then
+		require.NoError(t, err)
+		entry := &history.Entry{}
+		require.NoError(t, json.Unmarshal([]byte(out), entry))
+		assert.Equal(t, result.RunID, entry.RunID)
+		assert.Equal(t, "admin", entry.TriggeredBy)
+		assert.Equal(t, "10.0.0.1", entry.GlobalConfig["k8s/internal_ip"])
+		require.Len(t, entry.Deployments, 1)
+		assert.Equal(t, hosts.ActionUpdated, entry.Deployments[0].Action)
+	})
+	t.Run("should print recorded host change as text", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil))
+		out, err := execute(t, "apply", "-o", "json")
+		require.NoError(t, err)
+		result := &hosts.Result{}
+		require.NoError(t, json.Unmarshal([]byte(out), result))
+
+		// when
+		out, err = execute(t, "history", "show", result.RunID)
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, out, "Run ID:         "+result.RunID)
+		assert.Contains(t, out, "  k8s/internal_ip: 10.0.0.1\n")
+		assert.Contains(t, out, "10.0.0.1\tces.example.com\n")
+		assert.Contains(t, out, "  cas: updated\n")
+	})
+	t.Run("should report empty history", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+
+		// when
+		out, err := execute(t, "history", "list")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "No host changes recorded\n", out)
+	})
+	t.Run("should not record history if disabled", func(t *testing.T) {
+		// given
+		clientSet := setUpCluster(t, doguDeployment("cas", nil))
+		t.Setenv("HISTORY_RETENTION", "0")
+
+		// when
+		_, err := execute(t, "apply")
+
+		// then
+		require.NoError(t, err)
+		_, err = clientSet.CoreV1().ConfigMaps(testNamespace).Get(context.TODO(), history.ConfigMapName, metav1.GetOptions{})
+		assert.True(t, apierrors.IsNotFound(err))
+	})
+	t.Run("should fail on unknown run id", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+
+		// when
+		_, err := execute(t, "history", "show", "unknown")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "no host change with run id 'unknown' found in history")
+	})
+}
@@ -0,0 +1,202 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudogu/k8s-registry-lib/repository"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+
+	"github.com/cloudogu/k8s-host-change/pkg/alias"
+	"github.com/cloudogu/k8s-host-change/pkg/coredns"
+	"github.com/cloudogu/k8s-host-change/pkg/dogu"
+	"github.com/cloudogu/k8s-host-change/pkg/history"
+	"github.com/cloudogu/k8s-host-change/pkg/hosts"
+	"github.com/cloudogu/k8s-host-change/pkg/hostsfile"
+	"github.com/cloudogu/k8s-host-change/pkg/initializer"
+	"github.com/cloudogu/k8s-host-change/pkg/rollout"
+	"github.com/cloudogu/k8s-host-change/pkg/settings"
+)
+
+const maintenanceModeOwner = "k8s-host-change"
+
+// staticSourceName identifies the additional hosts from environment variables, flags and the configuration file in
+// the origins of the host aliases.
+const staticSourceName = "additional-hosts"
+
+// historyTimeout limits writing the run history after the host change.
+const historyTimeout = 10 * time.Second
+
+// newPodExecutor creates the transport used to read the hosts files of pods. It is replaced in tests.
+var newPodExecutor = func(restConfig *rest.Config, clientSet kubernetes.Interface) hostsfile.PodExecutor {
+	return hostsfile.NewExecutor(restConfig, clientSet)
+}
+
+// hostChange bundles everything the commands need to work on the dogu deployments of an ecosystem.
+type hostChange struct {
+	namespace  string
+	restConfig *rest.Config
+	clientSet  kubernetes.Interface
+	settings   *settings.Settings
+	generator  *alias.HostAliasGenerator
+	// globalConfigRepo reads and writes the global config the host aliases are generated from.
+	globalConfigRepo *repository.GlobalConfigRepository
+}
+
+func newHostChange(opts *globalOptions) (*hostChange, error) {
+	cfg := opts.settings
+	init := initializer.New(initializer.Options{
+		Kubeconfig: opts.kubeconfig,
+		Context:    opts.context,
+		Namespace:  cfg.Namespace,
+		QPS:        opts.qps,
+		Burst:      opts.burst,
+		Timeout:    opts.requestTimeout,
+	})
+	namespace := init.GetNamespace()
+
+	restConfig, err := init.RestConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	clientSet, err := init.CreateClientSet()
+	if err != nil {
+		return nil, err
+	}
+
+	globalConfigRepo := repository.NewGlobalConfigRepository(clientSet.CoreV1().ConfigMaps(namespace))
+
+	return &hostChange{
+		namespace:        namespace,
+		restConfig:       restConfig,
+		clientSet:        clientSet,
+		settings:         cfg,
+		generator:        alias.NewHostAliasGenerator(globalConfigRepo, aliasSources(clientSet, namespace, cfg.AliasSources)...),
+		globalConfigRepo: globalConfigRepo,
+	}, nil
+}
+
+// aliasSources creates the configured host alias sources ordered by increasing precedence.
+func aliasSources(clientSet kubernetes.Interface, namespace string, cfg settings.AliasSources) []alias.Source {
+	var sources []alias.Source
+	if cfg.ConfigMap != "" {
+		sources = append(sources, alias.NewConfigMapSource(clientSet, namespace, cfg.ConfigMap))
+	}
+	if cfg.File != "" {
+		sources = append(sources, alias.NewFileSource(cfg.File))
+	}
+	if len(cfg.Hosts) > 0 {
+		sources = append(sources, alias.NewStaticSource(staticSourceName, cfg.Hosts))
+	}
+
+	return sources
+}
+
+// recordHistory appends the given result to the run history if the history is enabled. The history is written even if
+// the given context is done, e.g. because the process is terminating.
+func (h *hostChange) recordHistory(ctx context.Context, result *hosts.Result) error {
+	cfg := h.settings.History
+	if cfg.Retention == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), historyTimeout)
+	defer cancel()
+
+	triggeredBy := cfg.TriggeredBy
+	if triggeredBy == "" {
+		triggeredBy = history.Identity(ctx, h.clientSet)
+	}
+
+	entry := history.Entry{TriggeredBy: triggeredBy, Result: *result}
+	return history.NewStore(h.clientSet, cfg.Retention).Append(ctx, h.namespace, entry)
+}
+
+// findStalePods returns the running pods of all dogu deployments which do not carry the given host aliases. It waits
+// for stale pods to be replaced as configured by the settings.
+func (h *hostChange) findStalePods(ctx context.Context, hostAliases []corev1.HostAlias) ([]rollout.StalePod, error) {
+	deployments, err := dogu.NewDeploymentFetcher(h.clientSet).FetchAll(ctx, h.namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dogu deployments: %w", err)
+	}
+
+	return rollout.NewPodVerifier(h.clientSet, h.settings.Pods.Timeout).FindStalePods(ctx, h.namespace, deployments, hostAliases)
+}
+
+// verifyHostsFiles reads the hosts file of a running pod of every dogu deployment via exec and returns the pods whose
+// hosts file does not contain the given host aliases.
+func (h *hostChange) verifyHostsFiles(ctx context.Context, hostAliases []corev1.HostAlias) ([]hostsfile.Mismatch, error) {
+	deployments, err := dogu.NewDeploymentFetcher(h.clientSet).FetchAll(ctx, h.namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dogu deployments: %w", err)
+	}
+
+	verifier := hostsfile.NewVerifier(h.clientSet, newPodExecutor(h.restConfig, h.clientSet))
+	return verifier.VerifyHostsFiles(ctx, h.namespace, deployments, hostAliases)
+}
+
+// backend creates the backend configured by the settings which publishes the host aliases.
+func (h *hostChange) backend() (hosts.Backend, error) {
+	backend, err := hosts.ParseBackendType(h.settings.Backend)
+	if err != nil {
+		return nil, err
+	}
+
+	if backend == hosts.BackendCoreDNS {
+		return hosts.NewDNSUpdater(h.clientSet, h.publisher(), hosts.WithGenerator(h.generator)), nil
+	}
+
+	return h.updater()
+}
+
+// publisher creates the publisher of the CoreDNS config map configured by the settings.
+func (h *hostChange) publisher() *coredns.Publisher {
+	cfg := h.settings.CoreDNS
+	return coredns.NewPublisher(h.clientSet, coredns.Options{Namespace: cfg.Namespace, ConfigMap: cfg.ConfigMap})
+}
+
+// updater creates a host alias updater configured by the settings. It fails if the configured backend does not write
+// the host aliases into the dogu deployments.
+func (h *hostChange) updater() (*hosts.DefaultHostAliasUpdater, error) {
+	cfg := h.settings
+	backend, err := hosts.ParseBackendType(cfg.Backend)
+	if err != nil {
+		return nil, err
+	}
+	if backend != hosts.BackendHostAliases {
+		return nil, fmt.Errorf("the dogu deployments do not carry host aliases with the backend '%s'", backend)
+	}
+
+	failurePolicy, err := hosts.ParseFailurePolicy(cfg.FailurePolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	opts := hosts.Options{
+		WaitForRollout:         cfg.Rollout.Wait,
+		RolloutTimeout:         cfg.Rollout.Timeout,
+		RolloutGlobalTimeout:   cfg.Rollout.GlobalTimeout,
+		StagedRestart:          cfg.Rollout.Staged,
+		Canary:                 cfg.Canary.Enabled,
+		CanaryDogu:             cfg.Canary.Dogu,
+		ShutdownTimeout:        cfg.ShutdownTimeout,
+		MaxAliasRemovalShare:   cfg.AliasRemoval.MaxShare,
+		ConfirmAliasRemoval:    cfg.AliasRemoval.Confirmed,
+		RemovalConfirmation:    h.generator,
+		FailurePolicy:          failurePolicy,
+		VerifyPods:             cfg.Pods.Verify,
+		PodVerificationTimeout: cfg.Pods.Timeout,
+	}
+	if cfg.Pods.VerifyHostsFile {
+		opts.HostsFileVerifier = hostsfile.NewVerifier(h.clientSet, newPodExecutor(h.restConfig, h.clientSet))
+	}
+	if cfg.MaintenanceMode {
+		opts.MaintenanceMode = repository.NewMaintenanceModeAdapter(maintenanceModeOwner, h.clientSet.CoreV1().ConfigMaps(h.namespace))
+	}
+
+	return hosts.NewUpdater(h.clientSet, hosts.WithGenerator(h.generator), hosts.WithOptions(opts)), nil
+}
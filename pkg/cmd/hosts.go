@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudogu/k8s-host-change/pkg/alias"
+)
+
+// newHostsConfigCommand creates a command which changes the host configuration in the global config with the given
+// function. With --apply the host aliases of the dogu deployments are updated afterwards.
+func newHostsConfigCommand(opts *globalOptions, cmd *cobra.Command, change func(cmd *cobra.Command, writer *alias.HostsConfigWriter, args []string) (string, error)) *cobra.Command {
+	var apply bool
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		hostChange, err := newHostChange(opts)
+		if err != nil {
+			return err
+		}
+
+		message, err := change(cmd, alias.NewHostsConfigWriter(hostChange.globalConfigRepo), args)
+		if err != nil {
+			return err
+		}
+
+		logger.Info(message)
+		if opts.output == outputText {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout(), message)
+		}
+		if !apply {
+			return nil
+		}
+
+		return applyHostChange(cmd, opts, hostChange)
+	}
+
+	cmd.Flags().BoolVar(&apply, "apply", false, "update the host aliases of all dogu deployments afterwards")
+
+	return cmd
+}
+
+func newSetInternalIPCommand(opts *globalOptions) *cobra.Command {
+	return newHostsConfigCommand(opts, &cobra.Command{
+		Use:   "set-internal-ip <ip>",
+		Short: "Resolve the fqdn to the given internal ip in all dogus",
+		Args:  cobra.ExactArgs(1),
+	}, func(cmd *cobra.Command, writer *alias.HostsConfigWriter, args []string) (string, error) {
+		err := writer.SetInternalIP(cmd.Context(), args[0])
+		if err != nil {
+			return "", fmt.Errorf("failed to set internal ip: %w", err)
+		}
+
+		return fmt.Sprintf("Set internal ip to %s", args[0]), nil
+	})
+}
+
+func newDisableInternalIPCommand(opts *globalOptions) *cobra.Command {
+	return newHostsConfigCommand(opts, &cobra.Command{
+		Use:   "disable-internal-ip",
+		Short: "Stop resolving the fqdn to the internal ip in the dogus",
+		Args:  cobra.NoArgs,
+	}, func(cmd *cobra.Command, writer *alias.HostsConfigWriter, _ []string) (string, error) {
+		err := writer.DisableInternalIP(cmd.Context())
+		if err != nil {
+			return "", fmt.Errorf("failed to disable internal ip: %w", err)
+		}
+
+		return "Disabled internal ip", nil
+	})
+}
+
+func newAddHostCommand(opts *globalOptions) *cobra.Command {
+	return newHostsConfigCommand(opts, &cobra.Command{
+		Use:   "add-host <host name> <ip>",
+		Short: "Add an additional host to the global config or change its ip",
+		Args:  cobra.ExactArgs(2),
+	}, func(cmd *cobra.Command, writer *alias.HostsConfigWriter, args []string) (string, error) {
+		err := writer.AddHost(cmd.Context(), args[0], args[1])
+		if err != nil {
+			return "", fmt.Errorf("failed to add host '%s': %w", args[0], err)
+		}
+
+		return fmt.Sprintf("Added host %s with ip %s", args[0], args[1]), nil
+	})
+}
+
+func newRemoveHostCommand(opts *globalOptions) *cobra.Command {
+	return newHostsConfigCommand(opts, &cobra.Command{
+		Use:   "remove-host <host name>",
+		Short: "Remove an additional host from the global config",
+		Args:  cobra.ExactArgs(1),
+	}, func(cmd *cobra.Command, writer *alias.HostsConfigWriter, args []string) (string, error) {
+		err := writer.RemoveHost(cmd.Context(), args[0])
+		if err != nil {
+			return "", fmt.Errorf("failed to remove host '%s': %w", args[0], err)
+		}
+
+		return fmt.Sprintf("Removed host %s", args[0]), nil
+	})
+}
+
+func newListHostsCommand(opts *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "list-hosts",
+		Short: "List the additional hosts of the global config",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			change, err := newHostChange(opts)
+			if err != nil {
+				return err
+			}
+
+			hosts, err := alias.NewHostsConfigWriter(change.globalConfigRepo).ListHosts(cmd.Context())
+			if err != nil {
+				return fmt.Errorf("failed to list hosts: %w", err)
+			}
+
+			return printResult(cmd.OutOrStdout(), opts.output, hosts, func(w io.Writer) {
+				for _, host := range hosts {
+					_, _ = fmt.Fprintf(w, "%s\t%s\n", host.Hostname, host.IP)
+				}
+			})
+		},
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudogu/k8s-host-change/pkg/alias"
+	"github.com/cloudogu/k8s-host-change/pkg/hostsfile"
+)
+
+func newImportCommand(opts *globalOptions) *cobra.Command {
+	var importOpts alias.ImportOptions
+	cmd := &cobra.Command{
+		Use:   "import <file>",
+		Short: "Write the entries of a hosts file as additional hosts into the global config",
+		Long: "Parses a file in hosts file syntax and writes every host name as key below containers/additional_hosts/ " +
+			"with its ip as value. The FQDN of the ecosystem is skipped. '-' reads the file from stdin. " +
+			"The dogus are updated by the next apply.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			content, err := readInput(cmd, args[0])
+			if err != nil {
+				return err
+			}
+
+			hostAliases, err := hostsfile.Parse(content)
+			if err != nil {
+				return fmt.Errorf("failed to parse hosts file '%s': %w", args[0], err)
+			}
+
+			change, err := newHostChange(opts)
+			if err != nil {
+				return err
+			}
+
+			result, err := alias.NewHostsImporter(change.globalConfigRepo).Import(cmd.Context(), hostAliases, importOpts)
+			if err != nil {
+				return fmt.Errorf("failed to import hosts file '%s': %w", args[0], err)
+			}
+
+			return printResult(cmd.OutOrStdout(), opts.output, result, func(w io.Writer) {
+				printImportResult(w, result, importOpts.DryRun)
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&importOpts.Replace, "replace", false, "remove additional hosts which are not contained in the file")
+	cmd.Flags().BoolVar(&importOpts.DryRun, "dry-run", false, "only print the changes without writing the global config")
+
+	return cmd
+}
+
+// readInput reads the file at the given path or stdin for '-'.
+func readInput(cmd *cobra.Command, path string) ([]byte, error) {
+	if path == "-" {
+		content, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stdin: %w", err)
+		}
+		return content, nil
+	}
+
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read file '%s': %w", path, err)
+	}
+
+	return content, nil
+}
+
+func printImportResult(w io.Writer, result *alias.ImportResult, dryRun bool) {
+	verb := "Imported"
+	if dryRun {
+		verb = "Would import"
+	}
+	_, _ = fmt.Fprintf(w, "%s additional hosts: %d added, %d changed, %d removed, %d unchanged\n", verb,
+		len(result.Added), len(result.Changed), len(result.Removed), len(result.Unchanged))
+
+	for _, change := range []struct {
+		prefix    string
+		hostNames []string
+	}{{"+", result.Added}, {"~", result.Changed}, {"-", result.Removed}} {
+		if len(change.hostNames) > 0 {
+			_, _ = fmt.Fprintf(w, "  %s %s\n", change.prefix, strings.Join(change.hostNames, " "))
+		}
+	}
+	if len(result.Skipped) > 0 {
+		_, _ = fmt.Fprintf(w, "Skipped the FQDN %s which is generated from the internal ip\n", strings.Join(result.Skipped, " "))
+	}
+}
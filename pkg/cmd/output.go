@@ -0,0 +1,59 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	outputText = "text"
+	outputJSON = "json"
+	outputYAML = "yaml"
+)
+
+func validateOutput(output string) error {
+	switch output {
+	case outputText, outputJSON, outputYAML:
+		return nil
+	default:
+		return fmt.Errorf("unknown output format '%s': expected one of '%s', '%s' or '%s'", output, outputText, outputJSON, outputYAML)
+	}
+}
+
+// printResult writes the given result in the given output format. The text format is rendered by the given function.
+func printResult(w io.Writer, output string, result any, printText func(w io.Writer)) error {
+	switch output {
+	case outputJSON:
+		raw, err := json.MarshalIndent(result, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to render result as json: %w", err)
+		}
+		_, err = fmt.Fprintln(w, string(raw))
+		return err
+	case outputYAML:
+		raw, err := yaml.Marshal(result)
+		if err != nil {
+			return fmt.Errorf("failed to render result as yaml: %w", err)
+		}
+		_, err = w.Write(raw)
+		return err
+	default:
+		printText(w)
+		return nil
+	}
+}
+
+// formatHostAliases renders the host aliases like lines of a hosts file.
+func formatHostAliases(aliases []corev1.HostAlias) string {
+	lines := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		lines = append(lines, fmt.Sprintf("%s\t%s", alias.IP, strings.Join(alias.Hostnames, " ")))
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_printResult(t *testing.T) {
+	result := rollbackResult{Restored: []string{"cas"}}
+	printText := func(w io.Writer) {
+		_, _ = io.WriteString(w, "text\n")
+	}
+
+	tests := []struct {
+		name   string
+		output string
+		want   string
+	}{
+		{name: "should print text", output: outputText, want: "text\n"},
+		{name: "should print json", output: outputJSON, want: "{\n  \"restored\": [\n    \"cas\"\n  ]\n}\n"},
+		{name: "should print yaml", output: outputYAML, want: "restored:\n- cas\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out := &bytes.Buffer{}
+
+			err := printResult(out, tt.output, result, printText)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, out.String())
+		})
+	}
+}
+
+func Test_validateOutput(t *testing.T) {
+	assert.NoError(t, validateOutput(outputText))
+	assert.NoError(t, validateOutput(outputJSON))
+	assert.NoError(t, validateOutput(outputYAML))
+	assert.ErrorContains(t, validateOutput("xml"), "unknown output format 'xml'")
+}
+
+func Test_formatHostAliases(t *testing.T) {
+	// given
+	aliases := []corev1.HostAlias{
+		{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}},
+		{IP: "10.0.0.2", Hostnames: []string{"db.example.com", "database.example.com"}},
+	}
+
+	// when
+	actual := formatHostAliases(aliases)
+
+	// then
+	assert.Equal(t, "10.0.0.1\tces.example.com\n10.0.0.2\tdb.example.com database.example.com", actual)
+}
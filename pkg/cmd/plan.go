@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudogu/k8s-host-change/pkg/hosts"
+)
+
+func newPlanCommand(opts *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "plan",
+		Short: "Show which dogu deployments apply would update without changing anything",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			change, err := newHostChange(opts)
+			if err != nil {
+				return err
+			}
+
+			updater, err := change.updater()
+			if err != nil {
+				return err
+			}
+
+			plan, err := updater.Plan(cmd.Context(), change.namespace)
+			if err != nil {
+				return err
+			}
+
+			return printResult(cmd.OutOrStdout(), opts.output, plan, func(w io.Writer) {
+				printPlan(w, plan)
+			})
+		},
+	}
+}
+
+func printPlan(w io.Writer, plan *hosts.Plan) {
+	if len(plan.HostAliases) > 0 {
+		_, _ = fmt.Fprintf(w, "Host aliases:\n%s\n\n", formatHostAliases(plan.HostAliases))
+	} else {
+		_, _ = fmt.Fprint(w, "Host aliases: none\n\n")
+	}
+
+	_, _ = fmt.Fprintf(w, "%d of %d dogu deployments would be updated:\n", len(plan.ChangedDeployments()), len(plan.Deployments))
+	for _, deploy := range plan.Deployments {
+		action := "unchanged"
+		if deploy.Changed {
+			action = "update"
+		}
+		_, _ = fmt.Fprintf(w, "  %s: %s\n", deploy.Name, action)
+	}
+}
@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"slices"
+
+	"github.com/spf13/cobra"
+
+	"github.com/cloudogu/k8s-host-change/pkg/hosts"
+	"github.com/cloudogu/k8s-host-change/pkg/hostsfile"
+)
+
+// hostsFilePreview contains the hosts file of a dogu deployment before and after the host change.
+type hostsFilePreview struct {
+	Name string `json:"name"`
+	// Changed is true if the host change would change the hosts file.
+	Changed bool   `json:"changed"`
+	Current string `json:"current"`
+	New     string `json:"new"`
+	// Diff is the unified diff from the current to the new hosts file. It is empty if the hosts file is unchanged.
+	Diff string `json:"diff,omitempty"`
+}
+
+func newPreviewCommand(opts *globalOptions) *cobra.Command {
+	var full bool
+	cmd := &cobra.Command{
+		Use:   "preview [deployment...]",
+		Short: "Show the /etc/hosts file of the dogus before and after the host change without changing anything",
+		Long: "Renders the hosts file which the kubelet writes into the pods of every dogu deployment with the current and " +
+			"the generated host aliases and prints the unified diff. The IP and the host name of the pod are only known " +
+			"at runtime and are rendered as placeholders. The arguments are names of dogu deployments, not of dogus, and " +
+			"restrict the preview to these deployments. Without arguments all dogu deployments are shown.",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			change, err := newHostChange(opts)
+			if err != nil {
+				return err
+			}
+
+			updater, err := change.updater()
+			if err != nil {
+				return err
+			}
+
+			plan, err := updater.Plan(cmd.Context(), change.namespace)
+			if err != nil {
+				return err
+			}
+
+			previews, err := previewHostsFiles(plan, args)
+			if err != nil {
+				return err
+			}
+
+			return printResult(cmd.OutOrStdout(), opts.output, previews, func(w io.Writer) {
+				printHostsFilePreviews(w, previews, full)
+			})
+		},
+	}
+
+	cmd.Flags().BoolVar(&full, "full", false, "print the complete new hosts file instead of the diff")
+
+	return cmd
+}
+
+// previewHostsFiles renders the hosts files of the planned deployments. If names of deployments are given, only these
+// deployments are rendered.
+func previewHostsFiles(plan *hosts.Plan, deployments []string) ([]hostsFilePreview, error) {
+	previews := []hostsFilePreview{}
+	for _, deploy := range plan.Deployments {
+		if len(deployments) > 0 && !slices.Contains(deployments, deploy.Name) {
+			continue
+		}
+
+		diff, err := hostsfile.Diff(deploy.Name, deploy.CurrentHostAliases, plan.HostAliases)
+		if err != nil {
+			return nil, err
+		}
+
+		previews = append(previews, hostsFilePreview{
+			Name:    deploy.Name,
+			Changed: diff != "",
+			Current: hostsfile.Render(deploy.CurrentHostAliases),
+			New:     hostsfile.Render(plan.HostAliases),
+			Diff:    diff,
+		})
+	}
+
+	for _, name := range deployments {
+		if !slices.ContainsFunc(previews, func(preview hostsFilePreview) bool { return preview.Name == name }) {
+			return nil, fmt.Errorf("dogu deployment '%s' not found", name)
+		}
+	}
+
+	return previews, nil
+}
+
+func printHostsFilePreviews(w io.Writer, previews []hostsFilePreview, full bool) {
+	for _, preview := range previews {
+		switch {
+		case full:
+			_, _ = fmt.Fprintf(w, "%s:/etc/hosts\n%s\n", preview.Name, preview.New)
+		case preview.Changed:
+			_, _ = fmt.Fprintln(w, preview.Diff)
+		default:
+			_, _ = fmt.Fprintf(w, "%s: unchanged\n", preview.Name)
+		}
+	}
+}
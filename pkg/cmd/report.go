@@ -0,0 +1,214 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/hashicorp/go-multierror"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/cloudogu/k8s-host-change/pkg/hosts"
+	"github.com/cloudogu/k8s-host-change/pkg/settings"
+)
+
+// maxTerminationMessageSize is the maximum size of a container termination message accepted by kubernetes.
+const maxTerminationMessageSize = 4096
+
+// writeReport writes the result of a host change to the given writer and to the file and the termination message
+// configured in the settings.
+func writeReport(w io.Writer, output string, result *hosts.Result, cfg settings.Report) error {
+	var err error
+	printErr := printResult(w, output, result, func(w io.Writer) { printApplyResult(w, result) })
+	if printErr != nil {
+		err = multierror.Append(err, printErr)
+	}
+
+	if cfg.File != "" {
+		fileErr := writeReportFile(cfg.File, result)
+		if fileErr != nil {
+			err = multierror.Append(err, fileErr)
+		}
+	}
+
+	if cfg.TerminationMessagePath != "" {
+		messageErr := writeTerminationMessage(cfg.TerminationMessagePath, result)
+		if messageErr != nil {
+			err = multierror.Append(err, messageErr)
+		}
+	}
+
+	return err
+}
+
+func printApplyResult(w io.Writer, result *hosts.Result) {
+	if result.Backend == hosts.BackendCoreDNS {
+		_, _ = fmt.Fprintf(w, "Host change %s after %s (backend '%s')\n", result.Status, result.Duration.Duration, result.Backend)
+		if result.Status == hosts.StatusSucceeded {
+			_, _ = fmt.Fprintf(w, "  published %d host aliases to config map %s\n", len(result.HostAliases), result.DNSConfigMap)
+		}
+		printDeploymentResults(w, result.Deployments)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "Host change %s after %s (failure policy '%s')\n", result.Status, result.Duration.Duration, result.FailurePolicy)
+	printDeploymentResults(w, result.Deployments)
+}
+
+func printDeploymentResults(w io.Writer, deployments []hosts.DeploymentResult) {
+	for _, deploy := range deployments {
+		if deploy.Error != "" {
+			_, _ = fmt.Fprintf(w, "  %s: %s: %s\n", deploy.Name, deploy.Action, deploy.Error)
+		} else {
+			_, _ = fmt.Fprintf(w, "  %s: %s\n", deploy.Name, deploy.Action)
+		}
+		if len(deploy.StalePods) > 0 {
+			_, _ = fmt.Fprintf(w, "    stale pods: %s\n", strings.Join(deploy.StalePods, ", "))
+		}
+		if deploy.HostsFileMismatch != "" {
+			_, _ = fmt.Fprintf(w, "    %s\n", deploy.HostsFileMismatch)
+		}
+	}
+}
+
+// writeReportFile writes the result as YAML if the file ends with .yaml or .yml and as JSON otherwise.
+func writeReportFile(path string, result *hosts.Result) error {
+	output := outputJSON
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		output = outputYAML
+	}
+
+	buf := &bytes.Buffer{}
+	err := printResult(buf, output, result, nil)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(path, buf.Bytes(), 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write report file '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+// writeTerminationMessage writes the result as compact JSON into the termination message. If the result exceeds the
+// size limit of the termination message, the host aliases are left out and, if that is not enough, the deployments are
+// only counted per action and the error message is truncated.
+func writeTerminationMessage(path string, result *hosts.Result) error {
+	message, err := terminationMessage(result)
+	if err != nil {
+		return err
+	}
+
+	err = os.WriteFile(path, message, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to write termination message '%s': %w", path, err)
+	}
+
+	return nil
+}
+
+// terminationSummary is the last resort of the termination message. It identifies the host change and counts the
+// dogu deployments per action instead of listing them.
+type terminationSummary struct {
+	RunID            string               `json:"runID"`
+	Namespace        string               `json:"namespace"`
+	Backend          hosts.BackendType    `json:"backend"`
+	Status           hosts.Status         `json:"status"`
+	FailurePolicy    hosts.FailurePolicy  `json:"failurePolicy"`
+	Error            string               `json:"error,omitempty"`
+	StartTime        metav1.Time          `json:"startTime"`
+	Duration         metav1.Duration      `json:"duration"`
+	DeploymentCounts map[hosts.Action]int `json:"deploymentCounts"`
+}
+
+func terminationMessage(result *hosts.Result) ([]byte, error) {
+	summary := *result
+	summary.Deployments = make([]hosts.DeploymentResult, 0, len(result.Deployments))
+	for _, deploy := range result.Deployments {
+		deploy.PreviousHostAliases = nil
+		deploy.NewHostAliases = nil
+		summary.Deployments = append(summary.Deployments, deploy)
+	}
+
+	for _, candidate := range []*hosts.Result{result, &summary} {
+		message, err := json.Marshal(candidate)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render termination message: %w", err)
+		}
+		if len(message) <= maxTerminationMessageSize {
+			return message, nil
+		}
+	}
+
+	return minimalTerminationMessage(result)
+}
+
+// minimalTerminationMessage renders the terminationSummary of the given result. If it does not fit into the
+// termination message, the error message is truncated to the longest prefix which fits.
+func minimalTerminationMessage(result *hosts.Result) ([]byte, error) {
+	minimal := terminationSummary{
+		RunID:            result.RunID,
+		Namespace:        result.Namespace,
+		Backend:          result.Backend,
+		Status:           result.Status,
+		FailurePolicy:    result.FailurePolicy,
+		Error:            result.Error,
+		StartTime:        result.StartTime,
+		Duration:         result.Duration,
+		DeploymentCounts: map[hosts.Action]int{},
+	}
+	for _, deploy := range result.Deployments {
+		minimal.DeploymentCounts[deploy.Action]++
+	}
+
+	message, err := json.Marshal(minimal)
+	if err != nil {
+		return nil, fmt.Errorf("failed to render termination message: %w", err)
+	}
+	if len(message) <= maxTerminationMessageSize {
+		return message, nil
+	}
+
+	var fitting []byte
+	low, high := 0, len(result.Error)-1
+	for low <= high {
+		end := (low + high) / 2
+		minimal.Error = truncate(result.Error, end)
+		message, err = json.Marshal(minimal)
+		if err != nil {
+			return nil, fmt.Errorf("failed to render termination message: %w", err)
+		}
+		if len(message) <= maxTerminationMessageSize {
+			fitting = message
+			low = end + 1
+		} else {
+			high = end - 1
+		}
+	}
+	if fitting == nil {
+		return nil, fmt.Errorf("failed to render termination message: result exceeds %d bytes", maxTerminationMessageSize)
+	}
+
+	return fitting, nil
+}
+
+// truncatedSuffix marks a truncated error message in the termination message.
+const truncatedSuffix = "…(truncated)"
+
+// truncate cuts the given message at the given byte offset and marks it with the truncatedSuffix. The message is only
+// cut at the start of a rune.
+func truncate(message string, end int) string {
+	for end > 0 && !utf8.RuneStart(message[end]) {
+		end--
+	}
+
+	return message[:end] + truncatedSuffix
+}
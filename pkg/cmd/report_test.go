@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"sigs.k8s.io/yaml"
+
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/cloudogu/k8s-host-change/pkg/hosts"
+)
+
+func Test_applyCommand_report(t *testing.T) {
+	t.Run("should print result as text", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil))
+
+		// when
+		out, err := execute(t, "apply")
+
+		// then
+		require.NoError(t, err)
+		assert.Contains(t, out, "Host change succeeded after ")
+		assert.Contains(t, out, "(failure policy 'rollback-all')\n  cas: updated\n")
+	})
+	t.Run("should print result as json", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil))
+
+		// when
+		out, err := execute(t, "apply", "-o", "json")
+
+		// then
+		require.NoError(t, err)
+		result := &hosts.Result{}
+		require.NoError(t, json.Unmarshal([]byte(out), result))
+		assert.Equal(t, hosts.StatusSucceeded, result.Status)
+		assert.Equal(t, testNamespace, result.Namespace)
+		assert.Equal(t, map[string]string{
+			"fqdn":                "ces.example.com",
+			"k8s/use_internal_ip": "true",
+			"k8s/internal_ip":     "10.0.0.1",
+		}, result.GlobalConfig)
+		assert.Equal(t, expectedHostAliases, result.HostAliases)
+		require.Len(t, result.Deployments, 1)
+		assert.Equal(t, "cas", result.Deployments[0].Name)
+		assert.Equal(t, hosts.ActionUpdated, result.Deployments[0].Action)
+		assert.Equal(t, expectedHostAliases, result.Deployments[0].NewHostAliases)
+	})
+	t.Run("should write result to report file and termination message", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil))
+		dir := t.TempDir()
+		reportFile := filepath.Join(dir, "report.yaml")
+		terminationMessage := filepath.Join(dir, "termination-log")
+
+		// when
+		_, err := execute(t, "apply", "--report-file", reportFile, "--termination-message-path", terminationMessage)
+
+		// then
+		require.NoError(t, err)
+		raw, err := os.ReadFile(reportFile)
+		require.NoError(t, err)
+		result := &hosts.Result{}
+		require.NoError(t, yaml.UnmarshalStrict(raw, result))
+		assert.Equal(t, hosts.StatusSucceeded, result.Status)
+
+		raw, err = os.ReadFile(terminationMessage)
+		require.NoError(t, err)
+		assert.NotContains(t, string(raw), "\n")
+		require.NoError(t, json.Unmarshal(raw, result))
+		assert.Equal(t, hosts.StatusSucceeded, result.Status)
+	})
+	t.Run("should write report of failed host change", func(t *testing.T) {
+		// given
+		setUpClusterWithConfig(t, withoutInternalIP, doguDeployment("cas", expectedHostAliases))
+		reportFile := filepath.Join(t.TempDir(), "report.json")
+
+		// when
+		_, err := execute(t, "apply", "--report-file", reportFile)
+
+		// then
+		require.Error(t, err)
+		raw, err := os.ReadFile(reportFile)
+		require.NoError(t, err)
+		result := &hosts.Result{}
+		require.NoError(t, json.Unmarshal(raw, result))
+		assert.Equal(t, hosts.StatusFailed, result.Status)
+		assert.Contains(t, result.Error, "refusing to remove all 1 host names")
+	})
+	t.Run("should fail to write report file", func(t *testing.T) {
+		// given
+		setUpCluster(t, doguDeployment("cas", nil))
+		reportFile := filepath.Join(t.TempDir(), "missing", "report.json")
+
+		// when
+		_, err := execute(t, "apply", "--report-file", reportFile)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to write report file")
+	})
+}
+
+func Test_terminationMessage(t *testing.T) {
+	t.Run("should leave out host aliases of large results", func(t *testing.T) {
+		// given
+		result := &hosts.Result{Status: hosts.StatusSucceeded}
+		aliases := []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{strings.Repeat("a", 100)}}}
+		for i := 0; i < 20; i++ {
+			result.Deployments = append(result.Deployments, hosts.DeploymentResult{
+				Name:           "dogu",
+				NewHostAliases: aliases,
+				Action:         hosts.ActionUpdated,
+			})
+		}
+
+		// when
+		message, err := terminationMessage(result)
+
+		// then
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(message), maxTerminationMessageSize)
+		assert.NotContains(t, string(message), "aaaa")
+		assert.Contains(t, string(message), `"action":"updated"`)
+	})
+	t.Run("should count deployments of very large results", func(t *testing.T) {
+		// given
+		result := &hosts.Result{RunID: "4f1c2a", Status: hosts.StatusFailed}
+		for i := 0; i < 100; i++ {
+			result.Deployments = append(result.Deployments, hosts.DeploymentResult{
+				Name:   "dogu",
+				Action: hosts.ActionFailed,
+				Error:  strings.Repeat("e", 100),
+			})
+		}
+		result.Deployments[0].Action = hosts.ActionUpdated
+
+		// when
+		message, err := terminationMessage(result)
+
+		// then
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(message), maxTerminationMessageSize)
+		assert.Contains(t, string(message), `"runID":"4f1c2a"`)
+		assert.Contains(t, string(message), `"status":"failed"`)
+		assert.Contains(t, string(message), `"deploymentCounts":{"failed":99,"updated":1}`)
+		assert.NotContains(t, string(message), `"deployments"`)
+	})
+	t.Run("should truncate very long error", func(t *testing.T) {
+		// given
+		result := &hosts.Result{RunID: "4f1c2a", Status: hosts.StatusFailed, Error: strings.Repeat("ä\"", 3000)}
+		result.Deployments = []hosts.DeploymentResult{{Name: "dogu", Action: hosts.ActionFailed}}
+
+		// when
+		message, err := terminationMessage(result)
+
+		// then
+		require.NoError(t, err)
+		assert.LessOrEqual(t, len(message), maxTerminationMessageSize)
+		var actual terminationSummary
+		require.NoError(t, json.Unmarshal(message, &actual))
+		assert.Equal(t, "4f1c2a", actual.RunID)
+		assert.Equal(t, map[hosts.Action]int{hosts.ActionFailed: 1}, actual.DeploymentCounts)
+		assert.True(t, strings.HasSuffix(actual.Error, "…(truncated)"))
+		assert.True(t, strings.HasPrefix(result.Error, strings.TrimSuffix(actual.Error, "…(truncated)")))
+		assert.Greater(t, len(message), maxTerminationMessageSize-8)
+	})
+}
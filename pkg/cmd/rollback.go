@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+type rollbackResult struct {
+	Restored []string `json:"restored"`
+}
+
+func newRollbackCommand(opts *globalOptions) *cobra.Command {
+	return &cobra.Command{
+		Use:   "rollback",
+		Short: "Restore the host aliases the dogu deployments carried before the last apply",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			change, err := newHostChange(opts)
+			if err != nil {
+				return err
+			}
+
+			updater, err := change.updater()
+			if err != nil {
+				return err
+			}
+
+			restored, err := updater.RollbackHosts(cmd.Context(), change.namespace)
+			if err != nil {
+				return err
+			}
+
+			result := rollbackResult{Restored: restored}
+			return printResult(cmd.OutOrStdout(), opts.output, result, func(w io.Writer) {
+				if len(restored) == 0 {
+					_, _ = fmt.Fprintln(w, "No host change to roll back")
+					return
+				}
+				_, _ = fmt.Fprintf(w, "Restored host aliases of %d dogu deployments: %v\n", len(restored), restored)
+			})
+		},
+	}
+}
@@ -0,0 +1,194 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	"github.com/cloudogu/k8s-host-change/pkg/hosts"
+	"github.com/cloudogu/k8s-host-change/pkg/logging"
+	"github.com/cloudogu/k8s-host-change/pkg/settings"
+)
+
+const (
+	exitCodeSuccess = 0
+	exitCodeFailure = 1
+	// exitCodePartialFailure signals that some dogu deployments failed and were kept because of the failure policy.
+	exitCodePartialFailure = 2
+	// exitCodeDrift signals that dogu deployments do not carry the expected host aliases.
+	exitCodeDrift = 3
+)
+
+var logger = ctrl.Log.WithName("k8s-host-change")
+
+// globalOptions contains the flags shared by all commands.
+type globalOptions struct {
+	configFile string
+	kubeconfig string
+	context    string
+	namespace  string
+	logLevel   string
+	logFormat  string
+	output     string
+	// qps, burst and requestTimeout tune the client of the api server. Zero keeps the client-go defaults.
+	qps            float32
+	burst          int
+	requestTimeout time.Duration
+	// aliasConfigMap, aliasFile and additionalHosts configure the host alias sources in addition to the global config.
+	aliasConfigMap  string
+	aliasFile       string
+	additionalHosts map[string]string
+	// settings are loaded from the configuration file and the environment and overridden by the flags before any
+	// command runs.
+	settings *settings.Settings
+}
+
+// NewRootCommand creates the command line interface of k8s-host-change.
+func NewRootCommand() *cobra.Command {
+	opts := &globalOptions{}
+	root := &cobra.Command{
+		Use:           "k8s-host-change",
+		Short:         "Updates the host aliases of all dogu deployments from the global config",
+		SilenceUsage:  true,
+		SilenceErrors: true,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			err := validateOutput(opts.output)
+			if err != nil {
+				return err
+			}
+
+			err = opts.loadSettings()
+			if err != nil {
+				return err
+			}
+
+			err = validateSettings(opts.settings)
+			if err != nil {
+				return err
+			}
+
+			return logging.Configure(logging.Options{Level: opts.settings.LogLevel, Format: opts.settings.LogFormat})
+		},
+	}
+
+	flags := root.PersistentFlags()
+	flags.StringVar(&opts.configFile, "config", "", "path to the configuration file; defaults to $"+settings.ConfigFileEnvName)
+	flags.StringVar(&opts.kubeconfig, "kubeconfig", "", "path to the kubeconfig file; $KUBECONFIG or the in-cluster config is used if empty")
+	flags.StringVar(&opts.context, "context", "", "kubeconfig context to use")
+	flags.StringVarP(&opts.namespace, "namespace", "n", "", "namespace of the ecosystem; defaults to $NAMESPACE or 'default'")
+	flags.StringVar(&opts.logLevel, "log-level", "", "log level (error, warn, info, debug); defaults to $LOG_LEVEL")
+	flags.StringVar(&opts.logFormat, "log-format", "", "log format (text, json); defaults to $LOG_FORMAT or 'text'")
+	flags.StringVarP(&opts.output, "output", "o", outputText, "output format (text, json, yaml)")
+	flags.Float32Var(&opts.qps, "qps", 0, "maximum queries per second to the api server; 0 keeps the client default")
+	flags.IntVar(&opts.burst, "burst", 0, "maximum burst of queries to the api server; 0 keeps the client default")
+	flags.DurationVar(&opts.requestTimeout, "request-timeout", 0, "timeout of a single request to the api server; 0 means no timeout")
+	flags.StringVar(&opts.aliasConfigMap, "alias-config-map", "", "read additional host aliases from this config map; defaults to $ALIAS_CONFIG_MAP")
+	flags.StringVar(&opts.aliasFile, "alias-file", "", "read additional host aliases from this file in hosts file syntax; defaults to $ALIAS_FILE")
+	flags.StringToStringVar(&opts.additionalHosts, "additional-host", nil, "additional host alias as host=ip, may be repeated; replaces $ADDITIONAL_HOSTS")
+
+	root.AddCommand(
+		newApplyCommand(opts),
+		newPlanCommand(opts),
+		newPreviewCommand(opts),
+		newVerifyCommand(opts),
+		newRollbackCommand(opts),
+		newExportCommand(opts),
+		newImportCommand(opts),
+		newSetInternalIPCommand(opts),
+		newDisableInternalIPCommand(opts),
+		newAddHostCommand(opts),
+		newRemoveHostCommand(opts),
+		newListHostsCommand(opts),
+		newHistoryCommand(opts),
+		newWebhookCommand(opts),
+	)
+
+	return root
+}
+
+// loadSettings loads the settings with the precedence configuration file < environment < flags.
+func (o *globalOptions) loadSettings() error {
+	path := o.configFile
+	if path == "" {
+		path = os.Getenv(settings.ConfigFileEnvName)
+	}
+
+	cfg, err := settings.Load(path)
+	if err != nil {
+		return err
+	}
+
+	if o.namespace != "" {
+		cfg.Namespace = o.namespace
+	}
+	if o.logLevel != "" {
+		cfg.LogLevel = o.logLevel
+	}
+	if o.logFormat != "" {
+		cfg.LogFormat = o.logFormat
+	}
+	if o.aliasConfigMap != "" {
+		cfg.AliasSources.ConfigMap = o.aliasConfigMap
+	}
+	if o.aliasFile != "" {
+		cfg.AliasSources.File = o.aliasFile
+	}
+	if len(o.additionalHosts) > 0 {
+		cfg.AliasSources.Hosts = o.additionalHosts
+	}
+
+	o.settings = cfg
+	return nil
+}
+
+// validateSettings checks the merged settings with the parse functions of the packages using them.
+func validateSettings(cfg *settings.Settings) error {
+	return cfg.Validate(settings.Validators{
+		FailurePolicy: func(name string) error {
+			_, err := hosts.ParseFailurePolicy(name)
+			return err
+		},
+		Backend: func(name string) error {
+			_, err := hosts.ParseBackendType(name)
+			return err
+		},
+	})
+}
+
+// Execute runs the command line interface with the arguments of the process and returns the exit code.
+// The commands get cancelled on termination signals so that they can clean up within the termination grace period.
+func Execute() int {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGTERM, os.Interrupt)
+	defer stop()
+
+	err := NewRootCommand().ExecuteContext(ctx)
+	if err != nil {
+		logger.Error(err, "exit k8s-host-change")
+	}
+
+	return exitCode(err)
+}
+
+func exitCode(err error) int {
+	if err == nil {
+		return exitCodeSuccess
+	}
+
+	var partialFailure *hosts.PartialFailureError
+	if errors.As(err, &partialFailure) {
+		return exitCodePartialFailure
+	}
+
+	var drift *driftError
+	if errors.As(err, &drift) {
+		return exitCodeDrift
+	}
+
+	return exitCodeFailure
+}
@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"github.com/cloudogu/k8s-host-change/pkg/hosts"
+	"github.com/cloudogu/k8s-host-change/pkg/initializer"
+)
+
+const testNamespace = "ecosystem"
+
+var expectedHostAliases = []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}}}
+
+func TestNewRootCommand(t *testing.T) {
+	t.Run("should contain all subcommands", func(t *testing.T) {
+		// when
+		root := NewRootCommand()
+
+		// then
+		var names []string
+		for _, command := range root.Commands() {
+			names = append(names, command.Name())
+		}
+		assert.Subset(t, names, []string{"apply", "plan", "preview", "verify", "rollback", "export", "import", "history",
+			"set-internal-ip", "disable-internal-ip", "add-host", "remove-host", "list-hosts", "webhook"})
+	})
+	t.Run("should fail on unknown output format", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+
+		// when
+		_, err := execute(t, "export", "--output", "xml")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "unknown output format 'xml'")
+	})
+	t.Run("should fail on invalid log level", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+
+		// when
+		_, err := execute(t, "export", "--log-level", "verbose")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "log level 'verbose' is not valid")
+	})
+	t.Run("should fail on invalid log format", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+
+		// when
+		_, err := execute(t, "export", "--log-format", "xml")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "log format 'xml' is not valid")
+	})
+	t.Run("should pass global flags to the initializer", func(t *testing.T) {
+		// given
+		clientSet := setUpCluster(t)
+		var actual initializer.Options
+		initializer.New = func(opts initializer.Options) initializer.Initializer {
+			actual = opts
+			return &testInitializer{clientSet: clientSet}
+		}
+
+		// when
+		_, err := execute(t, "export", "--kubeconfig", "/tmp/kubeconfig", "--context", "staging", "-n", "ces",
+			"--qps", "50", "--burst", "100", "--request-timeout", "30s")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, initializer.Options{
+			Kubeconfig: "/tmp/kubeconfig",
+			Context:    "staging",
+			Namespace:  "ces",
+			QPS:        50,
+			Burst:      100,
+			Timeout:    30 * time.Second,
+		}, actual)
+	})
+}
+
+func TestNewRootCommand_config(t *testing.T) {
+	const config = `apiVersion: k8s.cloudogu.com/v1
+kind: HostChangeConfiguration
+namespace: from-file
+logLevel: debug
+`
+
+	t.Run("should load namespace from configuration file", func(t *testing.T) {
+		// given
+		t.Setenv("NAMESPACE", "")
+		namespace := captureNamespace(t)
+		path := writeConfig(t, config)
+
+		// when
+		_, err := execute(t, "export", "--config", path)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "from-file", *namespace)
+	})
+	t.Run("should read configuration file path from environment", func(t *testing.T) {
+		// given
+		t.Setenv("NAMESPACE", "")
+		namespace := captureNamespace(t)
+		t.Setenv("CONFIG_FILE", writeConfig(t, config))
+
+		// when
+		_, err := execute(t, "export")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "from-file", *namespace)
+	})
+	t.Run("should override configuration file with environment", func(t *testing.T) {
+		// given
+		t.Setenv("NAMESPACE", "from-env")
+		namespace := captureNamespace(t)
+		path := writeConfig(t, config)
+
+		// when
+		_, err := execute(t, "export", "--config", path)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "from-env", *namespace)
+	})
+	t.Run("should override environment with flags", func(t *testing.T) {
+		// given
+		t.Setenv("NAMESPACE", "from-env")
+		namespace := captureNamespace(t)
+		path := writeConfig(t, config)
+
+		// when
+		_, err := execute(t, "export", "--config", path, "-n", "from-flag")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "from-flag", *namespace)
+	})
+	t.Run("should fail on invalid configuration file", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+		path := writeConfig(t, "apiVersion: v0\nkind: HostChangeConfiguration\n")
+
+		// when
+		_, err := execute(t, "export", "--config", path)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid configuration file")
+	})
+}
+
+func TestNewRootCommand_validateSettings(t *testing.T) {
+	t.Run("should fail on invalid failure policy in configuration file", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+		path := writeConfig(t, "apiVersion: k8s.cloudogu.com/v1\nkind: HostChangeConfiguration\nfailurePolicy: ignore\n")
+
+		// when
+		_, err := execute(t, "export", "--config", path)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid settings: failurePolicy: unknown failure policy 'ignore'")
+	})
+	t.Run("should fail on invalid failure policy in environment", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+		t.Setenv("FAILURE_POLICY", "ignore")
+
+		// when
+		_, err := execute(t, "export")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid settings: failurePolicy: unknown failure policy 'ignore'")
+	})
+	t.Run("should fail on invalid backend in environment", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+		t.Setenv("BACKEND", "etc-hosts")
+
+		// when
+		_, err := execute(t, "export")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid settings: backend: unknown backend 'etc-hosts'")
+	})
+	t.Run("should fail on invalid log format in environment", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+		t.Setenv("LOG_FORMAT", "xml")
+
+		// when
+		_, err := execute(t, "export")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid settings: log format 'xml' is not valid")
+	})
+	t.Run("should fail on invalid failure policy flag", func(t *testing.T) {
+		// given
+		setUpCluster(t)
+
+		// when
+		_, err := execute(t, "apply", "--failure-policy", "ignore")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "invalid settings: failurePolicy: unknown failure policy 'ignore'")
+	})
+}
+
+// captureNamespace sets up a fake cluster and returns the namespace passed to the initializer.
+func captureNamespace(t *testing.T) *string {
+	t.Helper()
+	clientSet := setUpCluster(t)
+	var namespace string
+	initializer.New = func(opts initializer.Options) initializer.Initializer {
+		namespace = opts.Namespace
+		return &testInitializer{clientSet: clientSet}
+	}
+
+	return &namespace
+}
+
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	return path
+}
+
+func Test_exitCode(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{name: "success", err: nil, want: exitCodeSuccess},
+		{name: "failure", err: assert.AnError, want: exitCodeFailure},
+		{name: "partial failure", err: fmt.Errorf("wrapped: %w", &hosts.PartialFailureError{}), want: exitCodePartialFailure},
+		{name: "drift", err: &driftError{deployments: []string{"cas"}}, want: exitCodeDrift},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, exitCode(tt.err))
+		})
+	}
+}
+
+type testInitializer struct {
+	clientSet kubernetes.Interface
+}
+
+func (i *testInitializer) GetNamespace() string {
+	return testNamespace
+}
+
+func (i *testInitializer) RestConfig() (*rest.Config, error) {
+	return &rest.Config{}, nil
+}
+
+func (i *testInitializer) CreateClientSet() (kubernetes.Interface, error) {
+	return i.clientSet, nil
+}
+
+func (i *testInitializer) CreateClient() (client.Client, error) {
+	return nil, nil
+}
+
+const defaultGlobalConfig = `fqdn: ces.example.com
+k8s:
+  use_internal_ip: "true"
+  internal_ip: 10.0.0.1
+`
+
+// setUpCluster replaces the cluster access of all commands with a fake cluster containing the default global config
+// and the given objects.
+func setUpCluster(t *testing.T, objects ...runtime.Object) *fake.Clientset {
+	t.Helper()
+	return setUpClusterWithConfig(t, defaultGlobalConfig, objects...)
+}
+
+// setUpClusterWithConfig works like setUpCluster but uses the given global config.
+func setUpClusterWithConfig(t *testing.T, config string, objects ...runtime.Object) *fake.Clientset {
+	t.Helper()
+	globalConfig := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "global-config", Namespace: testNamespace},
+		Data:       map[string]string{"config.yaml": config},
+	}
+	clientSet := fake.NewSimpleClientset(append(objects, globalConfig)...)
+
+	original := initializer.New
+	t.Cleanup(func() { initializer.New = original })
+	initializer.New = func(initializer.Options) initializer.Initializer {
+		return &testInitializer{clientSet: clientSet}
+	}
+
+	return clientSet
+}
+
+func execute(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	out := &bytes.Buffer{}
+	root := NewRootCommand()
+	root.SetOut(out)
+	root.SetArgs(args)
+
+	err := root.ExecuteContext(context.TODO())
+
+	return out.String(), err
+}
+
+func doguDeployment(name string, aliases []corev1.HostAlias) *appsv1.Deployment {
+	deploy := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: testNamespace,
+			Labels:    map[string]string{"dogu.name": name},
+		},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"dogu.name": name}},
+		},
+	}
+	deploy.Spec.Template.Spec.HostAliases = aliases
+
+	return deploy
+}
@@ -0,0 +1,233 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/k8s-host-change/pkg/hosts"
+	"github.com/cloudogu/k8s-host-change/pkg/hostsfile"
+	"github.com/cloudogu/k8s-host-change/pkg/logging"
+	"github.com/cloudogu/k8s-host-change/pkg/rollout"
+)
+
+// driftError is returned if dogu deployments, their running pods or the CoreDNS config map do not carry the expected
+// host aliases.
+type driftError struct {
+	deployments []string
+	stalePods   []string
+	hostsFiles  []string
+	// configMap is the CoreDNS config map whose server block of the ecosystem drifted.
+	configMap string
+}
+
+func (e *driftError) Error() string {
+	var messages []string
+	if e.configMap != "" {
+		messages = append(messages, fmt.Sprintf("config map %s does not contain the expected server block of the ecosystem", e.configMap))
+	}
+	if len(e.deployments) > 0 {
+		messages = append(messages, fmt.Sprintf("%d dogu deployments do not carry the expected host aliases: %v", len(e.deployments), e.deployments))
+	}
+	if len(e.stalePods) > 0 {
+		messages = append(messages, fmt.Sprintf("%d running pods do not carry the expected host aliases: %v", len(e.stalePods), e.stalePods))
+	}
+	if len(e.hostsFiles) > 0 {
+		messages = append(messages, fmt.Sprintf("hosts files of %d dogu deployments do not contain the expected host aliases: %v", len(e.hostsFiles), e.hostsFiles))
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+type verifyResult struct {
+	Verified int           `json:"verified"`
+	Drifted  []hosts.Drift `json:"drifted"`
+	// StalePods are only verified with the flag --pods.
+	StalePods []rollout.StalePod `json:"stalePods,omitempty"`
+	// HostsFileMismatches are only verified with the flag --hosts-file.
+	HostsFileMismatches []hostsfile.Mismatch `json:"hostsFileMismatches,omitempty"`
+}
+
+// serverBlockResult is the result of verify with the backend coredns.
+type serverBlockResult struct {
+	ConfigMap   string             `json:"configMap"`
+	HostAliases []corev1.HostAlias `json:"hostAliases"`
+	Published   bool               `json:"published"`
+}
+
+func newVerifyCommand(opts *globalOptions) *cobra.Command {
+	var pods bool
+	var hostsFiles bool
+	cmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Check that all dogu deployments carry the host aliases from the global config",
+		Long: fmt.Sprintf("Compares the host aliases of all dogu deployments with the host aliases generated from the global config "+
+			"and prints the missing and unexpected host names of every drifted deployment. Exits with code %d if any deployment "+
+			"drifted, e.g. because a dogu upgrade reset its pod template. Nothing is changed, so the command is suitable for "+
+			"periodic checks. With --pods the running pods of all dogu deployments are checked, too. With --hosts-file the "+
+			"hosts file of a pod of every dogu deployment is read via exec. With the backend coredns, the server block of the "+
+			"ecosystem in the CoreDNS config map is compared instead.", exitCodeDrift),
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			change, err := newHostChange(opts)
+			if err != nil {
+				return err
+			}
+
+			backend, err := hosts.ParseBackendType(change.settings.Backend)
+			if err != nil {
+				return err
+			}
+			if backend == hosts.BackendCoreDNS {
+				if pods || hostsFiles {
+					return fmt.Errorf("--pods and --hosts-file check the host aliases of the dogu deployments, which the backend '%s' does not write", backend)
+				}
+				return verifyServerBlock(cmd, opts.output, change)
+			}
+
+			updater, err := change.updater()
+			if err != nil {
+				return err
+			}
+
+			plan, err := updater.Plan(cmd.Context(), change.namespace)
+			if err != nil {
+				return err
+			}
+
+			result := verifyResult{Verified: len(plan.Deployments), Drifted: plan.Drift()}
+			logger := log.FromContext(cmd.Context())
+			names := make([]string, 0, len(result.Drifted))
+			for _, drift := range result.Drifted {
+				logger.Info("Host aliases drifted", logging.DeploymentKey, drift.Name,
+					"missingHostAliases", drift.MissingHostAliases, "unexpectedHostAliases", drift.UnexpectedHostAliases)
+				names = append(names, drift.Name)
+			}
+
+			var stalePods []string
+			if pods {
+				result.StalePods, err = change.findStalePods(cmd.Context(), plan.HostAliases)
+				if err != nil {
+					return err
+				}
+				for _, pod := range result.StalePods {
+					stalePods = append(stalePods, pod.Name)
+				}
+			}
+
+			var mismatchedDeployments []string
+			if hostsFiles {
+				result.HostsFileMismatches, err = change.verifyHostsFiles(cmd.Context(), plan.HostAliases)
+				if err != nil {
+					return err
+				}
+				for _, mismatch := range result.HostsFileMismatches {
+					mismatchedDeployments = append(mismatchedDeployments, mismatch.Deployment)
+				}
+			}
+
+			err = printResult(cmd.OutOrStdout(), opts.output, result, func(w io.Writer) { printVerifyResult(w, result) })
+			if err != nil {
+				return err
+			}
+
+			if len(names) > 0 || len(stalePods) > 0 || len(mismatchedDeployments) > 0 {
+				return &driftError{deployments: names, stalePods: stalePods, hostsFiles: mismatchedDeployments}
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().BoolVar(&pods, "pods", false, "also check that the running pods carry the expected host aliases")
+	cmd.Flags().BoolVar(&hostsFiles, "hosts-file", false, "also read /etc/hosts of a pod of every dogu deployment via exec and compare it with the expected host aliases")
+
+	return cmd
+}
+
+// verifyServerBlock checks that the CoreDNS config map contains the server block for the host aliases generated from
+// the global config.
+func verifyServerBlock(cmd *cobra.Command, output string, change *hostChange) error {
+	hostAliases, err := change.generator.Generate(cmd.Context())
+	if err != nil {
+		return fmt.Errorf("failed to generate host aliases: %w", err)
+	}
+
+	publisher := change.publisher()
+	published, err := publisher.IsPublished(cmd.Context(), change.namespace, hostAliases)
+	if err != nil {
+		return err
+	}
+
+	result := serverBlockResult{ConfigMap: publisher.ConfigMap(), HostAliases: hostAliases, Published: published}
+	err = printResult(cmd.OutOrStdout(), output, result, func(w io.Writer) { printServerBlockResult(w, result) })
+	if err != nil {
+		return err
+	}
+
+	if !published {
+		return &driftError{configMap: result.ConfigMap}
+	}
+
+	return nil
+}
+
+func printServerBlockResult(w io.Writer, result serverBlockResult) {
+	if result.Published {
+		_, _ = fmt.Fprintf(w, "Config map %s contains the expected server block with %d host aliases\n", result.ConfigMap, len(result.HostAliases))
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "Config map %s does not contain the expected server block; run apply to publish these host aliases:\n", result.ConfigMap)
+	for _, alias := range result.HostAliases {
+		_, _ = fmt.Fprintf(w, "  %s %s\n", alias.IP, strings.Join(alias.Hostnames, " "))
+	}
+}
+
+func printVerifyResult(w io.Writer, result verifyResult) {
+	defer printHostsFileMismatches(w, result.HostsFileMismatches)
+	defer printStalePods(w, result.StalePods)
+	if len(result.Drifted) == 0 {
+		_, _ = fmt.Fprintf(w, "All %d dogu deployments carry the expected host aliases\n", result.Verified)
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "%d of %d dogu deployments drifted:\n", len(result.Drifted), result.Verified)
+	for _, drift := range result.Drifted {
+		_, _ = fmt.Fprintf(w, "  %s\n", drift.Name)
+		printDriftedHostAliases(w, "missing", drift.MissingHostAliases)
+		printDriftedHostAliases(w, "unexpected", drift.UnexpectedHostAliases)
+	}
+}
+
+func printStalePods(w io.Writer, stalePods []rollout.StalePod) {
+	if len(stalePods) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "%d running pods do not carry the expected host aliases:\n", len(stalePods))
+	for _, pod := range stalePods {
+		_, _ = fmt.Fprintf(w, "  %s (deployment %s)\n", pod.Name, pod.Deployment)
+	}
+}
+
+func printHostsFileMismatches(w io.Writer, mismatches []hostsfile.Mismatch) {
+	if len(mismatches) == 0 {
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "Hosts files of %d dogu deployments do not contain the expected host aliases:\n", len(mismatches))
+	for _, mismatch := range mismatches {
+		_, _ = fmt.Fprintf(w, "  %s: %s\n", mismatch.Deployment, mismatch)
+	}
+}
+
+func printDriftedHostAliases(w io.Writer, kind string, aliases []corev1.HostAlias) {
+	for _, alias := range aliases {
+		_, _ = fmt.Fprintf(w, "    %s: %s %s\n", kind, alias.IP, strings.Join(alias.Hostnames, " "))
+	}
+}
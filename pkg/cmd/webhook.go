@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/k8s-host-change/pkg/deployment"
+	"github.com/cloudogu/k8s-host-change/pkg/hosts"
+	"github.com/cloudogu/k8s-host-change/pkg/webhook"
+)
+
+func newWebhookCommand(opts *globalOptions) *cobra.Command {
+	var port int
+	var certDir string
+	var failOpen bool
+	cmd := &cobra.Command{
+		Use:   "webhook",
+		Short: "Serve the admission webhooks for dogu deployments and the global config",
+		Long: "Serves a mutating admission webhook at " + webhook.MutateDeploymentPath + " which sets the host aliases generated " +
+			"from the global config in dogu deployments when they are created or updated, so that newly installed or upgraded " +
+			"dogus do not need another host change. Writes with the field manager '" + deployment.FieldManager + "' of the host " +
+			"change are admitted unchanged, so that it can still roll back host aliases. At " +
+			webhook.ValidateGlobalConfigPath + " it serves a validating admission webhook which rejects writes of the global " +
+			"config with invalid host keys. With the backend coredns only the validating admission webhook is served because " +
+			"host aliases in the dogu deployments would take precedence over the CoreDNS configuration.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			change, err := newHostChange(opts)
+			if err != nil {
+				return err
+			}
+			cfg := &change.settings.Webhook
+			if cmd.Flags().Changed("port") {
+				cfg.Port = port
+			}
+			if cmd.Flags().Changed("cert-dir") {
+				cfg.CertDir = certDir
+			}
+			if cmd.Flags().Changed("fail-open") {
+				cfg.FailOpen = failOpen
+			}
+
+			backend, err := hosts.ParseBackendType(change.settings.Backend)
+			if err != nil {
+				return err
+			}
+
+			ctx := cmd.Context()
+			injectorOpts := webhook.InjectorOptions{Namespace: change.namespace, FailOpen: cfg.FailOpen}
+
+			log.FromContext(ctx).Info("Start webhook server", "port", cfg.Port, "failOpen", cfg.FailOpen, "backend", backend)
+			var injector *webhook.HostAliasInjector
+			if backend == hosts.BackendHostAliases {
+				injector = webhook.NewHostAliasInjector(change.generator, injectorOpts)
+			}
+			validator := webhook.NewGlobalConfigValidator(change.namespace)
+			server := webhook.NewServer(injector, validator, webhook.ServerOptions{Port: cfg.Port, CertDir: cfg.CertDir})
+			err = server.Start(ctx)
+			if err != nil {
+				return fmt.Errorf("failed to serve webhook: %w", err)
+			}
+
+			return nil
+		},
+	}
+
+	cmd.Flags().IntVar(&port, "port", 0, "port of the webhook server; defaults to $WEBHOOK_PORT or 9443")
+	cmd.Flags().StringVar(&certDir, "cert-dir", "", "directory containing the serving certificate tls.crt and key tls.key; defaults to $WEBHOOK_CERT_DIR")
+	cmd.Flags().BoolVar(&failOpen, "fail-open", false, "admit dogu deployments without host aliases if they cannot be generated instead of rejecting them")
+
+	return cmd
+}
@@ -0,0 +1,160 @@
+package coredns
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"slices"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+)
+
+const (
+	// DefaultNamespace is the namespace CoreDNS runs in by default.
+	DefaultNamespace = "kube-system"
+	// DefaultConfigMap is the config map whose server blocks the CoreDNS of k3s imports by default.
+	DefaultConfigMap = "coredns-custom"
+)
+
+// Options configures the config map the host aliases are published to.
+type Options struct {
+	// Namespace is the namespace of the config map. Defaults to DefaultNamespace.
+	Namespace string
+	// ConfigMap is the name of the config map CoreDNS imports server blocks from. Defaults to DefaultConfigMap.
+	ConfigMap string
+}
+
+// Publisher writes the host aliases of an ecosystem as server block into the config map CoreDNS imports. Every
+// ecosystem owns its own entry of the config map, so the entries of other ecosystems and administrators are kept.
+type Publisher struct {
+	clientSet kubernetes.Interface
+	namespace string
+	configMap string
+}
+
+// NewPublisher creates a new instance of Publisher.
+func NewPublisher(clientSet kubernetes.Interface, opts Options) *Publisher {
+	if opts.Namespace == "" {
+		opts.Namespace = DefaultNamespace
+	}
+	if opts.ConfigMap == "" {
+		opts.ConfigMap = DefaultConfigMap
+	}
+
+	return &Publisher{clientSet: clientSet, namespace: opts.Namespace, configMap: opts.ConfigMap}
+}
+
+// ConfigMap returns the namespace and the name of the config map the host aliases are published to.
+func (p *Publisher) ConfigMap() string {
+	return fmt.Sprintf("%s/%s", p.namespace, p.configMap)
+}
+
+// Publish writes the server block for the given host aliases of the ecosystem in the given namespace. Without host
+// aliases the server block is removed. The config map is created if it does not exist and fetched again on conflicts.
+// It is not updated if it already contains the server block. Publish fails without writing if another ecosystem
+// already publishes one of the host names, because CoreDNS refuses to load duplicate zones for the whole cluster.
+func (p *Publisher) Publish(ctx context.Context, namespace string, hostAliases []corev1.HostAlias) error {
+	key := Key(namespace)
+	serverBlock := expectedServerBlock(namespace, hostAliases)
+
+	err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMaps := p.clientSet.CoreV1().ConfigMaps(p.namespace)
+		configMap, err := configMaps.Get(ctx, p.configMap, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			if serverBlock == "" {
+				return nil
+			}
+
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{Name: p.configMap, Namespace: p.namespace},
+				Data:       map[string]string{key: serverBlock},
+			}
+			_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// let the retry fetch the config map created in the meantime
+				return apierrors.NewConflict(corev1.Resource("configmaps"), p.configMap, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		err = checkOverlappingZones(configMap, namespace, hostAliases)
+		if err != nil {
+			return err
+		}
+
+		if configMap.Data[key] == serverBlock {
+			return nil
+		}
+		if serverBlock == "" {
+			delete(configMap.Data, key)
+		} else {
+			if configMap.Data == nil {
+				configMap.Data = map[string]string{}
+			}
+			configMap.Data[key] = serverBlock
+		}
+
+		_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to publish host aliases to config map '%s': %w", p.ConfigMap(), err)
+	}
+
+	return nil
+}
+
+// IsPublished returns true if the config map contains the server block for the given host aliases of the ecosystem in
+// the given namespace. Without host aliases, the config map must not contain a server block of the ecosystem.
+func (p *Publisher) IsPublished(ctx context.Context, namespace string, hostAliases []corev1.HostAlias) (bool, error) {
+	serverBlock := expectedServerBlock(namespace, hostAliases)
+	configMap, err := p.clientSet.CoreV1().ConfigMaps(p.namespace).Get(ctx, p.configMap, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return serverBlock == "", nil
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to get config map '%s': %w", p.ConfigMap(), err)
+	}
+
+	return configMap.Data[Key(namespace)] == serverBlock, nil
+}
+
+// expectedServerBlock renders the server block for the given host aliases. It is empty without host aliases.
+func expectedServerBlock(namespace string, hostAliases []corev1.HostAlias) string {
+	if len(hostAliases) == 0 {
+		return ""
+	}
+
+	return RenderServerBlock(namespace, hostAliases)
+}
+
+// checkOverlappingZones fails if the server block of another ecosystem in the given config map contains a host name of
+// the given host aliases.
+func checkOverlappingZones(configMap *corev1.ConfigMap, namespace string, hostAliases []corev1.HostAlias) error {
+	zones := Zones(hostAliases)
+	for _, key := range slices.Sorted(maps.Keys(configMap.Data)) {
+		otherNamespace, managed := namespaceOfKey(key)
+		if !managed || otherNamespace == namespace {
+			continue
+		}
+
+		var overlapping []string
+		for _, zone := range parseZones(configMap.Data[key]) {
+			if slices.Contains(zones, zone) {
+				overlapping = append(overlapping, zone)
+			}
+		}
+		if len(overlapping) > 0 {
+			return fmt.Errorf("host names %v are already published by the ecosystem in namespace '%s'", overlapping, otherNamespace)
+		}
+	}
+
+	return nil
+}
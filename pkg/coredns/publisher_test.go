@@ -0,0 +1,236 @@
+package coredns
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const testNamespace = "ecosystem"
+
+var hostAliases = []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}}}
+
+func corednsCustom(data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: DefaultConfigMap, Namespace: DefaultNamespace},
+		Data:       data,
+	}
+}
+
+func getCorednsCustom(t *testing.T, clientSet *fake.Clientset) *corev1.ConfigMap {
+	t.Helper()
+	configMap, err := clientSet.CoreV1().ConfigMaps(DefaultNamespace).Get(context.TODO(), DefaultConfigMap, metav1.GetOptions{})
+	require.NoError(t, err)
+
+	return configMap
+}
+
+func TestPublisher_Publish(t *testing.T) {
+	t.Run("should create config map with server block", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		sut := NewPublisher(clientSet, Options{})
+
+		// when
+		err := sut.Publish(context.TODO(), testNamespace, hostAliases)
+
+		// then
+		require.NoError(t, err)
+		configMap := getCorednsCustom(t, clientSet)
+		assert.Equal(t, map[string]string{Key(testNamespace): RenderServerBlock(testNamespace, hostAliases)}, configMap.Data)
+	})
+	t.Run("should keep entries of other ecosystems", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(corednsCustom(map[string]string{"other.server": "other {}\n"}))
+		sut := NewPublisher(clientSet, Options{})
+
+		// when
+		err := sut.Publish(context.TODO(), testNamespace, hostAliases)
+
+		// then
+		require.NoError(t, err)
+		configMap := getCorednsCustom(t, clientSet)
+		assert.Equal(t, "other {}\n", configMap.Data["other.server"])
+		assert.Equal(t, RenderServerBlock(testNamespace, hostAliases), configMap.Data[Key(testNamespace)])
+	})
+	t.Run("should keep server blocks of other ecosystems with other host names", func(t *testing.T) {
+		// given
+		otherHostAliases := []corev1.HostAlias{{IP: "10.0.1.1", Hostnames: []string{"other.example.com"}}}
+		clientSet := fake.NewSimpleClientset(corednsCustom(map[string]string{Key("other"): RenderServerBlock("other", otherHostAliases)}))
+		sut := NewPublisher(clientSet, Options{})
+
+		// when
+		err := sut.Publish(context.TODO(), testNamespace, hostAliases)
+
+		// then
+		require.NoError(t, err)
+		configMap := getCorednsCustom(t, clientSet)
+		assert.Len(t, configMap.Data, 2)
+	})
+	t.Run("should fail on host names published by another ecosystem", func(t *testing.T) {
+		// given
+		otherHostAliases := []corev1.HostAlias{
+			{IP: "10.0.1.1", Hostnames: []string{"other.example.com"}},
+			{IP: "10.0.1.2", Hostnames: []string{"ces.example.com"}},
+		}
+		otherServerBlock := RenderServerBlock("other", otherHostAliases)
+		clientSet := fake.NewSimpleClientset(corednsCustom(map[string]string{Key("other"): otherServerBlock}))
+		sut := NewPublisher(clientSet, Options{})
+
+		// when
+		err := sut.Publish(context.TODO(), testNamespace, hostAliases)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "host names [ces.example.com] are already published by the ecosystem in namespace 'other'")
+		configMap := getCorednsCustom(t, clientSet)
+		assert.Equal(t, map[string]string{Key("other"): otherServerBlock}, configMap.Data)
+	})
+	t.Run("should remove server block without host aliases", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(corednsCustom(map[string]string{
+			"other.server":     "other {}\n",
+			Key(testNamespace): RenderServerBlock(testNamespace, hostAliases),
+		}))
+		sut := NewPublisher(clientSet, Options{})
+
+		// when
+		err := sut.Publish(context.TODO(), testNamespace, nil)
+
+		// then
+		require.NoError(t, err)
+		configMap := getCorednsCustom(t, clientSet)
+		assert.Equal(t, map[string]string{"other.server": "other {}\n"}, configMap.Data)
+	})
+	t.Run("should not create config map without host aliases", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		sut := NewPublisher(clientSet, Options{})
+
+		// when
+		err := sut.Publish(context.TODO(), testNamespace, nil)
+
+		// then
+		require.NoError(t, err)
+		configMaps, err := clientSet.CoreV1().ConfigMaps(DefaultNamespace).List(context.TODO(), metav1.ListOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, configMaps.Items)
+	})
+	t.Run("should not update config map which contains the server block", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(corednsCustom(map[string]string{Key(testNamespace): RenderServerBlock(testNamespace, hostAliases)}))
+		clientSet.PrependReactor("update", "configmaps", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, assert.AnError
+		})
+		sut := NewPublisher(clientSet, Options{})
+
+		// when
+		err := sut.Publish(context.TODO(), testNamespace, hostAliases)
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should use configured config map", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		sut := NewPublisher(clientSet, Options{Namespace: "dns", ConfigMap: "coredns-import"})
+
+		// when
+		err := sut.Publish(context.TODO(), testNamespace, hostAliases)
+
+		// then
+		require.NoError(t, err)
+		_, err = clientSet.CoreV1().ConfigMaps("dns").Get(context.TODO(), "coredns-import", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, "dns/coredns-import", sut.ConfigMap())
+	})
+	t.Run("should fail to update config map", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(corednsCustom(nil))
+		clientSet.PrependReactor("update", "configmaps", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, assert.AnError
+		})
+		sut := NewPublisher(clientSet, Options{})
+
+		// when
+		err := sut.Publish(context.TODO(), testNamespace, hostAliases)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to publish host aliases to config map 'kube-system/coredns-custom'")
+	})
+}
+
+func TestPublisher_IsPublished(t *testing.T) {
+	t.Run("should be published if the config map contains the server block", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(corednsCustom(map[string]string{Key(testNamespace): RenderServerBlock(testNamespace, hostAliases)}))
+		sut := NewPublisher(clientSet, Options{})
+
+		// when
+		actual, err := sut.IsPublished(context.TODO(), testNamespace, hostAliases)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, actual)
+	})
+	t.Run("should not be published if the server block differs", func(t *testing.T) {
+		// given
+		outdated := []corev1.HostAlias{{IP: "10.0.0.9", Hostnames: []string{"ces.example.com"}}}
+		clientSet := fake.NewSimpleClientset(corednsCustom(map[string]string{Key(testNamespace): RenderServerBlock(testNamespace, outdated)}))
+		sut := NewPublisher(clientSet, Options{})
+
+		// when
+		actual, err := sut.IsPublished(context.TODO(), testNamespace, hostAliases)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, actual)
+	})
+	t.Run("should not be published if the config map does not exist", func(t *testing.T) {
+		// given
+		sut := NewPublisher(fake.NewSimpleClientset(), Options{})
+
+		// when
+		actual, err := sut.IsPublished(context.TODO(), testNamespace, hostAliases)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, actual)
+	})
+	t.Run("should be published without host aliases and server block", func(t *testing.T) {
+		// given
+		sut := NewPublisher(fake.NewSimpleClientset(corednsCustom(map[string]string{"other.server": "other {}\n"})), Options{})
+
+		// when
+		actual, err := sut.IsPublished(context.TODO(), testNamespace, nil)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, actual)
+	})
+	t.Run("should fail to get config map", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		clientSet.PrependReactor("get", "configmaps", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, assert.AnError
+		})
+		sut := NewPublisher(clientSet, Options{})
+
+		// when
+		_, err := sut.IsPublished(context.TODO(), testNamespace, hostAliases)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to get config map 'kube-system/coredns-custom'")
+	})
+}
@@ -0,0 +1,83 @@
+package coredns
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	keyPrefix = "k8s-host-change-"
+	keySuffix = ".server"
+)
+
+// Key returns the key of the config map entry containing the server block of the ecosystem in the given namespace.
+// CoreDNS imports every entry ending with .server as additional server block.
+func Key(namespace string) string {
+	return keyPrefix + namespace + keySuffix
+}
+
+// namespaceOfKey returns the namespace of the ecosystem whose server block is stored at the given key. It returns false
+// if the key was not written by Key.
+func namespaceOfKey(key string) (string, bool) {
+	namespace, found := strings.CutPrefix(key, keyPrefix)
+	if !found {
+		return "", false
+	}
+
+	return strings.CutSuffix(namespace, keySuffix)
+}
+
+// RenderServerBlock returns a CoreDNS server block which resolves the host names of the given host aliases of the
+// ecosystem in the given namespace. The server block is only responsible for these host names, so other names are
+// resolved as before. Queries for other record types and sub domains of the host names are forwarded. CoreDNS serves
+// the server block to all pods of the cluster, not only to the dogus of the ecosystem.
+func RenderServerBlock(namespace string, hostAliases []corev1.HostAlias) string {
+	zones := Zones(hostAliases)
+
+	var b strings.Builder
+	_, _ = fmt.Fprintf(&b, "# Host aliases of the ecosystem in namespace '%s' managed by k8s-host-change.\n", namespace)
+	_, _ = fmt.Fprintf(&b, "%s {\n", strings.Join(zones, " "))
+	b.WriteString("    hosts {\n")
+	for _, alias := range hostAliases {
+		_, _ = fmt.Fprintf(&b, "        %s %s\n", alias.IP, strings.Join(alias.Hostnames, " "))
+	}
+	b.WriteString("        fallthrough\n")
+	b.WriteString("    }\n")
+	b.WriteString("    forward . /etc/resolv.conf\n")
+	b.WriteString("    reload\n")
+	b.WriteString("}\n")
+
+	return b.String()
+}
+
+// Zones returns the distinct host names of the given host aliases which RenderServerBlock uses as zones of the server
+// block.
+func Zones(hostAliases []corev1.HostAlias) []string {
+	var zones []string
+	for _, alias := range hostAliases {
+		for _, hostname := range alias.Hostnames {
+			if !slices.Contains(zones, hostname) {
+				zones = append(zones, hostname)
+			}
+		}
+	}
+
+	return zones
+}
+
+// parseZones returns the zones of a server block rendered by RenderServerBlock.
+func parseZones(serverBlock string) []string {
+	for _, line := range strings.Split(serverBlock, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		return strings.Fields(strings.TrimSuffix(line, "{"))
+	}
+
+	return nil
+}
@@ -0,0 +1,49 @@
+package coredns
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestKey(t *testing.T) {
+	assert.Equal(t, "k8s-host-change-ecosystem.server", Key("ecosystem"))
+}
+
+func TestRenderServerBlock(t *testing.T) {
+	t.Run("should render hosts of all host aliases", func(t *testing.T) {
+		// given
+		hostAliases := []corev1.HostAlias{
+			{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}},
+			{IP: "10.0.0.2", Hostnames: []string{"git.example.com", "ces.example.com"}},
+		}
+
+		// when
+		actual := RenderServerBlock("ecosystem", hostAliases)
+
+		// then
+		expected := `# Host aliases of the ecosystem in namespace 'ecosystem' managed by k8s-host-change.
+ces.example.com git.example.com {
+    hosts {
+        10.0.0.1 ces.example.com
+        10.0.0.2 git.example.com ces.example.com
+        fallthrough
+    }
+    forward . /etc/resolv.conf
+    reload
+}
+`
+		assert.Equal(t, expected, actual)
+		assert.Equal(t, []string{"ces.example.com", "git.example.com"}, parseZones(actual))
+	})
+}
+
+func Test_namespaceOfKey(t *testing.T) {
+	namespace, managed := namespaceOfKey(Key("ecosystem"))
+	assert.True(t, managed)
+	assert.Equal(t, "ecosystem", namespace)
+
+	_, managed = namespaceOfKey("custom.server")
+	assert.False(t, managed)
+}
@@ -0,0 +1,52 @@
+package deployment
+
+import (
+	"slices"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// SameHostAliases compares the given host aliases regardless of the order of the aliases and their host names.
+func SameHostAliases(a []corev1.HostAlias, b []corev1.HostAlias) bool {
+	return slices.Equal(hostAliasKeys(a), hostAliasKeys(b))
+}
+
+func hostAliasKeys(aliases []corev1.HostAlias) []string {
+	keys := make([]string, 0, len(aliases))
+	for _, alias := range aliases {
+		hostnames := slices.Clone(alias.Hostnames)
+		slices.Sort(hostnames)
+		keys = append(keys, alias.IP+" "+strings.Join(hostnames, " "))
+	}
+	slices.Sort(keys)
+
+	return keys
+}
+
+// SubtractHostAliases returns the host names of a which are not mapped to the same ip in b, grouped by their ip.
+func SubtractHostAliases(a []corev1.HostAlias, b []corev1.HostAlias) []corev1.HostAlias {
+	existing := map[string]bool{}
+	for _, alias := range b {
+		for _, hostname := range alias.Hostnames {
+			existing[alias.IP+" "+hostname] = true
+		}
+	}
+
+	var result []corev1.HostAlias
+	for _, alias := range a {
+		for _, hostname := range alias.Hostnames {
+			if existing[alias.IP+" "+hostname] {
+				continue
+			}
+			i := slices.IndexFunc(result, func(r corev1.HostAlias) bool { return r.IP == alias.IP })
+			if i < 0 {
+				result = append(result, corev1.HostAlias{IP: alias.IP})
+				i = len(result) - 1
+			}
+			result[i].Hostnames = append(result[i].Hostnames, hostname)
+		}
+	}
+
+	return result
+}
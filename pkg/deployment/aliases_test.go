@@ -0,0 +1,38 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestSameHostAliases(t *testing.T) {
+	a := []corev1.HostAlias{
+		{IP: "10.0.0.1", Hostnames: []string{"a.example.com", "b.example.com"}},
+		{IP: "10.0.0.2", Hostnames: []string{"c.example.com"}},
+	}
+	b := []corev1.HostAlias{
+		{IP: "10.0.0.2", Hostnames: []string{"c.example.com"}},
+		{IP: "10.0.0.1", Hostnames: []string{"b.example.com", "a.example.com"}},
+	}
+
+	assert.True(t, SameHostAliases(a, b))
+	assert.True(t, SameHostAliases(nil, []corev1.HostAlias{}))
+	assert.False(t, SameHostAliases(a, b[:1]))
+}
+
+func TestSubtractHostAliases(t *testing.T) {
+	a := []corev1.HostAlias{
+		{IP: "10.0.0.1", Hostnames: []string{"a.example.com", "b.example.com"}},
+		{IP: "10.0.0.2", Hostnames: []string{"c.example.com"}},
+	}
+	b := []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"a.example.com"}}}
+
+	assert.Equal(t, []corev1.HostAlias{
+		{IP: "10.0.0.1", Hostnames: []string{"b.example.com"}},
+		{IP: "10.0.0.2", Hostnames: []string{"c.example.com"}},
+	}, SubtractHostAliases(a, b))
+	assert.Nil(t, SubtractHostAliases(b, a))
+}
@@ -0,0 +1,75 @@
+package deployment
+
+import (
+	"encoding/json"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// PreviousHostAliasesAnnotation holds the host aliases a deployment carried before the last host change.
+// It is used to roll back the last host change.
+const PreviousHostAliasesAnnotation = "k8s.cloudogu.com/previous-host-aliases"
+
+// PreviousHostAliases returns the host aliases the deployment carried before the last host change.
+// The second return value is false if no host change was recorded.
+func PreviousHostAliases(deployment *appsv1.Deployment) ([]corev1.HostAlias, bool, error) {
+	raw, ok := deployment.Annotations[PreviousHostAliasesAnnotation]
+	if !ok {
+		return nil, false, nil
+	}
+
+	var aliases []corev1.HostAlias
+	err := json.Unmarshal([]byte(raw), &aliases)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to parse annotation '%s' of deployment '%s': %w", PreviousHostAliasesAnnotation, deployment.Name, err)
+	}
+
+	return aliases, true, nil
+}
+
+// recordPreviousHostAliases stores the current host aliases of the deployment in the PreviousHostAliasesAnnotation
+// before they get replaced with the given aliases. The annotation is left alone if the aliases do not change, so that
+// re-applying the same host aliases keeps the host aliases to roll back to.
+func recordPreviousHostAliases(deployment *appsv1.Deployment, aliases []corev1.HostAlias) error {
+	if SameHostAliases(deployment.Spec.Template.Spec.HostAliases, aliases) {
+		return nil
+	}
+
+	current, err := marshalAliases(deployment.Spec.Template.Spec.HostAliases)
+	if err != nil {
+		return err
+	}
+
+	if deployment.Annotations == nil {
+		deployment.Annotations = map[string]string{}
+	}
+	deployment.Annotations[PreviousHostAliasesAnnotation] = current
+
+	return nil
+}
+
+// removePreviousHostAliases removes the PreviousHostAliasesAnnotation when the deployment gets the given previous host
+// aliases back, so that a rollback cannot be repeated. The annotation is left alone if the aliases do not change.
+func removePreviousHostAliases(deployment *appsv1.Deployment, aliases []corev1.HostAlias) error {
+	if !SameHostAliases(deployment.Spec.Template.Spec.HostAliases, aliases) {
+		delete(deployment.Annotations, PreviousHostAliasesAnnotation)
+	}
+
+	return nil
+}
+
+// marshalAliases treats nil and empty host aliases equally.
+func marshalAliases(aliases []corev1.HostAlias) (string, error) {
+	if len(aliases) == 0 {
+		return "[]", nil
+	}
+
+	raw, err := json.Marshal(aliases)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal host aliases: %w", err)
+	}
+
+	return string(raw), nil
+}
@@ -0,0 +1,135 @@
+package deployment
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var (
+	oldAliases = []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}}}
+	newAliases = []corev1.HostAlias{{IP: "10.0.0.2", Hostnames: []string{"ces.example.com"}}}
+)
+
+func deploymentWithAliases(aliases []corev1.HostAlias, annotations map[string]string) *appsv1.Deployment {
+	deploy := &appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "cas", Annotations: annotations}}
+	deploy.Spec.Template.Spec.HostAliases = aliases
+	return deploy
+}
+
+func Test_recordPreviousHostAliases(t *testing.T) {
+	t.Run("should record replaced aliases", func(t *testing.T) {
+		// given
+		deploy := deploymentWithAliases(oldAliases, nil)
+
+		// when
+		err := recordPreviousHostAliases(deploy, newAliases)
+
+		// then
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"ip":"10.0.0.1","hostnames":["ces.example.com"]}]`, deploy.Annotations[PreviousHostAliasesAnnotation])
+	})
+	t.Run("should record empty aliases", func(t *testing.T) {
+		// given
+		deploy := deploymentWithAliases(nil, map[string]string{"other": "value"})
+
+		// when
+		err := recordPreviousHostAliases(deploy, newAliases)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "[]", deploy.Annotations[PreviousHostAliasesAnnotation])
+		assert.Equal(t, "value", deploy.Annotations["other"])
+	})
+	t.Run("should keep annotation if aliases do not change", func(t *testing.T) {
+		// given
+		deploy := deploymentWithAliases(newAliases, map[string]string{PreviousHostAliasesAnnotation: "[]"})
+
+		// when
+		err := recordPreviousHostAliases(deploy, newAliases)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "[]", deploy.Annotations[PreviousHostAliasesAnnotation])
+	})
+	t.Run("should record replaced aliases when previous aliases are applied again", func(t *testing.T) {
+		// given
+		previous, err := marshalAliases(oldAliases)
+		require.NoError(t, err)
+		deploy := deploymentWithAliases(newAliases, map[string]string{PreviousHostAliasesAnnotation: previous})
+
+		// when
+		err = recordPreviousHostAliases(deploy, oldAliases)
+
+		// then
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"ip":"10.0.0.2","hostnames":["ces.example.com"]}]`, deploy.Annotations[PreviousHostAliasesAnnotation])
+	})
+}
+
+func Test_removePreviousHostAliases(t *testing.T) {
+	t.Run("should remove annotation if previous aliases are restored", func(t *testing.T) {
+		// given
+		deploy := deploymentWithAliases(newAliases, map[string]string{PreviousHostAliasesAnnotation: "[]", "other": "value"})
+
+		// when
+		err := removePreviousHostAliases(deploy, oldAliases)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"other": "value"}, deploy.Annotations)
+	})
+	t.Run("should keep annotation if aliases do not change", func(t *testing.T) {
+		// given
+		deploy := deploymentWithAliases(newAliases, map[string]string{PreviousHostAliasesAnnotation: "[]"})
+
+		// when
+		err := removePreviousHostAliases(deploy, newAliases)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "[]", deploy.Annotations[PreviousHostAliasesAnnotation])
+	})
+}
+
+func TestPreviousHostAliases(t *testing.T) {
+	t.Run("should return recorded aliases", func(t *testing.T) {
+		// given
+		deploy := deploymentWithAliases(newAliases, map[string]string{
+			PreviousHostAliasesAnnotation: `[{"ip":"10.0.0.1","hostnames":["ces.example.com"]}]`,
+		})
+
+		// when
+		actual, found, err := PreviousHostAliases(deploy)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, found)
+		assert.Equal(t, oldAliases, actual)
+	})
+	t.Run("should return nothing without annotation", func(t *testing.T) {
+		// when
+		actual, found, err := PreviousHostAliases(deploymentWithAliases(newAliases, nil))
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, found)
+		assert.Nil(t, actual)
+	})
+	t.Run("should fail on invalid annotation", func(t *testing.T) {
+		// given
+		deploy := deploymentWithAliases(newAliases, map[string]string{PreviousHostAliasesAnnotation: "{"})
+
+		// when
+		_, _, err := PreviousHostAliases(deploy)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to parse annotation 'k8s.cloudogu.com/previous-host-aliases' of deployment 'cas'")
+	})
+}
@@ -12,29 +12,81 @@ import (
 	"k8s.io/client-go/util/retry"
 )
 
-type updater struct {
+// FieldManager is the field manager of all writes of the Updater. The admission webhook admits writes of this field
+// manager unchanged, so that it does not replace host aliases which the host change rolls back.
+const FieldManager = "k8s-host-change"
+
+// UpdateError is the error of the update of a single deployment.
+type UpdateError struct {
+	// Deployment is the name of the deployment which was not updated.
+	Deployment string
+	// Skipped is true if the deployment was not updated because the context was done.
+	Skipped bool
+	Err     error
+}
+
+// Error returns the message of the underlying error.
+func (e *UpdateError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *UpdateError) Unwrap() error {
+	return e.Err
+}
+
+// Updater writes host aliases into dogu deployments.
+type Updater struct {
 	clientSet kubernetes.Interface
 }
 
-// NewUpdater creates a new instance of updater.
-func NewUpdater(clientSet kubernetes.Interface) *updater {
-	return &updater{clientSet: clientSet}
+// NewUpdater creates a new instance of Updater.
+func NewUpdater(clientSet kubernetes.Interface) *Updater {
+	return &Updater{clientSet: clientSet}
 }
 
 // UpdateHostAliases replaces the host aliases in the given deployments.
 // Every deployment will be fetched again from the api with a retry mechanism to prevent
-// conflict api errors.
-func (u *updater) UpdateHostAliases(ctx context.Context, namespace string, deployments []appsv1.Deployment, aliases []corev1.HostAlias) error {
+// conflict api errors. No further deployment is updated once the context is done.
+// The replaced host aliases are recorded in the PreviousHostAliasesAnnotation of every deployment. All writes use the
+// FieldManager. The error of every deployment which was not updated is an *UpdateError.
+func (u *Updater) UpdateHostAliases(ctx context.Context, namespace string, deployments []appsv1.Deployment, aliases []corev1.HostAlias) error {
+	return u.writeHostAliases(ctx, namespace, deployments, aliases, recordPreviousHostAliases)
+}
+
+// RestoreHostAliases works like UpdateHostAliases but gives the deployments their previous host aliases back. Instead
+// of recording the replaced host aliases, it removes the PreviousHostAliasesAnnotation so that a rollback cannot be
+// repeated.
+func (u *Updater) RestoreHostAliases(ctx context.Context, namespace string, deployments []appsv1.Deployment, aliases []corev1.HostAlias) error {
+	return u.writeHostAliases(ctx, namespace, deployments, aliases, removePreviousHostAliases)
+}
+
+// writeHostAliases replaces the host aliases in the given deployments after the given function updated the
+// PreviousHostAliasesAnnotation.
+func (u *Updater) writeHostAliases(ctx context.Context, namespace string, deployments []appsv1.Deployment, aliases []corev1.HostAlias, annotate func(*appsv1.Deployment, []corev1.HostAlias) error) error {
 	var multiErr error
 	for _, deploy := range deployments {
+		if ctx.Err() != nil {
+			multiErr = multierror.Append(multiErr, &UpdateError{
+				Deployment: deploy.Name,
+				Skipped:    true,
+				Err:        fmt.Errorf("skipped update of deployment '%s': %w", deploy.Name, ctx.Err()),
+			})
+			continue
+		}
+
 		err := retry.RetryOnConflict(retry.DefaultRetry, func() error {
 			deployment, err := u.clientSet.AppsV1().Deployments(namespace).Get(ctx, deploy.Name, metav1.GetOptions{})
 			if err != nil {
 				return fmt.Errorf("failed to get deployment '%s': %w", deploy.Name, err)
 			}
+			err = annotate(deployment, aliases)
+			if err != nil {
+				return fmt.Errorf("failed to record previous host aliases of deployment '%s': %w", deploy.Name, err)
+			}
 			deployment.Spec.Template.Spec.HostAliases = aliases
 
-			_, err = u.clientSet.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{})
+			_, err = u.clientSet.AppsV1().Deployments(namespace).Update(ctx, deployment, metav1.UpdateOptions{FieldManager: FieldManager})
 			if err != nil {
 				return fmt.Errorf("failed to update deployment '%s': %w", deploy.Name, err)
 			}
@@ -43,7 +95,7 @@ func (u *updater) UpdateHostAliases(ctx context.Context, namespace string, deplo
 		})
 
 		if err != nil {
-			multiErr = multierror.Append(multiErr, err)
+			multiErr = multierror.Append(multiErr, &UpdateError{Deployment: deploy.Name, Err: err})
 		}
 	}
 	if multiErr != nil {
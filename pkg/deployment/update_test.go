@@ -10,8 +10,10 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	clienttest "k8s.io/client-go/testing"
 )
 
 const testNamespace = "ecosystem"
@@ -57,6 +59,10 @@ func Test_updater_Update(t *testing.T) {
 				require.Error(t, err)
 				assert.ErrorContains(t, err, "1 error occurred")
 				assert.ErrorContains(t, err, "failed to get deployment 'will-not-be-found': deployments.apps \"will-not-be-found\" not found")
+				var updateErr *UpdateError
+				require.ErrorAs(t, err, &updateErr)
+				assert.Equal(t, "will-not-be-found", updateErr.Deployment)
+				assert.False(t, updateErr.Skipped)
 			},
 		},
 		{
@@ -95,6 +101,33 @@ func Test_updater_Update(t *testing.T) {
 				assert.ErrorContains(t, err, "failed to get deployment 'will-not-be-found-either': deployments.apps \"will-not-be-found-either\" not found")
 			},
 		},
+		{
+			name: "should skip updates if context is canceled",
+			clientSet: fake.NewSimpleClientset(&appsv1.Deployment{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "will-be-found",
+					Namespace: testNamespace,
+				},
+			}),
+			args: args{
+				ctx:       canceledContext(),
+				namespace: testNamespace,
+				deployments: []appsv1.Deployment{{
+					ObjectMeta: metav1.ObjectMeta{
+						Name: "will-be-found",
+					},
+				}},
+			},
+			wantErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+				assert.ErrorIs(t, err, context.Canceled)
+				assert.ErrorContains(t, err, "skipped update of deployment 'will-be-found'")
+				var updateErr *UpdateError
+				require.ErrorAs(t, err, &updateErr)
+				assert.Equal(t, "will-be-found", updateErr.Deployment)
+				assert.True(t, updateErr.Skipped)
+			},
+		},
 		{
 			name: "should succeed",
 			clientSet: fake.NewSimpleClientset(
@@ -134,7 +167,7 @@ func Test_updater_Update(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			u := &updater{
+			u := &Updater{
 				clientSet: tt.clientSet,
 			}
 			err := u.UpdateHostAliases(tt.args.ctx, tt.args.namespace, tt.args.deployments, tt.args.hostAliases)
@@ -142,3 +175,94 @@ func Test_updater_Update(t *testing.T) {
 		})
 	}
 }
+
+func Test_updater_UpdateFieldManager(t *testing.T) {
+	t.Run("should mark updates with the field manager of the host change", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "cas", Namespace: testNamespace}})
+		var actualOptions metav1.UpdateOptions
+		clientSet.PrependReactor("update", "deployments", func(action clienttest.Action) (bool, runtime.Object, error) {
+			actualOptions = action.(clienttest.UpdateActionImpl).UpdateOptions
+			return false, nil, nil
+		})
+		deployments := []appsv1.Deployment{{ObjectMeta: metav1.ObjectMeta{Name: "cas"}}}
+
+		// when
+		err := NewUpdater(clientSet).UpdateHostAliases(context.TODO(), testNamespace, deployments, nil)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, FieldManager, actualOptions.FieldManager)
+	})
+}
+
+func Test_updater_PreviousHostAliases(t *testing.T) {
+	aliasesA := []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}}}
+	aliasesB := []corev1.HostAlias{{IP: "10.0.0.2", Hostnames: []string{"ces.example.com"}}}
+	deployments := []appsv1.Deployment{{ObjectMeta: metav1.ObjectMeta{Name: "cas"}}}
+	getPrevious := func(t *testing.T, clientSet kubernetes.Interface) ([]corev1.HostAlias, bool) {
+		t.Helper()
+		deploy, err := clientSet.AppsV1().Deployments(testNamespace).Get(context.TODO(), "cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		previous, found, err := PreviousHostAliases(deploy)
+		require.NoError(t, err)
+		return previous, found
+	}
+
+	t.Run("should roll back to the host aliases before re-applying", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "cas", Namespace: testNamespace}})
+		sut := NewUpdater(clientSet)
+		require.NoError(t, sut.UpdateHostAliases(context.TODO(), testNamespace, deployments, aliasesA))
+		require.NoError(t, sut.UpdateHostAliases(context.TODO(), testNamespace, deployments, aliasesB))
+
+		// when
+		err := sut.UpdateHostAliases(context.TODO(), testNamespace, deployments, aliasesA)
+
+		// then
+		require.NoError(t, err)
+		previous, found := getPrevious(t, clientSet)
+		assert.True(t, found)
+		assert.Equal(t, aliasesB, previous)
+	})
+	t.Run("should keep the previous host aliases on a no-op apply", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "cas", Namespace: testNamespace}})
+		sut := NewUpdater(clientSet)
+		require.NoError(t, sut.UpdateHostAliases(context.TODO(), testNamespace, deployments, aliasesA))
+		require.NoError(t, sut.UpdateHostAliases(context.TODO(), testNamespace, deployments, aliasesB))
+
+		// when
+		err := sut.UpdateHostAliases(context.TODO(), testNamespace, deployments, aliasesB)
+
+		// then
+		require.NoError(t, err)
+		previous, found := getPrevious(t, clientSet)
+		assert.True(t, found)
+		assert.Equal(t, aliasesA, previous)
+	})
+	t.Run("should remove the previous host aliases on restore", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "cas", Namespace: testNamespace}})
+		sut := NewUpdater(clientSet)
+		require.NoError(t, sut.UpdateHostAliases(context.TODO(), testNamespace, deployments, aliasesA))
+		require.NoError(t, sut.UpdateHostAliases(context.TODO(), testNamespace, deployments, aliasesB))
+
+		// when
+		err := sut.RestoreHostAliases(context.TODO(), testNamespace, deployments, aliasesA)
+
+		// then
+		require.NoError(t, err)
+		_, found := getPrevious(t, clientSet)
+		assert.False(t, found)
+		deploy, err := clientSet.AppsV1().Deployments(testNamespace).Get(context.TODO(), "cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, aliasesA, deploy.Spec.Template.Spec.HostAliases)
+	})
+}
+
+func canceledContext() context.Context {
+	ctx, cancel := context.WithCancel(context.TODO())
+	cancel()
+	return ctx
+}
@@ -0,0 +1,73 @@
+package dogu
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/cloudogu/cesapp-lib/core"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+const (
+	doguNameLabelKey  = "dogu.name"
+	currentVersionKey = "current"
+	// localDoguRegistrySelector selects the config maps containing the dogu descriptors of all installed dogus.
+	localDoguRegistrySelector = "app=ces,dogu.name,k8s.cloudogu.com/type=local-dogu-registry"
+)
+
+// NewDependencyFetcher creates a new instance of a dependency fetcher which is used for retrieving the dependencies
+// between the installed dogus.
+func NewDependencyFetcher(clientSet kubernetes.Interface) *DependencyFetcher {
+	return &DependencyFetcher{clientSet: clientSet}
+}
+
+// DependencyFetcher retrieves the dependencies between the installed dogus from the local dogu registry.
+type DependencyFetcher struct {
+	clientSet kubernetes.Interface
+}
+
+// FetchDependencies retrieves the dogu dependencies of all installed dogus in a given namespace.
+// The result maps the simple name of each dogu to the simple names of the dogus it depends on.
+// The dependencies are read from the dogu descriptors of the currently installed versions in the local dogu registry.
+// Optional dependencies are included because a present optional dependency should start first as well.
+func (f *DependencyFetcher) FetchDependencies(ctx context.Context, namespace string) (map[string][]string, error) {
+	logger := log.FromContext(ctx)
+
+	options := metav1.ListOptions{LabelSelector: localDoguRegistrySelector}
+	registryList, err := f.clientSet.CoreV1().ConfigMaps(namespace).List(ctx, options)
+	if err != nil {
+		return nil, fmt.Errorf("could not list local dogu registries: %w", err)
+	}
+
+	dependencies := make(map[string][]string, len(registryList.Items))
+	for _, registry := range registryList.Items {
+		doguName := registry.Labels[doguNameLabelKey]
+		version, ok := registry.Data[currentVersionKey]
+		if !ok {
+			logger.Info("Skip dependencies of dogu: no current version", "dogu", doguName)
+			continue
+		}
+
+		descriptor, ok := registry.Data[version]
+		if !ok {
+			return nil, fmt.Errorf("could not find descriptor of current version '%s' of dogu '%s'", version, doguName)
+		}
+
+		doguDescriptor := &core.Dogu{}
+		err = json.Unmarshal([]byte(descriptor), doguDescriptor)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unmarshal descriptor of dogu '%s' with version '%s': %w", doguName, version, err)
+		}
+
+		doguDependencies := []string{}
+		for _, dependency := range doguDescriptor.GetAllDependenciesOfType(core.DependencyTypeDogu) {
+			doguDependencies = append(doguDependencies, core.GetSimpleDoguName(dependency.Name))
+		}
+		dependencies[doguName] = doguDependencies
+	}
+
+	return dependencies, nil
+}
@@ -0,0 +1,121 @@
+package dogu
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	fakecorev1 "k8s.io/client-go/kubernetes/typed/core/v1/fake"
+	clienttest "k8s.io/client-go/testing"
+)
+
+const casDescriptor = `{
+  "Name": "official/cas",
+  "Version": "7.0.5-1",
+  "Dependencies": [
+    {"type": "dogu", "name": "ldap"},
+    {"type": "dogu", "name": "official/postfix"},
+    {"type": "client", "name": "k8s-dogu-operator"}
+  ],
+  "OptionalDependencies": [
+    {"type": "dogu", "name": "redis"}
+  ]
+}`
+
+const ldapDescriptor = `{"Name": "official/ldap", "Version": "2.6.7-3"}`
+
+func TestNewDependencyFetcher(t *testing.T) {
+	// given
+	clientSet := fake.NewSimpleClientset()
+
+	// when
+	fetcher := NewDependencyFetcher(clientSet)
+
+	// then
+	require.NotNil(t, fetcher)
+	assert.Equal(t, clientSet, fetcher.clientSet)
+}
+
+func Test_dependencyFetcher_FetchDependencies(t *testing.T) {
+	t.Run("should fail to list local dogu registries", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		clientSet.CoreV1().(*fakecorev1.FakeCoreV1).PrependReactor("list", "configmaps", func(action clienttest.Action) (handled bool, ret runtime.Object, err error) {
+			return true, nil, assert.AnError
+		})
+		sut := &DependencyFetcher{clientSet: clientSet}
+
+		// when
+		_, err := sut.FetchDependencies(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "could not list local dogu registries")
+	})
+	t.Run("should fail if descriptor of current version is missing", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(localDoguRegistry("cas", map[string]string{currentVersionKey: "7.0.5-1"}))
+		sut := &DependencyFetcher{clientSet: clientSet}
+
+		// when
+		_, err := sut.FetchDependencies(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "could not find descriptor of current version '7.0.5-1' of dogu 'cas'")
+	})
+	t.Run("should fail on invalid descriptor", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(localDoguRegistry("cas", map[string]string{currentVersionKey: "7.0.5-1", "7.0.5-1": "{"}))
+		sut := &DependencyFetcher{clientSet: clientSet}
+
+		// when
+		_, err := sut.FetchDependencies(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to unmarshal descriptor of dogu 'cas' with version '7.0.5-1'")
+	})
+	t.Run("should return dogu dependencies of all installed dogus", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(
+			localDoguRegistry("cas", map[string]string{currentVersionKey: "7.0.5-1", "7.0.5-1": casDescriptor}),
+			localDoguRegistry("ldap", map[string]string{currentVersionKey: "2.6.7-3", "2.6.7-3": ldapDescriptor}),
+			localDoguRegistry("redmine", map[string]string{}),
+		)
+		sut := &DependencyFetcher{clientSet: clientSet}
+
+		// when
+		actual, err := sut.FetchDependencies(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+		expected := map[string][]string{
+			"cas":  {"ldap", "postfix", "redis"},
+			"ldap": {},
+		}
+		assert.Equal(t, expected, actual)
+	})
+}
+
+func localDoguRegistry(doguName string, data map[string]string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "dogu-spec-" + doguName,
+			Namespace: testNamespace,
+			Labels: map[string]string{
+				"app":                   "ces",
+				"dogu.name":             doguName,
+				"k8s.cloudogu.com/type": "local-dogu-registry",
+			},
+		},
+		Data: data,
+	}
+}
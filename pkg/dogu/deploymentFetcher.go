@@ -6,36 +6,66 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/selection"
 	"k8s.io/client-go/kubernetes"
 )
 
 // NewDeploymentFetcher creates a new instance of a deployment fetcher which is used for retrieving dogu deployments.
-func NewDeploymentFetcher(clientSet kubernetes.Interface) *deploymentFetcher {
-	return &deploymentFetcher{clientSet: clientSet}
+func NewDeploymentFetcher(clientSet kubernetes.Interface) *DeploymentFetcher {
+	return &DeploymentFetcher{clientSet: clientSet}
 }
 
-type deploymentFetcher struct {
+// NewDeploymentFetcherWithSelector creates a deployment fetcher which only retrieves the dogu deployments matching
+// the given label selector, e.g. 'dogu.name=redmine'.
+func NewDeploymentFetcherWithSelector(clientSet kubernetes.Interface, selector labels.Selector) *DeploymentFetcher {
+	return &DeploymentFetcher{clientSet: clientSet, selector: selector}
+}
+
+// DeploymentFetcher retrieves the dogu deployments of a namespace.
+type DeploymentFetcher struct {
 	clientSet kubernetes.Interface
+	// selector narrows the dogu deployments. It is nil if all dogu deployments should be retrieved.
+	selector labels.Selector
 }
 
 // FetchAll retrieves all dogu deployments in a given namespace.
 // The 'dogu.name' label key is used for identifying dogu deployments.
-func (f *deploymentFetcher) FetchAll(ctx context.Context, namespace string) ([]appsv1.Deployment, error) {
-	selector := &metav1.LabelSelector{
-		MatchExpressions: []metav1.LabelSelectorRequirement{{
-			Key:      "dogu.name",
-			Operator: metav1.LabelSelectorOpExists,
-			Values:   nil,
-		}},
+func (f *DeploymentFetcher) FetchAll(ctx context.Context, namespace string) ([]appsv1.Deployment, error) {
+	selector, selectable, err := f.labelSelector()
+	if err != nil {
+		return nil, err
+	}
+	if !selectable {
+		// a selector like labels.Nothing() matches no deployment, but its string form would select all of them
+		return nil, nil
 	}
 
 	options := metav1.ListOptions{
-		LabelSelector: metav1.FormatLabelSelector(selector),
+		LabelSelector: selector.String(),
 	}
 	deploymentList, err := f.clientSet.AppsV1().Deployments(namespace).List(ctx, options)
 	if err != nil {
-		return nil, fmt.Errorf("could not list deployments with selector 'dogu.name': %w", err)
+		return nil, fmt.Errorf("could not list deployments with selector '%s': %w", selector, err)
 	}
 
 	return deploymentList.Items, nil
 }
+
+func (f *DeploymentFetcher) labelSelector() (labels.Selector, bool, error) {
+	doguRequirement, err := labels.NewRequirement(doguNameLabelKey, selection.Exists, nil)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to create selector for dogu deployments: %w", err)
+	}
+
+	selector := labels.NewSelector().Add(*doguRequirement)
+	if f.selector != nil {
+		requirements, selectable := f.selector.Requirements()
+		if !selectable {
+			return nil, false, nil
+		}
+		selector = selector.Add(requirements...)
+	}
+
+	return selector, true, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	appsv1 "k8s.io/api/apps/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
@@ -118,7 +119,7 @@ func Test_deploymentFetcher_FetchAll(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			f := &deploymentFetcher{
+			f := &DeploymentFetcher{
 				clientSet: tt.clientSet,
 			}
 			got, err := f.FetchAll(tt.args.ctx, tt.args.namespace)
@@ -128,6 +129,50 @@ func Test_deploymentFetcher_FetchAll(t *testing.T) {
 	}
 }
 
+func Test_deploymentFetcher_FetchAllWithSelector(t *testing.T) {
+	t.Run("should only find dogu deployments matching the selector", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "redmine", Namespace: testNamespace, Labels: map[string]string{"dogu.name": "redmine"}}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "cas", Namespace: testNamespace, Labels: map[string]string{"dogu.name": "cas"}}},
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: testNamespace, Labels: map[string]string{"app": "redmine"}}},
+		)
+		selector := labels.SelectorFromSet(labels.Set{"dogu.name": "redmine"})
+
+		// when
+		actual, err := NewDeploymentFetcherWithSelector(clientSet, selector).FetchAll(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, "redmine", actual[0].Name)
+	})
+	t.Run("should find no dogu deployments for a selector matching nothing", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(
+			&appsv1.Deployment{ObjectMeta: metav1.ObjectMeta{Name: "redmine", Namespace: testNamespace, Labels: map[string]string{"dogu.name": "redmine"}}},
+		)
+
+		// when
+		actual, err := NewDeploymentFetcherWithSelector(clientSet, labels.Nothing()).FetchAll(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+	t.Run("should report the combined selector on error", func(t *testing.T) {
+		// given
+		selector := labels.SelectorFromSet(labels.Set{"dogu.name": "redmine"})
+
+		// when
+		_, err := NewDeploymentFetcherWithSelector(failingClientSet(), selector).FetchAll(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "could not list deployments with selector 'dogu.name,dogu.name=redmine'")
+	})
+}
+
 func failingClientSet() *fake.Clientset {
 	clientSet := fake.NewSimpleClientset()
 	clientSet.AppsV1().(*fakeappsv1.FakeAppsV1).PrependReactor("list", "deployments", func(action clienttest.Action) (handled bool, ret runtime.Object, err error) {
@@ -0,0 +1,22 @@
+package history
+
+import (
+	"context"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Identity returns the user name the given client set is authenticated as. It returns an empty string if the api
+// server does not reveal the identity.
+func Identity(ctx context.Context, clientSet kubernetes.Interface) string {
+	review, err := clientSet.AuthenticationV1().SelfSubjectReviews().Create(ctx, &authenticationv1.SelfSubjectReview{}, metav1.CreateOptions{})
+	if err != nil {
+		log.FromContext(ctx).Info("Failed to determine the identity of the host change", "error", err.Error())
+		return ""
+	}
+
+	return review.Status.UserInfo.Username
+}
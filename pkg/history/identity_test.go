@@ -0,0 +1,44 @@
+package history
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	authenticationv1 "k8s.io/api/authentication/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func TestIdentity(t *testing.T) {
+	t.Run("should return user name", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		clientSet.PrependReactor("create", "selfsubjectreviews", func(k8stesting.Action) (bool, runtime.Object, error) {
+			review := &authenticationv1.SelfSubjectReview{}
+			review.Status.UserInfo.Username = "system:serviceaccount:ecosystem:k8s-host-change"
+			return true, review, nil
+		})
+
+		// when
+		actual := Identity(context.TODO(), clientSet)
+
+		// then
+		assert.Equal(t, "system:serviceaccount:ecosystem:k8s-host-change", actual)
+	})
+	t.Run("should return empty identity on error", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		clientSet.PrependReactor("create", "selfsubjectreviews", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, assert.AnError
+		})
+
+		// when
+		actual := Identity(context.TODO(), clientSet)
+
+		// then
+		assert.Empty(t, actual)
+	})
+}
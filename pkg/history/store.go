@@ -0,0 +1,144 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/util/retry"
+
+	"github.com/cloudogu/k8s-host-change/pkg/hosts"
+)
+
+// ConfigMapName is the name of the config map containing the history of host changes.
+const ConfigMapName = "k8s-host-change-history"
+
+// Entry is a single host change in the history.
+type Entry struct {
+	// TriggeredBy is the identity which ran the host change. It is empty if unknown.
+	TriggeredBy string `json:"triggeredBy,omitempty"`
+	hosts.Result
+}
+
+type store struct {
+	clientSet kubernetes.Interface
+	retention int
+}
+
+// NewStore creates a store which keeps the given number of host changes in the history config map.
+func NewStore(clientSet kubernetes.Interface, retention int) *store {
+	return &store{clientSet: clientSet, retention: retention}
+}
+
+// Append adds the given entry to the history and removes the oldest entries exceeding the retention.
+// The config map is created if it does not exist and fetched again on conflicts.
+func (s *store) Append(ctx context.Context, namespace string, entry Entry) error {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("failed to serialize history entry '%s': %w", entry.RunID, err)
+	}
+
+	err = retry.RetryOnConflict(retry.DefaultRetry, func() error {
+		configMaps := s.clientSet.CoreV1().ConfigMaps(namespace)
+		configMap, err := configMaps.Get(ctx, ConfigMapName, metav1.GetOptions{})
+		if apierrors.IsNotFound(err) {
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      ConfigMapName,
+					Namespace: namespace,
+					Labels:    map[string]string{"app": "ces", "app.kubernetes.io/name": "k8s-host-change"},
+				},
+				Data: map[string]string{entry.RunID: string(raw)},
+			}
+			_, err = configMaps.Create(ctx, configMap, metav1.CreateOptions{})
+			if apierrors.IsAlreadyExists(err) {
+				// let the retry fetch the config map created in the meantime
+				return apierrors.NewConflict(corev1.Resource("configmaps"), ConfigMapName, err)
+			}
+			return err
+		}
+		if err != nil {
+			return err
+		}
+
+		if configMap.Data == nil {
+			configMap.Data = map[string]string{}
+		}
+		configMap.Data[entry.RunID] = string(raw)
+		s.prune(configMap)
+
+		_, err = configMaps.Update(ctx, configMap, metav1.UpdateOptions{})
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("failed to write history entry '%s' to config map '%s': %w", entry.RunID, ConfigMapName, err)
+	}
+
+	return nil
+}
+
+// prune removes the oldest entries exceeding the retention from the given config map.
+func (s *store) prune(configMap *corev1.ConfigMap) {
+	entries := parseEntries(configMap)
+	for i := s.retention; i < len(entries); i++ {
+		delete(configMap.Data, entries[i].RunID)
+	}
+}
+
+// List returns all entries of the history, the latest host change first.
+func (s *store) List(ctx context.Context, namespace string) ([]Entry, error) {
+	configMap, err := s.clientSet.CoreV1().ConfigMaps(namespace).Get(ctx, ConfigMapName, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return []Entry{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get config map '%s': %w", ConfigMapName, err)
+	}
+
+	return parseEntries(configMap), nil
+}
+
+// Get returns the entry of the host change with the given run id.
+func (s *store) Get(ctx context.Context, namespace string, runID string) (*Entry, error) {
+	entries, err := s.List(ctx, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, entry := range entries {
+		if entry.RunID == runID {
+			return &entry, nil
+		}
+	}
+
+	return nil, fmt.Errorf("no host change with run id '%s' found in history", runID)
+}
+
+// parseEntries returns the entries of the given config map, the latest host change first.
+// Entries which cannot be parsed are returned with their run id only, so that they are pruned eventually.
+func parseEntries(configMap *corev1.ConfigMap) []Entry {
+	entries := make([]Entry, 0, len(configMap.Data))
+	for runID, raw := range configMap.Data {
+		entry := Entry{}
+		err := json.Unmarshal([]byte(raw), &entry)
+		if err != nil {
+			entry = Entry{}
+		}
+		entry.RunID = runID
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].StartTime.Equal(&entries[j].StartTime) {
+			return entries[i].RunID > entries[j].RunID
+		}
+		return entries[j].StartTime.Before(&entries[i].StartTime)
+	})
+
+	return entries
+}
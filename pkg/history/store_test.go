@@ -0,0 +1,142 @@
+package history
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+
+	"github.com/cloudogu/k8s-host-change/pkg/hosts"
+)
+
+const testNamespace = "ecosystem"
+
+var startTime = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+
+func entry(runID string, minutes int) Entry {
+	return Entry{
+		TriggeredBy: "admin",
+		Result: hosts.Result{
+			RunID:     runID,
+			Namespace: testNamespace,
+			Status:    hosts.StatusSucceeded,
+			StartTime: metav1.NewTime(startTime.Add(time.Duration(minutes) * time.Minute)),
+		},
+	}
+}
+
+func Test_store_Append(t *testing.T) {
+	t.Run("should create history config map", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		sut := NewStore(clientSet, 5)
+
+		// when
+		err := sut.Append(context.TODO(), testNamespace, entry("run-1", 0))
+
+		// then
+		require.NoError(t, err)
+		configMap, err := clientSet.CoreV1().ConfigMaps(testNamespace).Get(context.TODO(), ConfigMapName, metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Contains(t, configMap.Data, "run-1")
+	})
+	t.Run("should remove oldest entries exceeding the retention", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		sut := NewStore(clientSet, 2)
+		for i := 1; i <= 3; i++ {
+			require.NoError(t, sut.Append(context.TODO(), testNamespace, entry(fmt.Sprintf("run-%d", i), i)))
+		}
+
+		// when
+		err := sut.Append(context.TODO(), testNamespace, entry("run-4", 4))
+
+		// then
+		require.NoError(t, err)
+		entries, err := sut.List(context.TODO(), testNamespace)
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "run-4", entries[0].RunID)
+		assert.Equal(t, "run-3", entries[1].RunID)
+	})
+	t.Run("should fail to update history config map", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(&corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: ConfigMapName, Namespace: testNamespace},
+		})
+		clientSet.PrependReactor("update", "configmaps", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, assert.AnError
+		})
+		sut := NewStore(clientSet, 5)
+
+		// when
+		err := sut.Append(context.TODO(), testNamespace, entry("run-1", 0))
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to write history entry 'run-1' to config map 'k8s-host-change-history'")
+	})
+}
+
+func Test_store_List(t *testing.T) {
+	t.Run("should return empty history without config map", func(t *testing.T) {
+		// given
+		sut := NewStore(fake.NewSimpleClientset(), 5)
+
+		// when
+		entries, err := sut.List(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, entries)
+	})
+	t.Run("should return latest entries first", func(t *testing.T) {
+		// given
+		sut := NewStore(fake.NewSimpleClientset(), 5)
+		require.NoError(t, sut.Append(context.TODO(), testNamespace, entry("run-2", 2)))
+		require.NoError(t, sut.Append(context.TODO(), testNamespace, entry("run-1", 1)))
+
+		// when
+		entries, err := sut.List(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, entries, 2)
+		assert.Equal(t, "run-2", entries[0].RunID)
+		assert.Equal(t, "admin", entries[0].TriggeredBy)
+		assert.Equal(t, hosts.StatusSucceeded, entries[0].Status)
+		assert.Equal(t, "run-1", entries[1].RunID)
+	})
+}
+
+func Test_store_Get(t *testing.T) {
+	sut := NewStore(fake.NewSimpleClientset(), 5)
+	require.NoError(t, sut.Append(context.TODO(), testNamespace, entry("run-1", 1)))
+
+	t.Run("should return entry", func(t *testing.T) {
+		// when
+		actual, err := sut.Get(context.TODO(), testNamespace, "run-1")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "run-1", actual.RunID)
+	})
+	t.Run("should fail on unknown run id", func(t *testing.T) {
+		// when
+		_, err := sut.Get(context.TODO(), testNamespace, "run-2")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "no host change with run id 'run-2' found in history")
+	})
+}
@@ -0,0 +1,29 @@
+package hosts
+
+import (
+	"fmt"
+)
+
+// BackendType names the way the host aliases are published to the dogus.
+type BackendType string
+
+const (
+	// BackendHostAliases writes the host aliases into the pod templates of the dogu deployments. Every change restarts
+	// the dogus.
+	BackendHostAliases BackendType = "host-aliases"
+	// BackendCoreDNS publishes the host aliases as server block in the config map CoreDNS imports. The dogus resolve
+	// the changed host names without a restart.
+	BackendCoreDNS BackendType = "coredns"
+)
+
+// ParseBackendType returns the backend with the given name. An empty name results in BackendHostAliases.
+func ParseBackendType(name string) (BackendType, error) {
+	switch backend := BackendType(name); backend {
+	case "":
+		return BackendHostAliases, nil
+	case BackendHostAliases, BackendCoreDNS:
+		return backend, nil
+	default:
+		return "", fmt.Errorf("unknown backend '%s': expected '%s' or '%s'", name, BackendHostAliases, BackendCoreDNS)
+	}
+}
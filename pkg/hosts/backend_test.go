@@ -0,0 +1,37 @@
+package hosts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBackendType(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    BackendType
+		wantErr func(t *testing.T, err error)
+	}{
+		{name: "should default to host-aliases", value: "", want: BackendHostAliases, wantErr: noError},
+		{name: "should parse host-aliases", value: "host-aliases", want: BackendHostAliases, wantErr: noError},
+		{name: "should parse coredns", value: "coredns", want: BackendCoreDNS, wantErr: noError},
+		{
+			name:  "should fail on unknown backend",
+			value: "etc-hosts",
+			wantErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, "unknown backend 'etc-hosts': expected 'host-aliases' or 'coredns'")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseBackendType(tt.value)
+
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, actual)
+		})
+	}
+}
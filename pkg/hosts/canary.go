@@ -0,0 +1,102 @@
+package hosts
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/k8s-host-change/pkg/logging"
+)
+
+// updateCanary updates, rolls out and verifies the canary deployment before any other deployment is touched.
+// It returns the name of the canary and the waves without the canary. The canary gets rolled back if it fails.
+func (hau *DefaultHostAliasUpdater) updateCanary(ctx context.Context, namespace string, waves [][]appsv1.Deployment, hostAliases []corev1.HostAlias, previousHostAliases map[string][]corev1.HostAlias, result *Result) (string, [][]appsv1.Deployment, error) {
+	canary, remainingWaves, err := selectCanary(waves, hau.canaryDogu)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to select canary dogu: %w", err)
+	}
+
+	ctx = logging.WithDeployment(logging.WithPhase(ctx, logging.PhaseCanary), canary.Name)
+	logger := log.FromContext(ctx)
+	logger.Info("Update canary deployment")
+	err = hau.updateWave(ctx, namespace, []appsv1.Deployment{canary}, hostAliases, previousHostAliases, result)
+	if err != nil {
+		return "", nil, fmt.Errorf("canary deployment '%s' failed: %w", canary.Name, err)
+	}
+
+	err = hau.verifyCanary(ctx, namespace, canary.Name, hostAliases)
+	if err != nil {
+		logger.Error(err, "Failed to verify canary deployment: rolling back")
+		result.fail(canary.Name, err)
+
+		err = hau.rollbackOnError(ctx, namespace, hostAliases, previousHostAliases, result, err)
+		return "", nil, fmt.Errorf("canary deployment '%s' failed: %w", canary.Name, err)
+	}
+
+	logger.Info("Canary deployment verified: update remaining deployments")
+	return canary.Name, remainingWaves, nil
+}
+
+// verifyCanary checks that the rolled out canary deployment carries the expected host aliases in its pod template.
+func (hau *DefaultHostAliasUpdater) verifyCanary(ctx context.Context, namespace string, name string, hostAliases []corev1.HostAlias) error {
+	deployments, err := hau.fetcher.FetchAll(ctx, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to fetch dogu deployments for verification: %w", err)
+	}
+
+	for _, deploy := range deployments {
+		if deploy.Name != name {
+			continue
+		}
+
+		actual := deploy.Spec.Template.Spec.HostAliases
+		if !equality.Semantic.DeepEqual(normalizeAliases(actual), normalizeAliases(hostAliases)) {
+			return fmt.Errorf("deployment '%s' has unexpected host aliases %v, expected %v", name, actual, hostAliases)
+		}
+
+		return nil
+	}
+
+	return fmt.Errorf("deployment '%s' not found", name)
+}
+
+// selectCanary removes the canary deployment from the waves. The canary is either the deployment of the given dogu
+// or, if no dogu is given, the first deployment of the first wave. Waves which become empty are dropped.
+func selectCanary(waves [][]appsv1.Deployment, doguName string) (appsv1.Deployment, [][]appsv1.Deployment, error) {
+	for i, wave := range waves {
+		for j, deploy := range wave {
+			if doguName != "" && deploy.Labels[doguNameLabelKey] != doguName {
+				continue
+			}
+
+			var remainingWaves [][]appsv1.Deployment
+			remainingWaves = append(remainingWaves, waves[:i]...)
+			remainingWave := append(append([]appsv1.Deployment{}, wave[:j]...), wave[j+1:]...)
+			if len(remainingWave) > 0 {
+				remainingWaves = append(remainingWaves, remainingWave)
+			}
+			remainingWaves = append(remainingWaves, waves[i+1:]...)
+
+			return deploy, remainingWaves, nil
+		}
+	}
+
+	if doguName != "" {
+		return appsv1.Deployment{}, nil, fmt.Errorf("no deployment found for dogu '%s'", doguName)
+	}
+
+	return appsv1.Deployment{}, nil, fmt.Errorf("no dogu deployments found")
+}
+
+// normalizeAliases treats nil and empty host aliases equally.
+func normalizeAliases(aliases []corev1.HostAlias) []corev1.HostAlias {
+	if len(aliases) == 0 {
+		return nil
+	}
+
+	return aliases
+}
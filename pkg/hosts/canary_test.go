@@ -0,0 +1,162 @@
+package hosts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_selectCanary(t *testing.T) {
+	cas := doguDeployment("cas")
+	ldap := doguDeployment("ldap")
+	nginx := doguDeployment("nginx")
+	redmine := doguDeployment("redmine")
+	waves := [][]appsv1.Deployment{{ldap}, {cas, nginx}, {redmine}}
+
+	t.Run("should select first deployment of plan", func(t *testing.T) {
+		// when
+		canary, remaining, err := selectCanary(waves, "")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, ldap, canary)
+		assert.Equal(t, [][]appsv1.Deployment{{cas, nginx}, {redmine}}, remaining)
+	})
+	t.Run("should select configured dogu", func(t *testing.T) {
+		// when
+		canary, remaining, err := selectCanary(waves, "nginx")
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, nginx, canary)
+		assert.Equal(t, [][]appsv1.Deployment{{ldap}, {cas}, {redmine}}, remaining)
+		assert.Equal(t, [][]appsv1.Deployment{{ldap}, {cas, nginx}, {redmine}}, waves, "input must not be modified")
+	})
+	t.Run("should fail if configured dogu has no deployment", func(t *testing.T) {
+		// when
+		_, _, err := selectCanary(waves, "jenkins")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "no deployment found for dogu 'jenkins'")
+	})
+	t.Run("should fail without deployments", func(t *testing.T) {
+		// when
+		_, _, err := selectCanary(nil, "")
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "no dogu deployments found")
+	})
+}
+
+func Test_hostAliasUpdater_UpdateHosts_canary(t *testing.T) {
+	ldap := doguDeployment("ldap")
+	cas := doguDeployment("cas")
+	updatedLdap := doguDeployment("ldap")
+	updatedLdap.Spec.Template.Spec.HostAliases = hostAliases
+
+	t.Run("should update remaining deployments after verified canary", func(t *testing.T) {
+		// given
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return([]appsv1.Deployment{ldap, cas}, nil).Once()
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return([]appsv1.Deployment{updatedLdap, cas}, nil).Once()
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{ldap}, hostAliases).Return(nil).Once()
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{cas}, hostAliases).Return(nil).Once()
+		waiter := NewMockRolloutWaiter(t)
+		waiter.EXPECT().WaitForRollout(mock.Anything, testNamespace, []appsv1.Deployment{ldap}).Return(nil).Once()
+		waiter.EXPECT().WaitForRollout(mock.Anything, testNamespace, []appsv1.Deployment{cas}).Return(nil).Once()
+		sut := &DefaultHostAliasUpdater{
+			generator: generator,
+			fetcher:   fetcher,
+			updater:   updater,
+			waiter:    waiter,
+			canary:    true,
+		}
+
+		// when
+		err := sut.UpdateHosts(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should roll back canary with unexpected host aliases", func(t *testing.T) {
+		// given
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return([]appsv1.Deployment{ldap, cas}, nil).Times(3)
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{cas}, hostAliases).Return(nil).Once()
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{ldap, cas}, mock.Anything).Return(nil).Once()
+		waiter := NewMockRolloutWaiter(t)
+		waiter.EXPECT().WaitForRollout(mock.Anything, testNamespace, []appsv1.Deployment{cas}).Return(nil).Once()
+		sut := &DefaultHostAliasUpdater{
+			generator:  generator,
+			fetcher:    fetcher,
+			updater:    updater,
+			waiter:     waiter,
+			canary:     true,
+			canaryDogu: "cas",
+		}
+
+		// when
+		err := sut.UpdateHosts(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "canary deployment 'cas' failed: deployment 'cas' has unexpected host aliases")
+	})
+	t.Run("should fail if canary rollout fails", func(t *testing.T) {
+		// given
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return([]appsv1.Deployment{ldap, cas}, nil).Twice()
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{ldap}, hostAliases).Return(nil).Once()
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{ldap, cas}, []corev1.HostAlias(nil)).Return(nil).Once()
+		waiter := NewMockRolloutWaiter(t)
+		waiter.EXPECT().WaitForRollout(mock.Anything, testNamespace, []appsv1.Deployment{ldap}).Return(assert.AnError).Once()
+		sut := &DefaultHostAliasUpdater{
+			generator: generator,
+			fetcher:   fetcher,
+			updater:   updater,
+			waiter:    waiter,
+			canary:    true,
+		}
+
+		// when
+		err := sut.UpdateHosts(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "canary deployment 'ldap' failed: failed to roll out host-aliases of dogu deployments in cluster")
+	})
+	t.Run("should fail if canary dogu is not deployed", func(t *testing.T) {
+		// given
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return([]appsv1.Deployment{ldap, cas}, nil).Once()
+		sut := &DefaultHostAliasUpdater{
+			generator:  generator,
+			fetcher:    fetcher,
+			canary:     true,
+			canaryDogu: "jenkins",
+		}
+
+		// when
+		err := sut.UpdateHosts(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to select canary dogu: no deployment found for dogu 'jenkins'")
+	})
+}
@@ -2,57 +2,225 @@ package hosts
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"slices"
+	"time"
+
+	"github.com/go-logr/logr"
 	"github.com/hashicorp/go-multierror"
+	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/client-go/kubernetes"
 	"sigs.k8s.io/controller-runtime/pkg/log"
 
 	"github.com/cloudogu/k8s-host-change/pkg/deployment"
 	"github.com/cloudogu/k8s-host-change/pkg/dogu"
+	"github.com/cloudogu/k8s-host-change/pkg/logging"
+	"github.com/cloudogu/k8s-host-change/pkg/rollout"
 )
 
+// Options configures the optional behaviour of the DefaultHostAliasUpdater.
+type Options struct {
+	// WaitForRollout enables waiting for the rollout of all updated deployments.
+	// Failed or stalled rollouts trigger a rollback of the host aliases.
+	WaitForRollout bool
+	// RolloutTimeout limits the wait for the rollout of a single deployment. Zero means no limit.
+	RolloutTimeout time.Duration
+	// RolloutGlobalTimeout limits the wait for the rollout of all deployments. Zero means no limit.
+	RolloutGlobalTimeout time.Duration
+	// StagedRestart enables updating the deployments in waves ordered by their dogu dependencies.
+	// Every wave is rolled out completely before the next wave is updated. This implies WaitForRollout.
+	StagedRestart bool
+	// Canary enables updating a single dogu first. The remaining dogus are only updated if the canary was rolled out
+	// successfully and carries the new host aliases. This implies WaitForRollout.
+	Canary bool
+	// CanaryDogu is the name of the dogu used as canary. If empty, the first dogu of the update plan is used.
+	CanaryDogu string
+	// MaintenanceMode is activated while the deployments are updated. Nil disables the maintenance mode.
+	MaintenanceMode MaintenanceModeSwitch
+	// ShutdownTimeout limits the rollback after the update was interrupted, e.g. by a termination signal.
+	// It should be lower than the termination grace period of the pod. Defaults to 25 seconds if zero.
+	ShutdownTimeout time.Duration
+	// MaxAliasRemovalShare is the share (0-1] of existing host names which may be removed without confirmation.
+	// The removal of all host names always requires a confirmation. Zero only guards the removal of all host names.
+	MaxAliasRemovalShare float64
+	// ConfirmAliasRemoval confirms the removal of host names beyond MaxAliasRemovalShare.
+	ConfirmAliasRemoval bool
+	// RemovalConfirmation is asked for a confirmation if ConfirmAliasRemoval is not set. It may be nil.
+	RemovalConfirmation AliasRemovalConfirmation
+	// FailurePolicy defines how the host change reacts on failed deployments. Defaults to FailurePolicyRollbackAll.
+	// The canary and interrupted host changes are always rolled back.
+	FailurePolicy FailurePolicy
+	// VerifyPods enables checking that the running pods of the updated deployments carry the new host aliases.
+	// Pods of old replica sets which were not replaced yet are reported as stale pods.
+	VerifyPods bool
+	// PodVerificationTimeout limits the wait for stale pods to be replaced. Zero only reports stale pods without
+	// waiting, otherwise pods which are still stale after the timeout fail the host change.
+	PodVerificationTimeout time.Duration
+	// HostsFileVerifier reads the hosts file of a pod of every updated deployment after the pod verification and
+	// fails the host change if it does not contain the new host aliases. Nil disables the verification.
+	HostsFileVerifier HostsFileVerifier
+}
+
+const defaultShutdownTimeout = 25 * time.Second
+
+// DefaultHostAliasUpdater updates the host aliases of the dogu deployments. Create it with NewUpdater.
 type DefaultHostAliasUpdater struct {
-	generator hostAliasGenerator
-	fetcher   doguDeploymentFetcher
-	updater   deploymentUpdater
+	// clientSet is used by the default generator to read the global config.
+	clientSet kubernetes.Interface
+	// generator is nil if the host aliases should be generated from the global config of the namespace.
+	generator HostAliasGenerator
+	fetcher   DeploymentFetcher
+	updater   DeploymentUpdater
+	// waiter is nil if the updater should not wait for rollouts.
+	waiter RolloutWaiter
+	// dependencyFetcher is nil if all deployments should be updated at once.
+	dependencyFetcher DependencyFetcher
+	canary            bool
+	canaryDogu        string
+	// maintenanceMode is nil if the maintenance mode should not be activated.
+	maintenanceMode MaintenanceModeSwitch
+	shutdownTimeout time.Duration
+	// maxAliasRemovalShare, confirmAliasRemoval and removalConfirmation configure the guard against removing host aliases.
+	maxAliasRemovalShare float64
+	confirmAliasRemoval  bool
+	removalConfirmation  AliasRemovalConfirmation
+	// failurePolicy is empty or FailurePolicyRollbackAll if all deployments should be rolled back on failure.
+	failurePolicy FailurePolicy
+	// podVerifier is nil if the running pods should not be verified.
+	podVerifier      PodVerifier
+	waitForStalePods bool
+	// hostsFileVerifier is nil if the hosts files of the pods should not be verified.
+	hostsFileVerifier HostsFileVerifier
+	// logger is nil if the logger of the context should be used.
+	logger *logr.Logger
 }
 
-// NewHostAliasUpdater is used to create a new instance of DefaultHostAliasUpdater.
-func NewHostAliasUpdater(clientSet kubernetes.Interface, generator hostAliasGenerator) *DefaultHostAliasUpdater {
-	return &DefaultHostAliasUpdater{
-		generator: generator,
-		fetcher:   dogu.NewDeploymentFetcher(clientSet),
-		updater:   deployment.NewUpdater(clientSet),
+// NewHostAliasUpdater is used to create a new instance of DefaultHostAliasUpdater. Use NewUpdater with WithOptions to
+// configure the optional behaviour.
+func NewHostAliasUpdater(clientSet kubernetes.Interface, generator HostAliasGenerator) *DefaultHostAliasUpdater {
+	return NewUpdater(clientSet, WithGenerator(generator))
+}
+
+// NewUpdater creates a DefaultHostAliasUpdater for the dogu deployments reachable by the given client set. Without
+// options it generates the host aliases from the global config of the namespace passed to its methods, updates all
+// dogu deployments at once and rolls back all deployments on failure.
+func NewUpdater(clientSet kubernetes.Interface, updaterOpts ...UpdaterOption) *DefaultHostAliasUpdater {
+	cfg := &updaterConfig{}
+	for _, updaterOpt := range updaterOpts {
+		updaterOpt(cfg)
+	}
+	opts := cfg.options
+	if cfg.failurePolicy != "" {
+		opts.FailurePolicy = cfg.failurePolicy
+	}
+
+	hau := &DefaultHostAliasUpdater{
+		clientSet:            clientSet,
+		generator:            cfg.generator,
+		fetcher:              cfg.fetcher,
+		updater:              cfg.updater,
+		logger:               cfg.logger,
+		canary:               opts.Canary,
+		canaryDogu:           opts.CanaryDogu,
+		maintenanceMode:      opts.MaintenanceMode,
+		shutdownTimeout:      opts.ShutdownTimeout,
+		maxAliasRemovalShare: opts.MaxAliasRemovalShare,
+		confirmAliasRemoval:  opts.ConfirmAliasRemoval,
+		removalConfirmation:  opts.RemovalConfirmation,
+		failurePolicy:        opts.FailurePolicy,
+		waitForStalePods:     opts.PodVerificationTimeout > 0,
+		hostsFileVerifier:    opts.HostsFileVerifier,
+	}
+
+	if hau.fetcher == nil {
+		hau.fetcher = dogu.NewDeploymentFetcherWithSelector(clientSet, cfg.selector)
+	}
+
+	if hau.updater == nil {
+		hau.updater = deployment.NewUpdater(clientSet)
 	}
+
+	if hau.shutdownTimeout == 0 {
+		hau.shutdownTimeout = defaultShutdownTimeout
+	}
+
+	if opts.StagedRestart {
+		hau.dependencyFetcher = dogu.NewDependencyFetcher(clientSet)
+	}
+
+	if opts.WaitForRollout || opts.StagedRestart || opts.Canary {
+		hau.waiter = rollout.NewWaiter(clientSet, opts.RolloutTimeout, opts.RolloutGlobalTimeout)
+	}
+
+	if opts.VerifyPods {
+		hau.podVerifier = rollout.NewPodVerifier(clientSet, opts.PodVerificationTimeout)
+	}
+
+	return hau
 }
 
 // UpdateHosts updates all dogu deployments with host information like fqdn, internal ip and additional hosts from ces registry.
 func (hau *DefaultHostAliasUpdater) UpdateHosts(ctx context.Context, namespace string) error {
-	logger := log.FromContext(ctx)
+	_, err := hau.UpdateHostsWithResult(ctx, namespace)
+	return err
+}
+
+// UpdateHostsWithResult works like UpdateHosts and additionally reports the outcome for every dogu deployment.
+// The result is returned even if the host change failed.
+func (hau *DefaultHostAliasUpdater) UpdateHostsWithResult(ctx context.Context, namespace string) (result *Result, resultErr error) {
+	result = newResult(namespace, BackendHostAliases, hau.failurePolicy)
+	defer func() {
+		result.finish(resultErr)
+	}()
+	ctx = logging.WithRun(hau.withLogger(ctx), namespace, result.RunID)
+
+	logger := log.FromContext(logging.WithPhase(ctx, logging.PhaseGenerate))
 	logger.Info("Update host entries in dogu deployments")
-	hostAliases, err := hau.generator.Generate(ctx)
+	hostAliases, err := hau.hostAliasGenerator(namespace).Generate(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to generate host aliases: %w", err)
+		return result, fmt.Errorf("failed to generate host aliases: %w", err)
 	}
+	result.HostAliases = hostAliases
 	if len(hostAliases) > 0 {
-		logger.Info(fmt.Sprintf("Use aliases: %s", hostAliases))
+		logger.Info("Use host aliases", "hostAliases", hostAliases)
 	} else {
 		logger.Info("Delete all aliases from dogu deployments")
 	}
 
-	err = hau.updateOrRollback(ctx, namespace, hostAliases)
+	if hau.maintenanceMode != nil {
+		err = hau.activateMaintenanceMode(ctx)
+		if err != nil {
+			return result, err
+		}
+		defer func() {
+			deactivateErr := hau.deactivateMaintenanceMode(ctx)
+			if deactivateErr != nil {
+				resultErr = multierror.Append(resultErr, deactivateErr)
+			}
+		}()
+	}
+
+	err = hau.updateOrRollback(ctx, namespace, hostAliases, result)
+	var partialFailure *PartialFailureError
+	if err == nil || errors.As(err, &partialFailure) {
+		verifyErr := hau.verifyUpdatedDeployments(ctx, namespace, hostAliases, result)
+		if verifyErr != nil {
+			err = multierror.Append(err, verifyErr)
+		}
+	}
 	if err != nil {
-		return err
+		return result, err
 	}
 
-	return nil
+	return result, nil
 }
 
-func (hau *DefaultHostAliasUpdater) updateOrRollback(ctx context.Context, namespace string, hostAliases []corev1.HostAlias) error {
-	logger := log.FromContext(ctx)
-	logger.Info("Fetch all dogu deployments")
-	deployments, err := hau.fetcher.FetchAll(ctx, namespace)
+func (hau *DefaultHostAliasUpdater) updateOrRollback(ctx context.Context, namespace string, hostAliases []corev1.HostAlias, result *Result) error {
+	planCtx := logging.WithPhase(ctx, logging.PhasePlan)
+	log.FromContext(planCtx).Info("Fetch all dogu deployments")
+	deployments, err := hau.fetcher.FetchAll(planCtx, namespace)
 	if err != nil {
 		return fmt.Errorf("failed to fetch dogu deployments: %w", err)
 	}
@@ -61,34 +229,218 @@ func (hau *DefaultHostAliasUpdater) updateOrRollback(ctx context.Context, namesp
 	for _, deploy := range deployments {
 		previousHostAliases[deploy.Name] = deploy.Spec.Template.Spec.HostAliases
 	}
+	result.addDeployments(deployments, hostAliases)
 
-	logger.Info("Update deployments with host aliases")
-	err = hau.updater.UpdateHostAliases(ctx, namespace, deployments, hostAliases)
+	err = hau.guardAliasRemoval(planCtx, deployments, hostAliases)
 	if err != nil {
-		logger.Error(err, "Failed to update dogu deployments: rolling back")
+		return err
+	}
+
+	waves := [][]appsv1.Deployment{deployments}
+	if hau.dependencyFetcher != nil {
+		waves, err = hau.planWaves(planCtx, namespace, deployments)
+		if err != nil {
+			return fmt.Errorf("failed to plan staged update of dogu deployments: %w", err)
+		}
+	}
+
+	report := &PartialFailureError{Policy: hau.failurePolicy}
+	if hau.canary && len(deployments) > 0 {
+		var canary string
+		canary, waves, err = hau.updateCanary(ctx, namespace, waves, hostAliases, previousHostAliases, result)
+		if err != nil {
+			return err
+		}
+		report.Updated = append(report.Updated, canary)
+	}
+
+	for i, wave := range waves {
+		if ctx.Err() != nil {
+			err = hau.rollbackOnError(ctx, namespace, hostAliases, previousHostAliases, result, ctx.Err())
+			return fmt.Errorf("host change interrupted before wave %d of %d: %w", i+1, len(waves), err)
+		}
+
+		if len(waves) > 1 {
+			log.FromContext(ctx).Info("Update wave", "wave", i+1, "waves", len(waves), "deployments", deploymentNames(wave))
+		}
+
+		if hau.keepsFailedDeployments() {
+			stop := hau.updateWaveIndividually(ctx, namespace, wave, hostAliases, report, result)
+			// the failure policy only applies to failed deployments, interrupted host changes are always rolled back
+			if ctx.Err() != nil {
+				err = hau.rollbackOnError(ctx, namespace, hostAliases, previousHostAliases, result, ctx.Err())
+				return fmt.Errorf("host change interrupted during wave %d of %d: %w", i+1, len(waves), err)
+			}
+			if stop {
+				for _, skippedWave := range waves[i+1:] {
+					report.Skipped = append(report.Skipped, deploymentNames(skippedWave)...)
+				}
+				break
+			}
+			continue
+		}
+
+		err = hau.updateWave(ctx, namespace, wave, hostAliases, previousHostAliases, result)
+		if err != nil {
+			return err
+		}
+	}
 
-		rollbackErr := hau.rollback(ctx, namespace, previousHostAliases)
-		if rollbackErr != nil {
-			err = multierror.Append(err, rollbackErr)
+	if hau.keepsFailedDeployments() {
+		logReport(ctx, report)
+		if len(report.Failed) > 0 {
+			return report
 		}
+	}
 
+	return nil
+}
+
+// updateWave updates the given deployments and waits for their rollout if configured.
+// All deployments get rolled back if the update or the rollout fails.
+func (hau *DefaultHostAliasUpdater) updateWave(ctx context.Context, namespace string, deployments []appsv1.Deployment, hostAliases []corev1.HostAlias, previousHostAliases map[string][]corev1.HostAlias, result *Result) error {
+	logger := log.FromContext(logging.WithPhase(ctx, logging.PhaseUpdate))
+	logger.Info("Update deployments with host aliases", "deployments", deploymentNames(deployments))
+	start := time.Now()
+	err := hau.updater.UpdateHostAliases(ctx, namespace, deployments, hostAliases)
+	if err != nil {
+		logger.Error(err, "Failed to update dogu deployments: rolling back")
+		result.recordUpdate(deployments, err, time.Since(start))
+
+		err = hau.rollbackOnError(ctx, namespace, hostAliases, previousHostAliases, result, err)
 		return fmt.Errorf("failed to update host-aliases of dogu deployments in cluster: %w", err)
 	}
 
+	if hau.waiter == nil {
+		result.recordAll(deployments, ActionUpdated, nil, time.Since(start))
+		return nil
+	}
+
+	rolloutCtx := logging.WithPhase(ctx, logging.PhaseRollout)
+	log.FromContext(rolloutCtx).Info("Wait for rollout of updated deployments", "deployments", deploymentNames(deployments))
+	err = hau.waiter.WaitForRollout(rolloutCtx, namespace, deployments)
+	if err != nil {
+		log.FromContext(rolloutCtx).Error(err, "Failed to roll out dogu deployments: rolling back")
+		rolloutErrs := rolloutErrorsByDeployment(err, deployments)
+		for _, deploy := range deployments {
+			if rolloutErr, failed := rolloutErrs[deploy.Name]; failed {
+				result.record(deploy.Name, ActionFailed, rolloutErr, time.Since(start))
+				continue
+			}
+			result.record(deploy.Name, ActionUpdated, nil, time.Since(start))
+		}
+
+		err = hau.rollbackOnError(ctx, namespace, hostAliases, previousHostAliases, result, err)
+		return fmt.Errorf("failed to roll out host-aliases of dogu deployments in cluster: %w", err)
+	}
+
+	result.recordAll(deployments, ActionUpdated, nil, time.Since(start))
 	return nil
 }
 
+func (hau *DefaultHostAliasUpdater) planWaves(ctx context.Context, namespace string, deployments []appsv1.Deployment) ([][]appsv1.Deployment, error) {
+	log.FromContext(ctx).Info("Fetch dogu dependencies")
+	dependencies, err := hau.dependencyFetcher.FetchDependencies(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dogu dependencies: %w", err)
+	}
+
+	return planWaves(deployments, dependencies)
+}
+
+// rollbackOnError restores the previous host aliases and appends a possible rollback error to the given error.
+// If the given context is already done, e.g. because the process is terminating, the rollback runs with a detached
+// context limited by the shutdown timeout and the resulting state of every deployment is logged afterwards.
+// Updated deployments are reported as rolled back if the rollback succeeded.
+func (hau *DefaultHostAliasUpdater) rollbackOnError(ctx context.Context, namespace string, hostAliases []corev1.HostAlias, previousHostAliases map[string][]corev1.HostAlias, result *Result, err error) error {
+	ctx = logging.WithPhase(ctx, logging.PhaseRollback)
+	interrupted := ctx.Err() != nil
+	if interrupted {
+		log.FromContext(ctx).Info("Host change interrupted: rolling back", "shutdownTimeout", hau.shutdownTimeout.String())
+
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(context.WithoutCancel(ctx), hau.shutdownTimeout)
+		defer cancel()
+	}
+
+	rollbackErr := hau.rollback(ctx, namespace, previousHostAliases)
+	if rollbackErr != nil {
+		err = multierror.Append(err, rollbackErr)
+	} else {
+		result.rolledBack()
+	}
+
+	if interrupted {
+		hau.logDeploymentStates(ctx, namespace, hostAliases, previousHostAliases)
+	}
+
+	return err
+}
+
 func (hau *DefaultHostAliasUpdater) rollback(ctx context.Context, namespace string, previousHostAliases map[string][]corev1.HostAlias) error {
 	deployments, err := hau.fetcher.FetchAll(ctx, namespace)
 	if err != nil {
 		return fmt.Errorf("failed to fetch dogu deployments on rollback: %w", err)
 	}
+	if len(deployments) == 0 {
+		return nil
+	}
 
-	// We can select the aliases by the first deployment name because all host aliases must be equal.
-	err = hau.updater.UpdateHostAliases(ctx, namespace, deployments, previousHostAliases[deployments[0].Name])
-	if err != nil {
-		return fmt.Errorf("failed to rollback dogu deployments: %w", err)
+	// every deployment gets its own previous host aliases back because they may differ, e.g. after a drift
+	var multiErr error
+	for _, group := range groupByPreviousHostAliases(deployments, previousHostAliases) {
+		err = hau.restoreHostAliases(ctx, namespace, group.deployments, group.hostAliases)
+		if err != nil {
+			multiErr = multierror.Append(multiErr, err)
+		}
+	}
+	if multiErr != nil {
+		return fmt.Errorf("failed to rollback dogu deployments: %w", multiErr)
 	}
 
 	return nil
 }
+
+// restoreHostAliases gives the given deployments their previous host aliases back. It prefers a DeploymentRestorer,
+// so that restoring is not recorded as another host change.
+func (hau *DefaultHostAliasUpdater) restoreHostAliases(ctx context.Context, namespace string, deployments []appsv1.Deployment, aliases []corev1.HostAlias) error {
+	restorer, ok := hau.updater.(DeploymentRestorer)
+	if ok {
+		return restorer.RestoreHostAliases(ctx, namespace, deployments, aliases)
+	}
+
+	return hau.updater.UpdateHostAliases(ctx, namespace, deployments, aliases)
+}
+
+type rollbackGroup struct {
+	hostAliases []corev1.HostAlias
+	deployments []appsv1.Deployment
+}
+
+// groupByPreviousHostAliases groups the given deployments by their previous host aliases, so that deployments with
+// equal host aliases are rolled back together. Deployments without previous host aliases were not part of the host
+// change and are left out.
+func groupByPreviousHostAliases(deployments []appsv1.Deployment, previousHostAliases map[string][]corev1.HostAlias) []rollbackGroup {
+	var groups []rollbackGroup
+	for _, deploy := range deployments {
+		previous, found := previousHostAliases[deploy.Name]
+		if !found {
+			continue
+		}
+
+		i := slices.IndexFunc(groups, func(group rollbackGroup) bool {
+			return slices.EqualFunc(group.hostAliases, previous, equalHostAlias)
+		})
+		if i < 0 {
+			groups = append(groups, rollbackGroup{hostAliases: previous})
+			i = len(groups) - 1
+		}
+		groups[i].deployments = append(groups[i].deployments, deploy)
+	}
+
+	return groups
+}
+
+func equalHostAlias(a corev1.HostAlias, b corev1.HostAlias) bool {
+	return a.IP == b.IP && slices.Equal(a.Hostnames, b.Hostnames)
+}
@@ -3,6 +3,7 @@ package hosts
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -143,93 +144,294 @@ func Test_hostAliasUpdater_UpdateHosts(t *testing.T) {
 		// when
 		err := sut.UpdateHosts(ctx, testNamespace)
 
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should fail to roll out dogu deployments and roll back", func(t *testing.T) {
+		// given
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := succeedingDoguDeploymentFetcherOnRollback(t)
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, doguDeployments, hostAliases).Return(nil).Once()
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, doguDeployments, mock.Anything).Return(nil).Once()
+		waiter := NewMockRolloutWaiter(t)
+		waiter.EXPECT().WaitForRollout(mock.Anything, testNamespace, doguDeployments).Return(assert.AnError)
+		ctx := context.TODO()
+		sut := &DefaultHostAliasUpdater{
+			generator: generator,
+			fetcher:   fetcher,
+			updater:   updater,
+			waiter:    waiter,
+		}
+
+		// when
+		err := sut.UpdateHosts(ctx, testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to roll out host-aliases of dogu deployments in cluster")
+	})
+	t.Run("should roll back every dogu deployment to its own previous host aliases", func(t *testing.T) {
+		// given
+		casAliases := []corev1.HostAlias{{IP: "4.3.2.1", Hostnames: []string{"old.example.com"}}}
+		ldapAliases := []corev1.HostAlias{{IP: "4.3.2.2", Hostnames: []string{"drifted.example.com"}}}
+		cas := doguDeployment("cas")
+		cas.Spec.Template.Spec.HostAliases = casAliases
+		ldap := doguDeployment("ldap")
+		ldap.Spec.Template.Spec.HostAliases = ldapAliases
+		nginx := doguDeployment("nginx")
+		nginx.Spec.Template.Spec.HostAliases = casAliases
+		deployments := []appsv1.Deployment{cas, ldap, nginx}
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(deployments, nil).Twice()
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, deployments, hostAliases).Return(nil).Once()
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{cas, nginx}, casAliases).Return(nil).Once()
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{ldap}, ldapAliases).Return(nil).Once()
+		waiter := NewMockRolloutWaiter(t)
+		waiter.EXPECT().WaitForRollout(mock.Anything, testNamespace, deployments).Return(assert.AnError)
+		sut := &DefaultHostAliasUpdater{
+			generator:           succeedingHostAliasGenerator(t),
+			fetcher:             fetcher,
+			updater:             updater,
+			waiter:              waiter,
+			confirmAliasRemoval: true,
+		}
+
+		// when
+		err := sut.UpdateHosts(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+	t.Run("should fail to fetch dogu dependencies for staged update", func(t *testing.T) {
+		// given
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := succeedingDoguDeploymentFetcher(t)
+		dependencyFetcher := NewMockDependencyFetcher(t)
+		dependencyFetcher.EXPECT().FetchDependencies(mock.Anything, testNamespace).Return(nil, assert.AnError)
+		ctx := context.TODO()
+		sut := &DefaultHostAliasUpdater{
+			generator:         generator,
+			fetcher:           fetcher,
+			dependencyFetcher: dependencyFetcher,
+		}
+
+		// when
+		err := sut.UpdateHosts(ctx, testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to plan staged update of dogu deployments: failed to fetch dogu dependencies")
+	})
+	t.Run("should update and roll out deployments in waves", func(t *testing.T) {
+		// given
+		ldap := doguDeployment("ldap")
+		cas := doguDeployment("cas")
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return([]appsv1.Deployment{cas, ldap}, nil)
+		dependencyFetcher := NewMockDependencyFetcher(t)
+		dependencyFetcher.EXPECT().FetchDependencies(mock.Anything, testNamespace).Return(map[string][]string{"cas": {"ldap"}}, nil)
+		updater := NewMockDeploymentUpdater(t)
+		waiter := NewMockRolloutWaiter(t)
+		firstWave := updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{ldap}, hostAliases).Return(nil).Once()
+		firstRollout := waiter.EXPECT().WaitForRollout(mock.Anything, testNamespace, []appsv1.Deployment{ldap}).Return(nil).Once().NotBefore(firstWave)
+		secondWave := updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{cas}, hostAliases).Return(nil).Once().NotBefore(firstRollout)
+		waiter.EXPECT().WaitForRollout(mock.Anything, testNamespace, []appsv1.Deployment{cas}).Return(nil).Once().NotBefore(secondWave)
+		ctx := context.TODO()
+		sut := &DefaultHostAliasUpdater{
+			generator:         generator,
+			fetcher:           fetcher,
+			updater:           updater,
+			waiter:            waiter,
+			dependencyFetcher: dependencyFetcher,
+		}
+
+		// when
+		err := sut.UpdateHosts(ctx, testNamespace)
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should roll back and stop updating if interrupted", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(doguDeployments, nil).Times(3)
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, doguDeployments, []corev1.HostAlias(nil)).
+			RunAndReturn(func(ctx context.Context, _ string, _ []appsv1.Deployment, _ []corev1.HostAlias) error {
+				return ctx.Err()
+			}).Once()
+		sut := &DefaultHostAliasUpdater{
+			generator:       generator,
+			fetcher:         fetcher,
+			updater:         updater,
+			shutdownTimeout: time.Second,
+		}
+
+		// when
+		err := sut.UpdateHosts(ctx, testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorContains(t, err, "host change interrupted before wave 1 of 1")
+	})
+	t.Run("should succeed after waiting for rollout", func(t *testing.T) {
+		// given
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := succeedingDoguDeploymentFetcher(t)
+		updater := succeedingDeploymentUpdater(t)
+		waiter := NewMockRolloutWaiter(t)
+		waiter.EXPECT().WaitForRollout(mock.Anything, testNamespace, doguDeployments).Return(nil)
+		ctx := context.TODO()
+		sut := &DefaultHostAliasUpdater{
+			generator: generator,
+			fetcher:   fetcher,
+			updater:   updater,
+			waiter:    waiter,
+		}
+
+		// when
+		err := sut.UpdateHosts(ctx, testNamespace)
+
 		// then
 		require.NoError(t, err)
 	})
 }
 
-func failingHostAliasGenerator(t *testing.T) hostAliasGenerator {
+func failingHostAliasGenerator(t *testing.T) HostAliasGenerator {
 	t.Helper()
-	generator := newMockHostAliasGenerator(t)
+	generator := NewMockHostAliasGenerator(t)
 	generator.EXPECT().Generate(mock.Anything).Return(nil, assert.AnError).Once()
 	return generator
 }
 
-func succeedingHostAliasGenerator(t *testing.T) hostAliasGenerator {
+func succeedingHostAliasGenerator(t *testing.T) HostAliasGenerator {
 	t.Helper()
-	generator := newMockHostAliasGenerator(t)
+	generator := NewMockHostAliasGenerator(t)
 	generator.EXPECT().Generate(mock.Anything).Return(hostAliases, nil).Once()
 	return generator
 }
 
-func failingDoguDeploymentFetcher(t *testing.T) doguDeploymentFetcher {
+func failingDoguDeploymentFetcher(t *testing.T) DeploymentFetcher {
 	t.Helper()
-	fetcher := newMockDoguDeploymentFetcher(t)
-	fetcher.EXPECT().FetchAll(context.TODO(), testNamespace).Return(nil, assert.AnError).Once()
+	fetcher := NewMockDeploymentFetcher(t)
+	fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(nil, assert.AnError).Once()
 	return fetcher
 }
 
-func succeedingDoguDeploymentFetcher(t *testing.T) doguDeploymentFetcher {
+func succeedingDoguDeploymentFetcher(t *testing.T) DeploymentFetcher {
 	t.Helper()
-	fetcher := newMockDoguDeploymentFetcher(t)
-	fetcher.EXPECT().FetchAll(context.TODO(), testNamespace).Return(doguDeployments, nil).Once()
+	fetcher := NewMockDeploymentFetcher(t)
+	fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(doguDeployments, nil).Once()
 	return fetcher
 }
 
-func succeedingDoguDeploymentFetcherOnRollback(t *testing.T) doguDeploymentFetcher {
+func succeedingDoguDeploymentFetcherOnRollback(t *testing.T) DeploymentFetcher {
 	t.Helper()
-	fetcher := newMockDoguDeploymentFetcher(t)
-	fetcher.EXPECT().FetchAll(context.TODO(), testNamespace).Return(doguDeployments, nil).Once()
-	fetcher.EXPECT().FetchAll(context.TODO(), testNamespace).Return(doguDeployments, nil).Once()
+	fetcher := NewMockDeploymentFetcher(t)
+	fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(doguDeployments, nil).Once()
+	fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(doguDeployments, nil).Once()
 	return fetcher
 }
 
-func failingDoguDeploymentFetcherOnRollback(t *testing.T) doguDeploymentFetcher {
+func failingDoguDeploymentFetcherOnRollback(t *testing.T) DeploymentFetcher {
 	t.Helper()
-	fetcher := newMockDoguDeploymentFetcher(t)
-	fetcher.EXPECT().FetchAll(context.TODO(), testNamespace).Return(doguDeployments, nil).Once()
-	fetcher.EXPECT().FetchAll(context.TODO(), testNamespace).Return(nil, assert.AnError).Once()
+	fetcher := NewMockDeploymentFetcher(t)
+	fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(doguDeployments, nil).Once()
+	fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(nil, assert.AnError).Once()
 	return fetcher
 }
 
-func failingDeploymentUpdater(t *testing.T) deploymentUpdater {
+func failingDeploymentUpdater(t *testing.T) DeploymentUpdater {
 	t.Helper()
-	updater := newMockDeploymentUpdater(t)
-	updater.EXPECT().UpdateHostAliases(context.TODO(), testNamespace, doguDeployments, hostAliases).Return(assert.AnError).Once()
-	updater.EXPECT().UpdateHostAliases(context.TODO(), testNamespace, doguDeployments, mock.Anything).Return(nil).Once()
+	updater := NewMockDeploymentUpdater(t)
+	updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, doguDeployments, hostAliases).Return(assert.AnError).Once()
+	updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, doguDeployments, mock.Anything).Return(nil).Once()
 	return updater
 }
 
-func failingDeploymentUpdaterCallOnce(t *testing.T) deploymentUpdater {
+func failingDeploymentUpdaterCallOnce(t *testing.T) DeploymentUpdater {
 	t.Helper()
-	updater := newMockDeploymentUpdater(t)
-	updater.EXPECT().UpdateHostAliases(context.TODO(), testNamespace, doguDeployments, hostAliases).Return(assert.AnError).Once()
+	updater := NewMockDeploymentUpdater(t)
+	updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, doguDeployments, hostAliases).Return(assert.AnError).Once()
 	return updater
 }
 
-func failingDeploymentUpdaterOnRollback(t *testing.T) deploymentUpdater {
+func failingDeploymentUpdaterOnRollback(t *testing.T) DeploymentUpdater {
 	t.Helper()
-	updater := newMockDeploymentUpdater(t)
-	updater.EXPECT().UpdateHostAliases(context.TODO(), testNamespace, doguDeployments, hostAliases).Return(assert.AnError).Once()
-	updater.EXPECT().UpdateHostAliases(context.TODO(), testNamespace, doguDeployments, mock.Anything).Return(assert.AnError).Once()
+	updater := NewMockDeploymentUpdater(t)
+	updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, doguDeployments, hostAliases).Return(assert.AnError).Once()
+	updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, doguDeployments, mock.Anything).Return(assert.AnError).Once()
 	return updater
 }
 
-func succeedingDeploymentUpdater(t *testing.T) deploymentUpdater {
+func succeedingDeploymentUpdater(t *testing.T) DeploymentUpdater {
 	t.Helper()
-	updater := newMockDeploymentUpdater(t)
-	updater.EXPECT().UpdateHostAliases(context.TODO(), testNamespace, doguDeployments, hostAliases).Return(nil).Once()
+	updater := NewMockDeploymentUpdater(t)
+	updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, doguDeployments, hostAliases).Return(nil).Once()
 	return updater
 }
 
 func TestNewHostAliasUpdater(t *testing.T) {
-	// given
-	clientSet := fake.NewSimpleClientset()
-	generatorMock := newMockHostAliasGenerator(t)
+	t.Run("should create updater without rollout waiter", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		generatorMock := NewMockHostAliasGenerator(t)
 
-	// when
-	updater := NewHostAliasUpdater(clientSet, generatorMock)
+		// when
+		updater := NewHostAliasUpdater(clientSet, generatorMock)
 
-	// then
-	require.NotNil(t, updater)
+		// then
+		require.NotNil(t, updater)
+		assert.Nil(t, updater.waiter)
+		assert.Equal(t, defaultShutdownTimeout, updater.shutdownTimeout)
+	})
+	t.Run("should create updater with rollout waiter", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		generatorMock := NewMockHostAliasGenerator(t)
+
+		// when
+		updater := NewUpdater(clientSet, WithGenerator(generatorMock), WithOptions(Options{WaitForRollout: true}))
+
+		// then
+		require.NotNil(t, updater)
+		assert.NotNil(t, updater.waiter)
+		assert.Nil(t, updater.dependencyFetcher)
+	})
+	t.Run("should create updater with dependency fetcher and rollout waiter for staged restart", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		generatorMock := NewMockHostAliasGenerator(t)
+
+		// when
+		updater := NewUpdater(clientSet, WithGenerator(generatorMock), WithOptions(Options{StagedRestart: true}))
+
+		// then
+		require.NotNil(t, updater)
+		assert.NotNil(t, updater.waiter)
+		assert.NotNil(t, updater.dependencyFetcher)
+	})
+	t.Run("should create updater with pod verifier", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		generatorMock := NewMockHostAliasGenerator(t)
+
+		// when
+		updater := NewUpdater(clientSet, WithGenerator(generatorMock), WithOptions(Options{VerifyPods: true, PodVerificationTimeout: time.Minute}))
+
+		// then
+		require.NotNil(t, updater)
+		assert.NotNil(t, updater.podVerifier)
+		assert.True(t, updater.waitForStalePods)
+	})
 }
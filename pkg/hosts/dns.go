@@ -0,0 +1,129 @@
+package hosts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-logr/logr"
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/k8s-host-change/pkg/deployment"
+	"github.com/cloudogu/k8s-host-change/pkg/dogu"
+	"github.com/cloudogu/k8s-host-change/pkg/logging"
+)
+
+// DNSUpdater publishes the host aliases to the cluster DNS instead of the dogu deployments, so that the dogus resolve
+// changed host names without a restart. Host aliases left in the dogu deployments take precedence over the cluster DNS,
+// so they are removed once. Create it with NewDNSUpdater.
+type DNSUpdater struct {
+	// clientSet is used by the default generator to read the global config.
+	clientSet kubernetes.Interface
+	// generator is nil if the host aliases should be generated from the global config of the namespace.
+	generator HostAliasGenerator
+	publisher DNSPublisher
+	fetcher   DeploymentFetcher
+	updater   DeploymentUpdater
+	// logger is nil if the logger of the context should be used.
+	logger *logr.Logger
+}
+
+// NewDNSUpdater creates a DNSUpdater which publishes the host aliases with the given publisher. Without options it
+// generates the host aliases from the global config of the namespace passed to its methods.
+func NewDNSUpdater(clientSet kubernetes.Interface, publisher DNSPublisher, updaterOpts ...UpdaterOption) *DNSUpdater {
+	cfg := &updaterConfig{}
+	for _, updaterOpt := range updaterOpts {
+		updaterOpt(cfg)
+	}
+
+	du := &DNSUpdater{
+		clientSet: clientSet,
+		generator: cfg.generator,
+		publisher: publisher,
+		fetcher:   cfg.fetcher,
+		updater:   cfg.updater,
+		logger:    cfg.logger,
+	}
+
+	if du.fetcher == nil {
+		du.fetcher = dogu.NewDeploymentFetcherWithSelector(clientSet, cfg.selector)
+	}
+
+	if du.updater == nil {
+		du.updater = deployment.NewUpdater(clientSet)
+	}
+
+	return du
+}
+
+// UpdateHostsWithResult publishes the host aliases generated for the given namespace and removes the host aliases from
+// the dogu deployments which still carry them afterwards. The result only contains these deployments. It is returned
+// even if the host change failed.
+func (du *DNSUpdater) UpdateHostsWithResult(ctx context.Context, namespace string) (result *Result, resultErr error) {
+	result = newResult(namespace, BackendCoreDNS, "")
+	result.DNSConfigMap = du.publisher.ConfigMap()
+	defer func() {
+		result.finish(resultErr)
+	}()
+	ctx = logging.WithRun(withLogger(ctx, du.logger), namespace, result.RunID)
+
+	logger := log.FromContext(logging.WithPhase(ctx, logging.PhaseGenerate))
+	logger.Info("Publish host entries to the cluster DNS", "configMap", result.DNSConfigMap)
+	hostAliases, err := hostAliasGenerator(du.clientSet, du.generator, namespace).Generate(ctx)
+	if err != nil {
+		return result, fmt.Errorf("failed to generate host aliases: %w", err)
+	}
+	result.HostAliases = hostAliases
+
+	logger = log.FromContext(logging.WithPhase(ctx, logging.PhaseUpdate))
+	if len(hostAliases) > 0 {
+		logger.Info("Use host aliases", "hostAliases", hostAliases)
+	} else {
+		logger.Info("Delete all aliases from the cluster DNS")
+	}
+	err = du.publisher.Publish(ctx, namespace, hostAliases)
+	if err != nil {
+		return result, err
+	}
+
+	err = du.removeHostAliases(ctx, namespace, result)
+	if err != nil {
+		return result, err
+	}
+
+	return result, nil
+}
+
+// removeHostAliases removes the host aliases from all dogu deployments which carry them, because they would take
+// precedence over the cluster DNS. This restarts these dogus once.
+func (du *DNSUpdater) removeHostAliases(ctx context.Context, namespace string, result *Result) error {
+	ctx = logging.WithPhase(ctx, logging.PhaseUpdate)
+	deployments, err := du.fetcher.FetchAll(ctx, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to fetch dogu deployments: %w", err)
+	}
+
+	var withHostAliases []appsv1.Deployment
+	for _, deploy := range deployments {
+		if len(deploy.Spec.Template.Spec.HostAliases) > 0 {
+			withHostAliases = append(withHostAliases, deploy)
+		}
+	}
+	if len(withHostAliases) == 0 {
+		return nil
+	}
+
+	log.FromContext(ctx).Info("Remove host aliases which take precedence over the cluster DNS from dogu deployments",
+		"deployments", deploymentNames(withHostAliases))
+	result.addDeployments(withHostAliases, nil)
+	start := time.Now()
+	err = du.updater.UpdateHostAliases(ctx, namespace, withHostAliases, nil)
+	result.recordUpdate(withHostAliases, err, time.Since(start))
+	if err != nil {
+		return fmt.Errorf("failed to remove host aliases from dogu deployments: %w", err)
+	}
+
+	return nil
+}
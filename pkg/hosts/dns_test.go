@@ -0,0 +1,131 @@
+package hosts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+const testDNSConfigMap = "kube-system/coredns-custom"
+
+func TestDNSUpdater_UpdateHostsWithResult(t *testing.T) {
+	hostAliases := []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}}}
+
+	t.Run("should publish host aliases", func(t *testing.T) {
+		// given
+		generatorMock := NewMockHostAliasGenerator(t)
+		generatorMock.EXPECT().Generate(mock.Anything).Return(hostAliases, nil)
+		publisherMock := NewMockDNSPublisher(t)
+		publisherMock.EXPECT().ConfigMap().Return(testDNSConfigMap)
+		publisherMock.EXPECT().Publish(mock.Anything, testNamespace, hostAliases).Return(nil)
+		fetcherMock := NewMockDeploymentFetcher(t)
+		fetcherMock.EXPECT().FetchAll(mock.Anything, testNamespace).Return([]appsv1.Deployment{doguDeployment("cas")}, nil)
+		sut := NewDNSUpdater(fake.NewSimpleClientset(), publisherMock, WithGenerator(generatorMock), WithDeploymentFetcher(fetcherMock))
+
+		// when
+		result, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, StatusSucceeded, result.Status)
+		assert.Equal(t, BackendCoreDNS, result.Backend)
+		assert.Equal(t, testDNSConfigMap, result.DNSConfigMap)
+		assert.Equal(t, hostAliases, result.HostAliases)
+		assert.Empty(t, result.Deployments)
+	})
+	t.Run("should remove host aliases left in dogu deployments", func(t *testing.T) {
+		// given
+		cas := doguDeployment("cas")
+		cas.Spec.Template.Spec.HostAliases = hostAliases
+		ldap := doguDeployment("ldap")
+		generatorMock := NewMockHostAliasGenerator(t)
+		generatorMock.EXPECT().Generate(mock.Anything).Return(hostAliases, nil)
+		publisherMock := NewMockDNSPublisher(t)
+		publisherMock.EXPECT().ConfigMap().Return(testDNSConfigMap)
+		publisherMock.EXPECT().Publish(mock.Anything, testNamespace, hostAliases).Return(nil)
+		fetcherMock := NewMockDeploymentFetcher(t)
+		fetcherMock.EXPECT().FetchAll(mock.Anything, testNamespace).Return([]appsv1.Deployment{cas, ldap}, nil)
+		updaterMock := NewMockDeploymentUpdater(t)
+		updaterMock.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{cas}, []corev1.HostAlias(nil)).Return(nil)
+		sut := NewDNSUpdater(fake.NewSimpleClientset(), publisherMock,
+			WithGenerator(generatorMock), WithDeploymentFetcher(fetcherMock), WithDeploymentUpdater(updaterMock))
+
+		// when
+		result, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, result.Deployments, 1)
+		assert.Equal(t, "cas", result.Deployments[0].Name)
+		assert.Equal(t, ActionUpdated, result.Deployments[0].Action)
+		assert.Equal(t, hostAliases, result.Deployments[0].PreviousHostAliases)
+		assert.Empty(t, result.Deployments[0].NewHostAliases)
+	})
+	t.Run("should fail to remove host aliases left in dogu deployments", func(t *testing.T) {
+		// given
+		cas := doguDeployment("cas")
+		cas.Spec.Template.Spec.HostAliases = hostAliases
+		generatorMock := NewMockHostAliasGenerator(t)
+		generatorMock.EXPECT().Generate(mock.Anything).Return(hostAliases, nil)
+		publisherMock := NewMockDNSPublisher(t)
+		publisherMock.EXPECT().ConfigMap().Return(testDNSConfigMap)
+		publisherMock.EXPECT().Publish(mock.Anything, testNamespace, hostAliases).Return(nil)
+		fetcherMock := NewMockDeploymentFetcher(t)
+		fetcherMock.EXPECT().FetchAll(mock.Anything, testNamespace).Return([]appsv1.Deployment{cas}, nil)
+		updaterMock := NewMockDeploymentUpdater(t)
+		updaterMock.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{cas}, []corev1.HostAlias(nil)).Return(assert.AnError)
+		sut := NewDNSUpdater(fake.NewSimpleClientset(), publisherMock,
+			WithGenerator(generatorMock), WithDeploymentFetcher(fetcherMock), WithDeploymentUpdater(updaterMock))
+
+		// when
+		result, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to remove host aliases from dogu deployments")
+		assert.Equal(t, StatusFailed, result.Status)
+		assert.Equal(t, ActionFailed, result.Deployments[0].Action)
+	})
+	t.Run("should fail to generate host aliases", func(t *testing.T) {
+		// given
+		generatorMock := NewMockHostAliasGenerator(t)
+		generatorMock.EXPECT().Generate(mock.Anything).Return(nil, assert.AnError)
+		publisherMock := NewMockDNSPublisher(t)
+		publisherMock.EXPECT().ConfigMap().Return(testDNSConfigMap)
+		sut := NewDNSUpdater(fake.NewSimpleClientset(), publisherMock, WithGenerator(generatorMock))
+
+		// when
+		result, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to generate host aliases")
+		assert.Equal(t, StatusFailed, result.Status)
+	})
+	t.Run("should fail to publish host aliases", func(t *testing.T) {
+		// given
+		generatorMock := NewMockHostAliasGenerator(t)
+		generatorMock.EXPECT().Generate(mock.Anything).Return(hostAliases, nil)
+		publisherMock := NewMockDNSPublisher(t)
+		publisherMock.EXPECT().ConfigMap().Return(testDNSConfigMap)
+		publisherMock.EXPECT().Publish(mock.Anything, testNamespace, hostAliases).Return(assert.AnError)
+		sut := NewDNSUpdater(fake.NewSimpleClientset(), publisherMock, WithGenerator(generatorMock))
+
+		// when
+		result, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.Equal(t, StatusFailed, result.Status)
+		assert.Equal(t, assert.AnError.Error(), result.Error)
+	})
+}
@@ -0,0 +1,34 @@
+package hosts
+
+import (
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/cloudogu/k8s-host-change/pkg/deployment"
+)
+
+// Drift describes how the host aliases of a dogu deployment differ from the host aliases generated from the global
+// config.
+type Drift struct {
+	Name string `json:"name"`
+	// MissingHostAliases are the generated host names which the deployment does not carry.
+	MissingHostAliases []corev1.HostAlias `json:"missingHostAliases,omitempty"`
+	// UnexpectedHostAliases are the host names of the deployment which were not generated.
+	UnexpectedHostAliases []corev1.HostAlias `json:"unexpectedHostAliases,omitempty"`
+}
+
+// Drift returns the differences of all deployments whose host aliases differ from the generated host aliases.
+func (p *Plan) Drift() []Drift {
+	drift := []Drift{}
+	for _, deploy := range p.Deployments {
+		if !deploy.Changed {
+			continue
+		}
+		drift = append(drift, Drift{
+			Name:                  deploy.Name,
+			MissingHostAliases:    deployment.SubtractHostAliases(p.HostAliases, deploy.CurrentHostAliases),
+			UnexpectedHostAliases: deployment.SubtractHostAliases(deploy.CurrentHostAliases, p.HostAliases),
+		})
+	}
+
+	return drift
+}
@@ -0,0 +1,53 @@
+package hosts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestPlan_Drift(t *testing.T) {
+	t.Run("should return missing and unexpected host names of changed deployments", func(t *testing.T) {
+		// given
+		sut := &Plan{
+			HostAliases: []corev1.HostAlias{
+				{IP: "10.0.0.1", Hostnames: []string{"ces.example.com", "fqdn.example.com"}},
+				{IP: "10.0.0.2", Hostnames: []string{"git.example.com"}},
+			},
+			Deployments: []PlannedDeployment{
+				{Name: "cas", CurrentHostAliases: []corev1.HostAlias{
+					{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}},
+					{IP: "10.0.0.9", Hostnames: []string{"old.example.com", "git.example.com"}},
+				}, Changed: true},
+				{Name: "ldap"},
+			},
+		}
+
+		// when
+		actual := sut.Drift()
+
+		// then
+		assert.Equal(t, []Drift{{
+			Name: "cas",
+			MissingHostAliases: []corev1.HostAlias{
+				{IP: "10.0.0.1", Hostnames: []string{"fqdn.example.com"}},
+				{IP: "10.0.0.2", Hostnames: []string{"git.example.com"}},
+			},
+			UnexpectedHostAliases: []corev1.HostAlias{
+				{IP: "10.0.0.9", Hostnames: []string{"old.example.com", "git.example.com"}},
+			},
+		}}, actual)
+	})
+	t.Run("should return empty drift", func(t *testing.T) {
+		// given
+		sut := &Plan{Deployments: []PlannedDeployment{{Name: "cas"}}}
+
+		// when
+		actual := sut.Drift()
+
+		// then
+		assert.Empty(t, actual)
+	})
+}
@@ -0,0 +1,84 @@
+package hosts
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// guardAliasRemoval refuses host changes that remove all host names or more than the configured share of host names
+// from the dogu deployments unless the removal was confirmed explicitly. Changing the IP of a host name does not count
+// as removal.
+func (hau *DefaultHostAliasUpdater) guardAliasRemoval(ctx context.Context, deployments []appsv1.Deployment, hostAliases []corev1.HostAlias) error {
+	removed, existing := removedHostnames(deployments, hostAliases)
+	if len(removed) == 0 {
+		return nil
+	}
+
+	removesAll := len(removed) == existing
+	share := float64(len(removed)) / float64(existing)
+	if !removesAll && (hau.maxAliasRemovalShare == 0 || share <= hau.maxAliasRemovalShare) {
+		return nil
+	}
+
+	confirmed, err := hau.isAliasRemovalConfirmed(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to check confirmation of host alias removal: %w", err)
+	}
+	if confirmed {
+		log.FromContext(ctx).Info("Removal of host names confirmed", "hostnames", removed)
+		return nil
+	}
+
+	if removesAll {
+		return fmt.Errorf("refusing to remove all %d host names %v from dogu deployments: confirm the removal explicitly to proceed", existing, removed)
+	}
+
+	return fmt.Errorf("refusing to remove %d of %d host names %v from dogu deployments which exceeds the allowed share of %.0f%%: confirm the removal explicitly to proceed",
+		len(removed), existing, removed, hau.maxAliasRemovalShare*100)
+}
+
+func (hau *DefaultHostAliasUpdater) isAliasRemovalConfirmed(ctx context.Context) (bool, error) {
+	if hau.confirmAliasRemoval {
+		return true, nil
+	}
+	if hau.removalConfirmation == nil {
+		return false, nil
+	}
+
+	return hau.removalConfirmation.IsAliasRemovalConfirmed(ctx)
+}
+
+// removedHostnames returns the sorted host names which are present in any deployment but not in the given host aliases
+// and the number of distinct host names present in the deployments.
+func removedHostnames(deployments []appsv1.Deployment, hostAliases []corev1.HostAlias) ([]string, int) {
+	existing := map[string]bool{}
+	for _, deploy := range deployments {
+		for _, alias := range deploy.Spec.Template.Spec.HostAliases {
+			for _, hostname := range alias.Hostnames {
+				existing[hostname] = true
+			}
+		}
+	}
+
+	desired := map[string]bool{}
+	for _, alias := range hostAliases {
+		for _, hostname := range alias.Hostnames {
+			desired[hostname] = true
+		}
+	}
+
+	var removed []string
+	for hostname := range existing {
+		if !desired[hostname] {
+			removed = append(removed, hostname)
+		}
+	}
+	sort.Strings(removed)
+
+	return removed, len(existing)
+}
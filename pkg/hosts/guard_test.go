@@ -0,0 +1,156 @@
+package hosts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+var existingAliases = []corev1.HostAlias{
+	{IP: "10.0.0.1", Hostnames: []string{"ecosystem.example.com"}},
+	{IP: "10.0.0.2", Hostnames: []string{"db.example.com", "database.example.com"}},
+	{IP: "10.0.0.3", Hostnames: []string{"mail.example.com"}},
+}
+
+func deploymentsWithAliases(aliases []corev1.HostAlias) []appsv1.Deployment {
+	cas := doguDeployment("cas")
+	cas.Spec.Template.Spec.HostAliases = aliases
+	ldap := doguDeployment("ldap")
+	ldap.Spec.Template.Spec.HostAliases = aliases
+	return []appsv1.Deployment{cas, ldap}
+}
+
+func Test_removedHostnames(t *testing.T) {
+	t.Run("should not count changed ips as removal", func(t *testing.T) {
+		// given
+		desired := []corev1.HostAlias{
+			{IP: "10.0.0.9", Hostnames: []string{"ecosystem.example.com"}},
+			{IP: "10.0.0.2", Hostnames: []string{"db.example.com"}},
+		}
+
+		// when
+		removed, existing := removedHostnames(deploymentsWithAliases(existingAliases), desired)
+
+		// then
+		assert.Equal(t, []string{"database.example.com", "mail.example.com"}, removed)
+		assert.Equal(t, 4, existing)
+	})
+	t.Run("should return nothing without existing aliases", func(t *testing.T) {
+		// when
+		removed, existing := removedHostnames(doguDeployments, hostAliases)
+
+		// then
+		assert.Empty(t, removed)
+		assert.Zero(t, existing)
+	})
+}
+
+func Test_hostAliasUpdater_guardAliasRemoval(t *testing.T) {
+	deployments := deploymentsWithAliases(existingAliases)
+
+	t.Run("should allow removal within share", func(t *testing.T) {
+		// given
+		desired := existingAliases[:2]
+		sut := &DefaultHostAliasUpdater{maxAliasRemovalShare: 0.5}
+
+		// when
+		err := sut.guardAliasRemoval(context.TODO(), deployments, desired)
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should allow any partial removal without share", func(t *testing.T) {
+		// given
+		desired := existingAliases[:1]
+		sut := &DefaultHostAliasUpdater{}
+
+		// when
+		err := sut.guardAliasRemoval(context.TODO(), deployments, desired)
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should refuse removal beyond share", func(t *testing.T) {
+		// given
+		desired := existingAliases[:1]
+		sut := &DefaultHostAliasUpdater{maxAliasRemovalShare: 0.5}
+
+		// when
+		err := sut.guardAliasRemoval(context.TODO(), deployments, desired)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "refusing to remove 3 of 4 host names [database.example.com db.example.com mail.example.com] from dogu deployments which exceeds the allowed share of 50%")
+	})
+	t.Run("should refuse removal of all host names", func(t *testing.T) {
+		// given
+		sut := &DefaultHostAliasUpdater{}
+
+		// when
+		err := sut.guardAliasRemoval(context.TODO(), deployments, nil)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "refusing to remove all 4 host names [database.example.com db.example.com ecosystem.example.com mail.example.com] from dogu deployments")
+	})
+	t.Run("should allow removal of all host names if confirmed by option", func(t *testing.T) {
+		// given
+		sut := &DefaultHostAliasUpdater{confirmAliasRemoval: true}
+
+		// when
+		err := sut.guardAliasRemoval(context.TODO(), deployments, nil)
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should allow removal of all host names if confirmed by confirmation", func(t *testing.T) {
+		// given
+		confirmation := NewMockAliasRemovalConfirmation(t)
+		confirmation.EXPECT().IsAliasRemovalConfirmed(mock.Anything).Return(true, nil)
+		sut := &DefaultHostAliasUpdater{removalConfirmation: confirmation}
+
+		// when
+		err := sut.guardAliasRemoval(context.TODO(), deployments, nil)
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should fail to check confirmation", func(t *testing.T) {
+		// given
+		confirmation := NewMockAliasRemovalConfirmation(t)
+		confirmation.EXPECT().IsAliasRemovalConfirmed(mock.Anything).Return(false, assert.AnError)
+		sut := &DefaultHostAliasUpdater{removalConfirmation: confirmation}
+
+		// when
+		err := sut.guardAliasRemoval(context.TODO(), deployments, nil)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to check confirmation of host alias removal")
+	})
+}
+
+func Test_hostAliasUpdater_UpdateHosts_guard(t *testing.T) {
+	t.Run("should not update deployments if all aliases would be removed", func(t *testing.T) {
+		// given
+		generator := NewMockHostAliasGenerator(t)
+		generator.EXPECT().Generate(mock.Anything).Return(nil, nil)
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(deploymentsWithAliases(existingAliases), nil)
+		sut := &DefaultHostAliasUpdater{generator: generator, fetcher: fetcher}
+
+		// when
+		err := sut.UpdateHosts(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "refusing to remove all 4 host names")
+	})
+}
@@ -2,21 +2,91 @@ package hosts
 
 import (
 	"context"
+
+	"github.com/cloudogu/k8s-registry-lib/repository"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
+
+	"github.com/cloudogu/k8s-host-change/pkg/hostsfile"
+	"github.com/cloudogu/k8s-host-change/pkg/rollout"
 )
 
-type hostAliasGenerator interface {
+// Backend publishes the host aliases generated from the global config to the dogus of a namespace. It is implemented
+// by the DefaultHostAliasUpdater for BackendHostAliases and by the DNSUpdater for BackendCoreDNS.
+type Backend interface {
+	// UpdateHostsWithResult publishes the host aliases and reports the outcome. The result is returned even if the
+	// host change failed.
+	UpdateHostsWithResult(ctx context.Context, namespace string) (*Result, error)
+}
+
+// DNSPublisher writes the host aliases into the configuration of the cluster DNS.
+type DNSPublisher interface {
+	// Publish replaces the host aliases of the ecosystem in the given namespace. Empty host aliases remove them.
+	Publish(ctx context.Context, namespace string, hostAliases []corev1.HostAlias) error
+	// ConfigMap returns the namespace and the name of the config map the host aliases are published to.
+	ConfigMap() string
+}
+
+// HostAliasGenerator provides the host aliases all dogu deployments should carry.
+type HostAliasGenerator interface {
 	// Generate patches the given deployment with the host configuration provided.
 	Generate(ctx context.Context) (hostAliases []corev1.HostAlias, err error)
 }
 
-type doguDeploymentFetcher interface {
+// DeploymentFetcher provides the dogu deployments whose host aliases are updated.
+type DeploymentFetcher interface {
 	// FetchAll retrieves all dogu deployments in a given namespace.
 	FetchAll(ctx context.Context, namespace string) ([]appsv1.Deployment, error)
 }
 
-type deploymentUpdater interface {
+// DependencyFetcher provides the dogu dependencies the update waves are ordered by.
+type DependencyFetcher interface {
+	// FetchDependencies retrieves the dogu dependencies of all installed dogus in a given namespace.
+	FetchDependencies(ctx context.Context, namespace string) (map[string][]string, error)
+}
+
+// DeploymentUpdater writes the host aliases into the dogu deployments.
+type DeploymentUpdater interface {
 	// UpdateHostAliases replaces the host aliases in the given deployments.
 	UpdateHostAliases(ctx context.Context, namespace string, deployments []appsv1.Deployment, aliases []corev1.HostAlias) error
 }
+
+// DeploymentRestorer is implemented by deployment updaters which give the dogu deployments their previous host aliases
+// back without recording the replaced host aliases, e.g. deployment.Updater. Other updaters restore the previous host
+// aliases with UpdateHostAliases.
+type DeploymentRestorer interface {
+	// RestoreHostAliases replaces the host aliases in the given deployments with their previous host aliases.
+	RestoreHostAliases(ctx context.Context, namespace string, deployments []appsv1.Deployment, aliases []corev1.HostAlias) error
+}
+
+// RolloutWaiter waits for the rollout of updated dogu deployments.
+type RolloutWaiter interface {
+	// WaitForRollout blocks until all given deployments are rolled out completely or the rollout failed.
+	WaitForRollout(ctx context.Context, namespace string, deployments []appsv1.Deployment) error
+}
+
+// PodVerifier finds running pods which were not replaced after the host change.
+type PodVerifier interface {
+	// FindStalePods returns the running pods of the given deployments which do not carry the given host aliases.
+	FindStalePods(ctx context.Context, namespace string, deployments []appsv1.Deployment, hostAliases []corev1.HostAlias) ([]rollout.StalePod, error)
+}
+
+// HostsFileVerifier checks the hosts file inside the running pods.
+type HostsFileVerifier interface {
+	// VerifyHostsFiles compares the hosts file of a running pod of every given deployment with the given host aliases.
+	VerifyHostsFiles(ctx context.Context, namespace string, deployments []appsv1.Deployment, hostAliases []corev1.HostAlias) ([]hostsfile.Mismatch, error)
+}
+
+// MaintenanceModeSwitch activates the maintenance mode of the ecosystem while the dogus are updated.
+type MaintenanceModeSwitch interface {
+	// Activate activates the maintenance mode with the given description.
+	Activate(ctx context.Context, content repository.MaintenanceModeDescription) error
+	// Deactivate deactivates the maintenance mode.
+	Deactivate(ctx context.Context) error
+}
+
+// AliasRemovalConfirmation confirms that a host change may remove host aliases from the dogus.
+type AliasRemovalConfirmation interface {
+	// IsAliasRemovalConfirmed checks whether the removal of host aliases from the dogus is confirmed.
+	IsAliasRemovalConfirmed(ctx context.Context) (bool, error)
+}
@@ -0,0 +1,49 @@
+package hosts
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/cloudogu/k8s-registry-lib/repository"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/k8s-host-change/pkg/logging"
+)
+
+const (
+	maintenanceModeTitle = "Host change"
+	maintenanceModeText  = "The host configuration of the Cloudogu EcoSystem is being updated. All dogus restart and may be unavailable for a moment."
+	// deactivationTimeout limits the deactivation of the maintenance mode which even runs if the update context is canceled.
+	deactivationTimeout = 30 * time.Second
+)
+
+func (hau *DefaultHostAliasUpdater) activateMaintenanceMode(ctx context.Context) error {
+	ctx = logging.WithPhase(ctx, logging.PhaseMaintenance)
+	log.FromContext(ctx).Info("Activate maintenance mode")
+	err := hau.maintenanceMode.Activate(ctx, repository.MaintenanceModeDescription{
+		Title: maintenanceModeTitle,
+		Text:  maintenanceModeText,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to activate maintenance mode: %w", err)
+	}
+
+	return nil
+}
+
+// deactivateMaintenanceMode deactivates the maintenance mode with a context which is detached from the cancellation of
+// the given context. This way the maintenance mode is also removed if the host change was interrupted.
+func (hau *DefaultHostAliasUpdater) deactivateMaintenanceMode(ctx context.Context) error {
+	ctx = logging.WithPhase(ctx, logging.PhaseMaintenance)
+	log.FromContext(ctx).Info("Deactivate maintenance mode")
+	deactivateCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deactivationTimeout)
+	defer cancel()
+
+	err := hau.maintenanceMode.Deactivate(deactivateCtx)
+	if err != nil {
+		return fmt.Errorf("failed to deactivate maintenance mode: %w", err)
+	}
+
+	return nil
+}
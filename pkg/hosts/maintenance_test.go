@@ -0,0 +1,120 @@
+package hosts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/cloudogu/k8s-registry-lib/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+var maintenanceDescription = repository.MaintenanceModeDescription{
+	Title: maintenanceModeTitle,
+	Text:  maintenanceModeText,
+}
+
+func Test_hostAliasUpdater_UpdateHosts_maintenanceMode(t *testing.T) {
+	t.Run("should fail to activate maintenance mode", func(t *testing.T) {
+		// given
+		generator := succeedingHostAliasGenerator(t)
+		maintenanceMode := NewMockMaintenanceModeSwitch(t)
+		maintenanceMode.EXPECT().Activate(mock.Anything, maintenanceDescription).Return(assert.AnError)
+		sut := &DefaultHostAliasUpdater{generator: generator, maintenanceMode: maintenanceMode}
+
+		// when
+		err := sut.UpdateHosts(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to activate maintenance mode")
+	})
+	t.Run("should activate and deactivate maintenance mode around update", func(t *testing.T) {
+		// given
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := succeedingDoguDeploymentFetcher(t)
+		updater := NewMockDeploymentUpdater(t)
+		maintenanceMode := NewMockMaintenanceModeSwitch(t)
+		activate := maintenanceMode.EXPECT().Activate(mock.Anything, maintenanceDescription).Return(nil).Once()
+		update := updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, doguDeployments, hostAliases).Return(nil).Once().NotBefore(activate)
+		maintenanceMode.EXPECT().Deactivate(mock.Anything).Return(nil).Once().NotBefore(update)
+		sut := &DefaultHostAliasUpdater{
+			generator:       generator,
+			fetcher:         fetcher,
+			updater:         updater,
+			maintenanceMode: maintenanceMode,
+		}
+
+		// when
+		err := sut.UpdateHosts(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should deactivate maintenance mode after failed update", func(t *testing.T) {
+		// given
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := succeedingDoguDeploymentFetcherOnRollback(t)
+		updater := failingDeploymentUpdater(t)
+		maintenanceMode := NewMockMaintenanceModeSwitch(t)
+		maintenanceMode.EXPECT().Activate(mock.Anything, maintenanceDescription).Return(nil).Once()
+		maintenanceMode.EXPECT().Deactivate(mock.Anything).Return(nil).Once()
+		sut := &DefaultHostAliasUpdater{
+			generator:       generator,
+			fetcher:         fetcher,
+			updater:         updater,
+			maintenanceMode: maintenanceMode,
+		}
+
+		// when
+		err := sut.UpdateHosts(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to update host-aliases of dogu deployments in cluster")
+	})
+	t.Run("should return error of failed deactivation", func(t *testing.T) {
+		// given
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := succeedingDoguDeploymentFetcher(t)
+		updater := succeedingDeploymentUpdater(t)
+		maintenanceMode := NewMockMaintenanceModeSwitch(t)
+		maintenanceMode.EXPECT().Activate(mock.Anything, maintenanceDescription).Return(nil).Once()
+		maintenanceMode.EXPECT().Deactivate(mock.Anything).Return(assert.AnError).Once()
+		sut := &DefaultHostAliasUpdater{
+			generator:       generator,
+			fetcher:         fetcher,
+			updater:         updater,
+			maintenanceMode: maintenanceMode,
+		}
+
+		// when
+		err := sut.UpdateHosts(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to deactivate maintenance mode")
+	})
+}
+
+func Test_hostAliasUpdater_deactivateMaintenanceMode(t *testing.T) {
+	t.Run("should deactivate maintenance mode even if context is canceled", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+		maintenanceMode := NewMockMaintenanceModeSwitch(t)
+		maintenanceMode.EXPECT().Deactivate(mock.Anything).RunAndReturn(func(ctx context.Context) error {
+			return ctx.Err()
+		})
+		sut := &DefaultHostAliasUpdater{maintenanceMode: maintenanceMode}
+
+		// when
+		err := sut.deactivateMaintenanceMode(ctx)
+
+		// then
+		require.NoError(t, err)
+	})
+}
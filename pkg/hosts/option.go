@@ -0,0 +1,110 @@
+package hosts
+
+import (
+	"context"
+
+	"github.com/cloudogu/k8s-registry-lib/repository"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/k8s-host-change/pkg/alias"
+)
+
+// UpdaterOption configures a DefaultHostAliasUpdater created by NewUpdater. The DNSUpdater created by NewDNSUpdater
+// ignores WithOptions and WithFailurePolicy.
+type UpdaterOption func(*updaterConfig)
+
+type updaterConfig struct {
+	options   Options
+	generator HostAliasGenerator
+	fetcher   DeploymentFetcher
+	updater   DeploymentUpdater
+	selector  labels.Selector
+	logger    *logr.Logger
+	// failurePolicy is set by WithFailurePolicy and takes precedence over the failure policy of the options.
+	failurePolicy FailurePolicy
+}
+
+// WithOptions configures the optional behaviour like rollout waiting, canary or verification. It replaces the options
+// set by earlier calls of WithOptions. A failure policy of WithFailurePolicy is kept regardless of the order.
+func WithOptions(opts Options) UpdaterOption {
+	return func(cfg *updaterConfig) {
+		cfg.options = opts
+	}
+}
+
+// WithGenerator replaces the default generator which reads the host aliases from the global config of the namespace
+// passed to the updater.
+func WithGenerator(generator HostAliasGenerator) UpdaterOption {
+	return func(cfg *updaterConfig) {
+		cfg.generator = generator
+	}
+}
+
+// WithDeploymentFetcher replaces the default fetcher which lists all deployments with the label 'dogu.name'.
+// WithSelector has no effect if a fetcher is given.
+func WithDeploymentFetcher(fetcher DeploymentFetcher) UpdaterOption {
+	return func(cfg *updaterConfig) {
+		cfg.fetcher = fetcher
+	}
+}
+
+// WithDeploymentUpdater replaces the default updater which writes the host aliases into the deployments with
+// conflict retries.
+func WithDeploymentUpdater(updater DeploymentUpdater) UpdaterOption {
+	return func(cfg *updaterConfig) {
+		cfg.updater = updater
+	}
+}
+
+// WithSelector restricts the host change to the dogu deployments matching the given label selector, e.g. a single
+// dogu which was installed right now.
+func WithSelector(selector labels.Selector) UpdaterOption {
+	return func(cfg *updaterConfig) {
+		cfg.selector = selector
+	}
+}
+
+// WithLogger replaces the logger of the given context in all calls of the updater.
+func WithLogger(logger logr.Logger) UpdaterOption {
+	return func(cfg *updaterConfig) {
+		cfg.logger = &logger
+	}
+}
+
+// WithFailurePolicy defines how the host change reacts on failed deployments. It takes precedence over
+// Options.FailurePolicy of WithOptions.
+func WithFailurePolicy(policy FailurePolicy) UpdaterOption {
+	return func(cfg *updaterConfig) {
+		cfg.failurePolicy = policy
+	}
+}
+
+// hostAliasGenerator returns the configured generator or a generator reading the global config of the given namespace.
+func (hau *DefaultHostAliasUpdater) hostAliasGenerator(namespace string) HostAliasGenerator {
+	return hostAliasGenerator(hau.clientSet, hau.generator, namespace)
+}
+
+func hostAliasGenerator(clientSet kubernetes.Interface, generator HostAliasGenerator, namespace string) HostAliasGenerator {
+	if generator != nil {
+		return generator
+	}
+
+	globalConfigRepo := repository.NewGlobalConfigRepository(clientSet.CoreV1().ConfigMaps(namespace))
+	return alias.NewHostAliasGenerator(globalConfigRepo)
+}
+
+// withLogger puts the configured logger into the given context.
+func (hau *DefaultHostAliasUpdater) withLogger(ctx context.Context) context.Context {
+	return withLogger(ctx, hau.logger)
+}
+
+func withLogger(ctx context.Context, logger *logr.Logger) context.Context {
+	if logger == nil {
+		return ctx
+	}
+
+	return log.IntoContext(ctx, *logger)
+}
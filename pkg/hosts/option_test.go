@@ -0,0 +1,129 @@
+package hosts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewUpdater(t *testing.T) {
+	t.Run("should create updater with defaults", func(t *testing.T) {
+		// when
+		updater := NewUpdater(fake.NewSimpleClientset())
+
+		// then
+		require.NotNil(t, updater)
+		assert.Nil(t, updater.generator)
+		assert.NotNil(t, updater.fetcher)
+		assert.NotNil(t, updater.updater)
+		assert.Nil(t, updater.logger)
+		assert.Empty(t, updater.failurePolicy)
+		assert.Equal(t, defaultShutdownTimeout, updater.shutdownTimeout)
+	})
+	t.Run("should create updater with custom components", func(t *testing.T) {
+		// given
+		generatorMock := NewMockHostAliasGenerator(t)
+		fetcherMock := NewMockDeploymentFetcher(t)
+		updaterMock := NewMockDeploymentUpdater(t)
+
+		// when
+		updater := NewUpdater(fake.NewSimpleClientset(),
+			WithGenerator(generatorMock),
+			WithDeploymentFetcher(fetcherMock),
+			WithDeploymentUpdater(updaterMock),
+			WithOptions(Options{WaitForRollout: true}),
+			WithFailurePolicy(FailurePolicyFailFast),
+		)
+
+		// then
+		assert.Same(t, generatorMock, updater.generator)
+		assert.Same(t, fetcherMock, updater.fetcher)
+		assert.Same(t, updaterMock, updater.updater)
+		assert.NotNil(t, updater.waiter)
+		assert.Equal(t, FailurePolicyFailFast, updater.failurePolicy)
+	})
+	t.Run("should keep failure policy before options", func(t *testing.T) {
+		// when
+		updater := NewUpdater(fake.NewSimpleClientset(), WithFailurePolicy(FailurePolicyFailFast), WithOptions(Options{WaitForRollout: true}))
+
+		// then
+		assert.Equal(t, FailurePolicyFailFast, updater.failurePolicy)
+		assert.NotNil(t, updater.waiter)
+	})
+	t.Run("should keep failure policy after options", func(t *testing.T) {
+		// when
+		updater := NewUpdater(fake.NewSimpleClientset(), WithOptions(Options{WaitForRollout: true}), WithFailurePolicy(FailurePolicyFailFast))
+
+		// then
+		assert.Equal(t, FailurePolicyFailFast, updater.failurePolicy)
+		assert.NotNil(t, updater.waiter)
+	})
+	t.Run("should prefer failure policy over failure policy of options", func(t *testing.T) {
+		// when
+		updater := NewUpdater(fake.NewSimpleClientset(),
+			WithFailurePolicy(FailurePolicyContinueOnError),
+			WithOptions(Options{FailurePolicy: FailurePolicyFailFast}),
+		)
+
+		// then
+		assert.Equal(t, FailurePolicyContinueOnError, updater.failurePolicy)
+	})
+	t.Run("should use failure policy of options without failure policy", func(t *testing.T) {
+		// when
+		updater := NewUpdater(fake.NewSimpleClientset(), WithOptions(Options{FailurePolicy: FailurePolicyFailFast}))
+
+		// then
+		assert.Equal(t, FailurePolicyFailFast, updater.failurePolicy)
+	})
+}
+
+func TestDefaultHostAliasUpdater_defaults(t *testing.T) {
+	t.Run("should generate host aliases from global config and only update selected dogus", func(t *testing.T) {
+		// given
+		globalConfig := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: "global-config", Namespace: testNamespace},
+			Data:       map[string]string{"config.yaml": "containers:\n  additional_hosts:\n    db: 10.0.0.2\n"},
+		}
+		cas, redmine := doguDeployment("cas"), doguDeployment("redmine")
+		clientSet := fake.NewSimpleClientset(globalConfig, &cas, &redmine)
+		updater := NewUpdater(clientSet, WithSelector(labels.SelectorFromSet(labels.Set{"dogu.name": "redmine"})))
+
+		// when
+		err := updater.UpdateHosts(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+		updatedRedmine, err := clientSet.AppsV1().Deployments(testNamespace).Get(context.TODO(), "redmine", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Equal(t, []corev1.HostAlias{{IP: "10.0.0.2", Hostnames: []string{"db"}}}, updatedRedmine.Spec.Template.Spec.HostAliases)
+		updatedCas, err := clientSet.AppsV1().Deployments(testNamespace).Get(context.TODO(), "cas", metav1.GetOptions{})
+		require.NoError(t, err)
+		assert.Empty(t, updatedCas.Spec.Template.Spec.HostAliases)
+	})
+	t.Run("should log with configured logger", func(t *testing.T) {
+		// given
+		var messages []string
+		logger := funcr.New(func(_, args string) {
+			messages = append(messages, args)
+		}, funcr.Options{})
+		generatorMock := NewMockHostAliasGenerator(t)
+		generatorMock.EXPECT().Generate(mock.Anything).Return(nil, assert.AnError)
+		updater := NewUpdater(fake.NewSimpleClientset(), WithGenerator(generatorMock), WithLogger(logger))
+
+		// when
+		err := updater.UpdateHosts(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		require.NotEmpty(t, messages)
+		assert.Contains(t, messages[0], "Update host entries in dogu deployments")
+	})
+}
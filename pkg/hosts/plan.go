@@ -0,0 +1,106 @@
+package hosts
+
+import (
+	"context"
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/k8s-host-change/pkg/deployment"
+	"github.com/cloudogu/k8s-host-change/pkg/logging"
+)
+
+// Plan describes which dogu deployments a host change would update.
+type Plan struct {
+	// HostAliases are the host aliases generated from the global config.
+	HostAliases []corev1.HostAlias  `json:"hostAliases"`
+	Deployments []PlannedDeployment `json:"deployments"`
+}
+
+// PlannedDeployment describes the planned change of a single dogu deployment.
+type PlannedDeployment struct {
+	Name               string             `json:"name"`
+	CurrentHostAliases []corev1.HostAlias `json:"currentHostAliases"`
+	// Changed is true if the current host aliases differ from the generated host aliases.
+	Changed bool `json:"changed"`
+}
+
+// ChangedDeployments returns the names of all deployments whose host aliases differ from the generated host aliases.
+func (p *Plan) ChangedDeployments() []string {
+	var names []string
+	for _, deploy := range p.Deployments {
+		if deploy.Changed {
+			names = append(names, deploy.Name)
+		}
+	}
+
+	return names
+}
+
+// Plan generates the host aliases and compares them with the host aliases of all dogu deployments without changing
+// anything. The order of the host aliases is not considered a change.
+func (hau *DefaultHostAliasUpdater) Plan(ctx context.Context, namespace string) (*Plan, error) {
+	ctx = hau.withLogger(ctx)
+	hostAliases, err := hau.hostAliasGenerator(namespace).Generate(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate host aliases: %w", err)
+	}
+
+	deployments, err := hau.fetcher.FetchAll(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dogu deployments: %w", err)
+	}
+
+	plan := &Plan{HostAliases: hostAliases}
+	for _, deploy := range deployments {
+		current := deploy.Spec.Template.Spec.HostAliases
+		plan.Deployments = append(plan.Deployments, PlannedDeployment{
+			Name:               deploy.Name,
+			CurrentHostAliases: current,
+			Changed:            !deployment.SameHostAliases(current, hostAliases),
+		})
+	}
+
+	return plan, nil
+}
+
+// RollbackHosts restores the host aliases which the dogu deployments carried before the last host change.
+// Deployments without a recorded host change are left untouched. It returns the names of the restored deployments.
+func (hau *DefaultHostAliasUpdater) RollbackHosts(ctx context.Context, namespace string) ([]string, error) {
+	ctx = hau.withLogger(ctx)
+	logger := log.FromContext(ctx)
+	deployments, err := hau.fetcher.FetchAll(ctx, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch dogu deployments: %w", err)
+	}
+
+	var restored []appsv1.Deployment
+	for _, deploy := range deployments {
+		previous, found, err := deployment.PreviousHostAliases(&deploy)
+		if err != nil {
+			return deploymentNames(restored), err
+		}
+		if !found {
+			logger.Info("No previous host aliases recorded: skipping", logging.DeploymentKey, deploy.Name)
+			continue
+		}
+
+		logger.Info("Restore host aliases", logging.DeploymentKey, deploy.Name, "hostAliases", previous)
+		err = hau.restoreHostAliases(ctx, namespace, []appsv1.Deployment{deploy}, previous)
+		if err != nil {
+			return deploymentNames(restored), fmt.Errorf("failed to restore host aliases of deployment '%s': %w", deploy.Name, err)
+		}
+		restored = append(restored, deploy)
+	}
+
+	if hau.waiter != nil && len(restored) > 0 {
+		err = hau.waiter.WaitForRollout(ctx, namespace, restored)
+		if err != nil {
+			return deploymentNames(restored), fmt.Errorf("failed to roll out restored host aliases: %w", err)
+		}
+	}
+
+	return deploymentNames(restored), nil
+}
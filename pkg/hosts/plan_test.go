@@ -0,0 +1,136 @@
+package hosts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/cloudogu/k8s-host-change/pkg/deployment"
+)
+
+func Test_hostAliasUpdater_Plan(t *testing.T) {
+	t.Run("should plan changed deployments", func(t *testing.T) {
+		// given
+		cas := doguDeployment("cas")
+		cas.Spec.Template.Spec.HostAliases = hostAliases
+		ldap := doguDeployment("ldap")
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(context.TODO(), testNamespace).Return([]appsv1.Deployment{cas, ldap}, nil)
+		sut := &DefaultHostAliasUpdater{generator: generator, fetcher: fetcher}
+
+		// when
+		actual, err := sut.Plan(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, hostAliases, actual.HostAliases)
+		assert.Equal(t, []PlannedDeployment{
+			{Name: "cas", CurrentHostAliases: hostAliases},
+			{Name: "ldap", Changed: true},
+		}, actual.Deployments)
+		assert.Equal(t, []string{"ldap"}, actual.ChangedDeployments())
+	})
+	t.Run("should fail to generate host aliases", func(t *testing.T) {
+		// given
+		sut := &DefaultHostAliasUpdater{generator: failingHostAliasGenerator(t)}
+
+		// when
+		_, err := sut.Plan(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to generate host aliases")
+	})
+	t.Run("should fail to fetch dogu deployments", func(t *testing.T) {
+		// given
+		sut := &DefaultHostAliasUpdater{generator: succeedingHostAliasGenerator(t), fetcher: failingDoguDeploymentFetcher(t)}
+
+		// when
+		_, err := sut.Plan(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to fetch dogu deployments")
+	})
+}
+
+func Test_hostAliasUpdater_RollbackHosts(t *testing.T) {
+	previous := []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}}}
+	cas := doguDeployment("cas")
+	cas.Annotations = map[string]string{deployment.PreviousHostAliasesAnnotation: `[{"ip":"10.0.0.1","hostnames":["ces.example.com"]}]`}
+	ldap := doguDeployment("ldap")
+
+	t.Run("should restore recorded host aliases", func(t *testing.T) {
+		// given
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(context.TODO(), testNamespace).Return([]appsv1.Deployment{cas, ldap}, nil)
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(context.TODO(), testNamespace, []appsv1.Deployment{cas}, previous).Return(nil)
+		waiter := NewMockRolloutWaiter(t)
+		waiter.EXPECT().WaitForRollout(context.TODO(), testNamespace, []appsv1.Deployment{cas}).Return(nil)
+		sut := &DefaultHostAliasUpdater{fetcher: fetcher, updater: updater, waiter: waiter}
+
+		// when
+		actual, err := sut.RollbackHosts(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cas"}, actual)
+	})
+	t.Run("should restore recorded host aliases with restorer", func(t *testing.T) {
+		// given
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(context.TODO(), testNamespace).Return([]appsv1.Deployment{cas, ldap}, nil)
+		restorer := NewMockDeploymentRestorer(t)
+		restorer.EXPECT().RestoreHostAliases(context.TODO(), testNamespace, []appsv1.Deployment{cas}, previous).Return(nil)
+		updater := struct {
+			*MockDeploymentUpdater
+			*MockDeploymentRestorer
+		}{NewMockDeploymentUpdater(t), restorer}
+		sut := &DefaultHostAliasUpdater{fetcher: fetcher, updater: updater}
+
+		// when
+		actual, err := sut.RollbackHosts(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []string{"cas"}, actual)
+	})
+	t.Run("should fail to restore host aliases", func(t *testing.T) {
+		// given
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(context.TODO(), testNamespace).Return([]appsv1.Deployment{cas}, nil)
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(context.TODO(), testNamespace, mock.Anything, previous).Return(assert.AnError)
+		sut := &DefaultHostAliasUpdater{fetcher: fetcher, updater: updater}
+
+		// when
+		actual, err := sut.RollbackHosts(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to restore host aliases of deployment 'cas'")
+		assert.Empty(t, actual)
+	})
+	t.Run("should fail to fetch dogu deployments", func(t *testing.T) {
+		// given
+		sut := &DefaultHostAliasUpdater{fetcher: failingDoguDeploymentFetcher(t)}
+
+		// when
+		_, err := sut.RollbackHosts(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
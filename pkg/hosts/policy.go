@@ -0,0 +1,204 @@
+package hosts
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/k8s-host-change/pkg/deployment"
+	"github.com/cloudogu/k8s-host-change/pkg/logging"
+	"github.com/cloudogu/k8s-host-change/pkg/rollout"
+)
+
+// FailurePolicy defines how the host change reacts on dogu deployments which fail to update or to roll out.
+type FailurePolicy string
+
+const (
+	// FailurePolicyRollbackAll rolls back the host aliases of all dogu deployments if a single deployment fails.
+	FailurePolicyRollbackAll FailurePolicy = "rollback-all"
+	// FailurePolicyFailFast stops at the first failed deployment. Already updated deployments keep the new host aliases.
+	FailurePolicyFailFast FailurePolicy = "fail-fast"
+	// FailurePolicyContinueOnError updates all deployments possible and reports the failed deployments afterwards.
+	FailurePolicyContinueOnError FailurePolicy = "continue-on-error"
+)
+
+// ParseFailurePolicy returns the failure policy with the given name. An empty name results in FailurePolicyRollbackAll.
+func ParseFailurePolicy(name string) (FailurePolicy, error) {
+	switch policy := FailurePolicy(name); policy {
+	case "":
+		return FailurePolicyRollbackAll, nil
+	case FailurePolicyRollbackAll, FailurePolicyFailFast, FailurePolicyContinueOnError:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown failure policy '%s': expected one of '%s', '%s' or '%s'",
+			name, FailurePolicyRollbackAll, FailurePolicyFailFast, FailurePolicyContinueOnError)
+	}
+}
+
+// DeploymentFailure describes a dogu deployment which failed to update or to roll out.
+type DeploymentFailure struct {
+	Deployment string
+	Err        error
+}
+
+// PartialFailureError is returned if some dogu deployments failed and were not rolled back because of the
+// failure policy. It reports the state of all deployments of the host change.
+type PartialFailureError struct {
+	Policy FailurePolicy
+	// Updated contains the names of the deployments which carry the new host aliases.
+	Updated []string
+	// Failed contains the deployments which failed to update or to roll out.
+	Failed []DeploymentFailure
+	// Skipped contains the names of the deployments which were not updated because the host change stopped early.
+	Skipped []string
+}
+
+// Error lists all failed deployments.
+func (e *PartialFailureError) Error() string {
+	failures := make([]string, 0, len(e.Failed))
+	for _, failure := range e.Failed {
+		failures = append(failures, fmt.Sprintf("'%s': %s", failure.Deployment, failure.Err))
+	}
+
+	return fmt.Sprintf("host change with failure policy '%s' failed for %d dogu deployments: %s",
+		e.Policy, len(e.Failed), strings.Join(failures, "; "))
+}
+
+// Unwrap returns the errors of all failed deployments.
+func (e *PartialFailureError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Failed))
+	for _, failure := range e.Failed {
+		errs = append(errs, failure.Err)
+	}
+
+	return errs
+}
+
+// keepsFailedDeployments returns true if failed deployments should not trigger a rollback of all deployments.
+func (hau *DefaultHostAliasUpdater) keepsFailedDeployments() bool {
+	return hau.failurePolicy == FailurePolicyFailFast || hau.failurePolicy == FailurePolicyContinueOnError
+}
+
+// updateWaveIndividually updates the given deployments one by one and records the outcome of every deployment in the
+// given report and result instead of rolling back. It returns true if the host change should stop because of the
+// failure policy.
+func (hau *DefaultHostAliasUpdater) updateWaveIndividually(ctx context.Context, namespace string, deployments []appsv1.Deployment, hostAliases []corev1.HostAlias, report *PartialFailureError, result *Result) bool {
+	logger := log.FromContext(logging.WithPhase(ctx, logging.PhaseUpdate))
+	logger.Info("Update deployments with host aliases", "deployments", deploymentNames(deployments))
+
+	stop := false
+	var updated []appsv1.Deployment
+	durations := make(map[string]time.Duration)
+	for i, deploy := range deployments {
+		start := time.Now()
+		err := hau.updater.UpdateHostAliases(ctx, namespace, []appsv1.Deployment{deploy}, hostAliases)
+		durations[deploy.Name] = time.Since(start)
+		if err != nil {
+			logger.Error(err, "Failed to update deployment", logging.DeploymentKey, deploy.Name)
+			report.Failed = append(report.Failed, DeploymentFailure{Deployment: deploy.Name, Err: err})
+			result.record(deploy.Name, ActionFailed, err, durations[deploy.Name])
+
+			if hau.failurePolicy == FailurePolicyFailFast {
+				report.Skipped = append(report.Skipped, deploymentNames(deployments[i+1:])...)
+				stop = true
+				break
+			}
+			continue
+		}
+
+		updated = append(updated, deploy)
+	}
+
+	var rolloutErrs map[string]error
+	var rolloutDuration time.Duration
+	if hau.waiter != nil && len(updated) > 0 {
+		rolloutCtx := logging.WithPhase(ctx, logging.PhaseRollout)
+		log.FromContext(rolloutCtx).Info("Wait for rollout of updated deployments", "deployments", deploymentNames(updated))
+		start := time.Now()
+		err := hau.waiter.WaitForRollout(rolloutCtx, namespace, updated)
+		rolloutDuration = time.Since(start)
+		if err != nil {
+			log.FromContext(rolloutCtx).Error(err, "Failed to roll out dogu deployments")
+			rolloutErrs = rolloutErrorsByDeployment(err, updated)
+		}
+	}
+
+	for _, deploy := range updated {
+		duration := durations[deploy.Name] + rolloutDuration
+		if err, failed := rolloutErrs[deploy.Name]; failed {
+			report.Failed = append(report.Failed, DeploymentFailure{Deployment: deploy.Name, Err: err})
+			result.record(deploy.Name, ActionFailed, err, duration)
+			continue
+		}
+		report.Updated = append(report.Updated, deploy.Name)
+		result.record(deploy.Name, ActionUpdated, nil, duration)
+	}
+
+	return stop || (hau.failurePolicy == FailurePolicyFailFast && len(rolloutErrs) > 0)
+}
+
+// rolloutErrorsByDeployment assigns the errors of the rollout to the failed deployments. Errors which cannot be
+// assigned to a single deployment are assigned to all given deployments.
+func rolloutErrorsByDeployment(err error, deployments []appsv1.Deployment) map[string]error {
+	errs := []error{err}
+	var multiErr *multierror.Error
+	if errors.As(err, &multiErr) {
+		errs = multiErr.WrappedErrors()
+	}
+
+	result := make(map[string]error)
+	for _, e := range errs {
+		var deploymentErr *rollout.DeploymentError
+		if errors.As(e, &deploymentErr) {
+			result[deploymentErr.Deployment] = fmt.Errorf("failed to roll out host-aliases: %w", deploymentErr.Err)
+			continue
+		}
+
+		for _, deploy := range deployments {
+			result[deploy.Name] = fmt.Errorf("failed to roll out host-aliases: %w", e)
+		}
+	}
+
+	return result
+}
+
+// updateErrorsByDeployment assigns the errors of a failed update to the deployments which were not updated. Errors
+// which cannot be assigned to a single deployment are assigned to all deployments.
+func updateErrorsByDeployment(err error, deployments []appsv1.Deployment) map[string]*deployment.UpdateError {
+	errs := []error{err}
+	var multiErr *multierror.Error
+	if errors.As(err, &multiErr) {
+		errs = multiErr.WrappedErrors()
+	}
+
+	result := make(map[string]*deployment.UpdateError)
+	for _, e := range errs {
+		var updateErr *deployment.UpdateError
+		if errors.As(e, &updateErr) {
+			result[updateErr.Deployment] = updateErr
+			continue
+		}
+
+		for _, deploy := range deployments {
+			result[deploy.Name] = &deployment.UpdateError{Deployment: deploy.Name, Err: e}
+		}
+	}
+
+	return result
+}
+
+func logReport(ctx context.Context, report *PartialFailureError) {
+	logger := log.FromContext(logging.WithPhase(ctx, logging.PhaseReport))
+	logger.Info("Host change report", "failurePolicy", report.Policy, "updated", report.Updated,
+		"failed", len(report.Failed), "skipped", report.Skipped)
+	for _, failure := range report.Failed {
+		logger.Error(failure.Err, "Deployment failed", logging.DeploymentKey, failure.Deployment)
+	}
+}
@@ -0,0 +1,234 @@
+package hosts
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/cloudogu/k8s-host-change/pkg/deployment"
+	"github.com/cloudogu/k8s-host-change/pkg/rollout"
+)
+
+func TestParseFailurePolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		want    FailurePolicy
+		wantErr func(t *testing.T, err error)
+	}{
+		{name: "should default to rollback-all", value: "", want: FailurePolicyRollbackAll, wantErr: noError},
+		{name: "should parse rollback-all", value: "rollback-all", want: FailurePolicyRollbackAll, wantErr: noError},
+		{name: "should parse fail-fast", value: "fail-fast", want: FailurePolicyFailFast, wantErr: noError},
+		{name: "should parse continue-on-error", value: "continue-on-error", want: FailurePolicyContinueOnError, wantErr: noError},
+		{
+			name:  "should fail on unknown policy",
+			value: "ignore",
+			wantErr: func(t *testing.T, err error) {
+				require.Error(t, err)
+				assert.ErrorContains(t, err, "unknown failure policy 'ignore'")
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual, err := ParseFailurePolicy(tt.value)
+
+			tt.wantErr(t, err)
+			assert.Equal(t, tt.want, actual)
+		})
+	}
+}
+
+func noError(t *testing.T, err error) {
+	require.NoError(t, err)
+}
+
+func Test_hostAliasUpdater_UpdateHosts_failurePolicy(t *testing.T) {
+	cas := doguDeployment("cas")
+	ldap := doguDeployment("ldap")
+	nginx := doguDeployment("nginx")
+	deployments := []appsv1.Deployment{cas, ldap, nginx}
+
+	t.Run("should stop at first failed deployment with fail-fast", func(t *testing.T) {
+		// given
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(deployments, nil)
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{cas}, hostAliases).Return(nil)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{ldap}, hostAliases).Return(assert.AnError)
+		sut := &DefaultHostAliasUpdater{generator: generator, fetcher: fetcher, updater: updater, failurePolicy: FailurePolicyFailFast}
+
+		// when
+		err := sut.UpdateHosts(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		var report *PartialFailureError
+		require.ErrorAs(t, err, &report)
+		assert.Equal(t, []string{"cas"}, report.Updated)
+		assert.Equal(t, []DeploymentFailure{{Deployment: "ldap", Err: assert.AnError}}, report.Failed)
+		assert.Equal(t, []string{"nginx"}, report.Skipped)
+		assert.ErrorContains(t, err, "host change with failure policy 'fail-fast' failed for 1 dogu deployments: 'ldap':")
+	})
+	t.Run("should roll back if interrupted during the last wave with continue-on-error", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.TODO())
+		defer cancel()
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(deployments, nil)
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{cas}, hostAliases).Return(nil)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{ldap}, hostAliases).
+			RunAndReturn(func(context.Context, string, []appsv1.Deployment, []corev1.HostAlias) error {
+				cancel()
+				return context.Canceled
+			})
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{nginx}, hostAliases).Return(context.Canceled)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, mock.Anything, []corev1.HostAlias(nil)).Return(nil)
+		sut := &DefaultHostAliasUpdater{generator: generator, fetcher: fetcher, updater: updater,
+			failurePolicy: FailurePolicyContinueOnError, shutdownTimeout: time.Second}
+
+		// when
+		result, err := sut.UpdateHostsWithResult(ctx, testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorContains(t, err, "host change interrupted during wave 1 of 1")
+		var report *PartialFailureError
+		assert.False(t, errors.As(err, &report))
+		assert.Equal(t, ActionRolledBack, result.action("cas"))
+		assert.Equal(t, ActionFailed, result.action("ldap"))
+	})
+	t.Run("should skip remaining waves with fail-fast after failed rollout", func(t *testing.T) {
+		// given
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(deployments, nil)
+		dependencyFetcher := NewMockDependencyFetcher(t)
+		dependencyFetcher.EXPECT().FetchDependencies(mock.Anything, testNamespace).Return(map[string][]string{"nginx": {"cas"}}, nil)
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, mock.Anything, hostAliases).Return(nil).Twice()
+		waiter := NewMockRolloutWaiter(t)
+		waiter.EXPECT().WaitForRollout(mock.Anything, testNamespace, []appsv1.Deployment{cas, ldap}).
+			Return(multierror.Append(nil, &rollout.DeploymentError{Deployment: "ldap", Err: assert.AnError}))
+		sut := &DefaultHostAliasUpdater{generator: generator, fetcher: fetcher, updater: updater, waiter: waiter,
+			dependencyFetcher: dependencyFetcher, failurePolicy: FailurePolicyFailFast}
+
+		// when
+		err := sut.UpdateHosts(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		var report *PartialFailureError
+		require.ErrorAs(t, err, &report)
+		assert.Equal(t, []string{"cas"}, report.Updated)
+		require.Len(t, report.Failed, 1)
+		assert.Equal(t, "ldap", report.Failed[0].Deployment)
+		assert.ErrorIs(t, report.Failed[0].Err, assert.AnError)
+		assert.Equal(t, []string{"nginx"}, report.Skipped)
+	})
+	t.Run("should update all possible deployments with continue-on-error", func(t *testing.T) {
+		// given
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(deployments, nil)
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{cas}, hostAliases).Return(assert.AnError)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{ldap}, hostAliases).Return(nil)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{nginx}, hostAliases).Return(nil)
+		waiter := NewMockRolloutWaiter(t)
+		waiter.EXPECT().WaitForRollout(mock.Anything, testNamespace, []appsv1.Deployment{ldap, nginx}).Return(nil)
+		sut := &DefaultHostAliasUpdater{generator: generator, fetcher: fetcher, updater: updater, waiter: waiter,
+			failurePolicy: FailurePolicyContinueOnError}
+
+		// when
+		err := sut.UpdateHosts(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		var report *PartialFailureError
+		require.ErrorAs(t, err, &report)
+		assert.Equal(t, FailurePolicyContinueOnError, report.Policy)
+		assert.Equal(t, []string{"ldap", "nginx"}, report.Updated)
+		assert.Equal(t, []DeploymentFailure{{Deployment: "cas", Err: assert.AnError}}, report.Failed)
+		assert.Empty(t, report.Skipped)
+	})
+	t.Run("should succeed with continue-on-error if nothing failed", func(t *testing.T) {
+		// given
+		generator := succeedingHostAliasGenerator(t)
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(deployments, nil)
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, mock.Anything, hostAliases).Return(nil).Times(3)
+		sut := &DefaultHostAliasUpdater{generator: generator, fetcher: fetcher, updater: updater, failurePolicy: FailurePolicyContinueOnError}
+
+		// when
+		err := sut.UpdateHosts(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+	})
+}
+
+func Test_rolloutErrorsByDeployment(t *testing.T) {
+	deployments := []appsv1.Deployment{doguDeployment("cas"), doguDeployment("ldap")}
+
+	t.Run("should assign errors to failed deployments", func(t *testing.T) {
+		// given
+		err := multierror.Append(nil, &rollout.DeploymentError{Deployment: "ldap", Err: assert.AnError})
+
+		// when
+		actual := rolloutErrorsByDeployment(err, deployments)
+
+		// then
+		require.Len(t, actual, 1)
+		assert.ErrorIs(t, actual["ldap"], assert.AnError)
+	})
+	t.Run("should assign unknown errors to all deployments", func(t *testing.T) {
+		// when
+		actual := rolloutErrorsByDeployment(assert.AnError, deployments)
+
+		// then
+		require.Len(t, actual, 2)
+		assert.ErrorIs(t, actual["cas"], assert.AnError)
+		assert.ErrorIs(t, actual["ldap"], assert.AnError)
+	})
+}
+
+func Test_updateErrorsByDeployment(t *testing.T) {
+	deployments := []appsv1.Deployment{doguDeployment("cas"), doguDeployment("ldap")}
+
+	t.Run("should assign errors to deployments which were not updated", func(t *testing.T) {
+		// given
+		err := multierror.Append(nil, &deployment.UpdateError{Deployment: "ldap", Err: assert.AnError})
+
+		// when
+		actual := updateErrorsByDeployment(err, deployments)
+
+		// then
+		require.Len(t, actual, 1)
+		assert.ErrorIs(t, actual["ldap"], assert.AnError)
+	})
+	t.Run("should assign unknown errors to all deployments", func(t *testing.T) {
+		// when
+		actual := updateErrorsByDeployment(assert.AnError, deployments)
+
+		// then
+		require.Len(t, actual, 2)
+		assert.ErrorIs(t, actual["cas"], assert.AnError)
+		assert.ErrorIs(t, actual["ldap"], assert.AnError)
+	})
+}
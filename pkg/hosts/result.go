@@ -0,0 +1,213 @@
+package hosts
+
+import (
+	"errors"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+// Action describes what a host change did with a dogu deployment.
+type Action string
+
+const (
+	// ActionUpdated means that the deployment carries the new host aliases.
+	ActionUpdated Action = "updated"
+	// ActionSkipped means that the deployment was not touched.
+	ActionSkipped Action = "skipped"
+	// ActionFailed means that the update or the rollout of the deployment failed.
+	ActionFailed Action = "failed"
+	// ActionRolledBack means that the deployment was updated and rolled back afterwards because another one failed.
+	ActionRolledBack Action = "rolled-back"
+)
+
+// Status describes the outcome of a host change.
+type Status string
+
+const (
+	// StatusSucceeded means that all dogu deployments carry the new host aliases.
+	StatusSucceeded Status = "succeeded"
+	// StatusPartiallyFailed means that some dogu deployments failed and were kept because of the failure policy.
+	StatusPartiallyFailed Status = "partially-failed"
+	// StatusFailed means that the host change failed.
+	StatusFailed Status = "failed"
+)
+
+// Result is the machine-readable report of a host change.
+type Result struct {
+	// RunID identifies the host change. The log entries of the host change carry it as field.
+	RunID     string `json:"runID"`
+	Namespace string `json:"namespace"`
+	// Backend publishes the host aliases to the dogus.
+	Backend       BackendType   `json:"backend"`
+	Status        Status        `json:"status"`
+	FailurePolicy FailurePolicy `json:"failurePolicy"`
+	// Error is the error message of a failed host change.
+	Error     string          `json:"error,omitempty"`
+	StartTime metav1.Time     `json:"startTime"`
+	Duration  metav1.Duration `json:"duration"`
+	// GlobalConfig contains the values of the global config the host aliases were generated from.
+	GlobalConfig map[string]string `json:"globalConfig,omitempty"`
+	// HostAliases are the host aliases generated from the global config.
+	HostAliases []corev1.HostAlias `json:"hostAliases"`
+	// DNSConfigMap is the config map the BackendCoreDNS published the host aliases to. This backend only changes dogu
+	// deployments which still carry host aliases.
+	DNSConfigMap string             `json:"dnsConfigMap,omitempty"`
+	Deployments  []DeploymentResult `json:"deployments"`
+}
+
+// DeploymentResult reports the outcome of a host change for a single dogu deployment.
+type DeploymentResult struct {
+	Name string `json:"name"`
+	// PreviousHostAliases are the host aliases the deployment carried before the host change.
+	PreviousHostAliases []corev1.HostAlias `json:"previousHostAliases"`
+	// NewHostAliases are the host aliases the host change tried to set.
+	NewHostAliases []corev1.HostAlias `json:"newHostAliases"`
+	Action         Action             `json:"action"`
+	Error          string             `json:"error,omitempty"`
+	// Duration is the time spent on updating and rolling out the deployment.
+	Duration metav1.Duration `json:"duration"`
+	// StalePods are the names of running pods which did not carry the new host aliases when the pods were verified.
+	StalePods []string `json:"stalePods,omitempty"`
+	// HostsFileMismatch describes why the hosts file of a pod of the deployment did not match the new host aliases.
+	HostsFileMismatch string `json:"hostsFileMismatch,omitempty"`
+}
+
+func newResult(namespace string, backend BackendType, policy FailurePolicy) *Result {
+	if policy == "" {
+		policy = FailurePolicyRollbackAll
+	}
+
+	return &Result{
+		RunID:         string(uuid.NewUUID()),
+		Namespace:     namespace,
+		Backend:       backend,
+		FailurePolicy: policy,
+		StartTime:     metav1.Now(),
+		Deployments:   []DeploymentResult{},
+	}
+}
+
+// addDeployments adds the given deployments as skipped.
+func (r *Result) addDeployments(deployments []appsv1.Deployment, hostAliases []corev1.HostAlias) {
+	for _, deploy := range deployments {
+		r.Deployments = append(r.Deployments, DeploymentResult{
+			Name:                deploy.Name,
+			PreviousHostAliases: deploy.Spec.Template.Spec.HostAliases,
+			NewHostAliases:      hostAliases,
+			Action:              ActionSkipped,
+		})
+	}
+}
+
+// record sets the action of the deployment with the given name. The error may be nil.
+func (r *Result) record(name string, action Action, err error, duration time.Duration) {
+	for i := range r.Deployments {
+		if r.Deployments[i].Name != name {
+			continue
+		}
+
+		r.Deployments[i].Action = action
+		r.Deployments[i].Duration = metav1.Duration{Duration: duration}
+		if err != nil {
+			r.Deployments[i].Error = err.Error()
+		}
+	}
+}
+
+// fail marks the deployment with the given name as failed and keeps its duration.
+func (r *Result) fail(name string, err error) {
+	for i := range r.Deployments {
+		if r.Deployments[i].Name == name {
+			r.Deployments[i].Action = ActionFailed
+			r.Deployments[i].Error = err.Error()
+		}
+	}
+}
+
+// recordStalePod adds the given pod to the stale pods of the deployment with the given name.
+func (r *Result) recordStalePod(name string, pod string) {
+	for i := range r.Deployments {
+		if r.Deployments[i].Name == name {
+			r.Deployments[i].StalePods = append(r.Deployments[i].StalePods, pod)
+		}
+	}
+}
+
+// recordHostsFileMismatch sets the hosts file mismatch of the deployment with the given name.
+func (r *Result) recordHostsFileMismatch(name string, mismatch string) {
+	for i := range r.Deployments {
+		if r.Deployments[i].Name == name {
+			r.Deployments[i].HostsFileMismatch = mismatch
+		}
+	}
+}
+
+// action returns the action of the deployment with the given name.
+func (r *Result) action(name string) Action {
+	for _, deploy := range r.Deployments {
+		if deploy.Name == name {
+			return deploy.Action
+		}
+	}
+
+	return ""
+}
+
+// recordAll sets the action of all given deployments.
+func (r *Result) recordAll(deployments []appsv1.Deployment, action Action, err error, duration time.Duration) {
+	for _, deploy := range deployments {
+		r.record(deploy.Name, action, err, duration)
+	}
+}
+
+// recordUpdate sets the actions of the given deployments after an update with the given error: deployments without an
+// error of their own are updated, deployments skipped because of the context are skipped and all others are failed.
+func (r *Result) recordUpdate(deployments []appsv1.Deployment, err error, duration time.Duration) {
+	if err == nil {
+		r.recordAll(deployments, ActionUpdated, nil, duration)
+		return
+	}
+
+	updateErrs := updateErrorsByDeployment(err, deployments)
+	for _, deploy := range deployments {
+		updateErr, failed := updateErrs[deploy.Name]
+		switch {
+		case !failed:
+			r.record(deploy.Name, ActionUpdated, nil, duration)
+		case updateErr.Skipped:
+			r.record(deploy.Name, ActionSkipped, updateErr, duration)
+		default:
+			r.record(deploy.Name, ActionFailed, updateErr, duration)
+		}
+	}
+}
+
+// rolledBack marks all updated deployments as rolled back.
+func (r *Result) rolledBack() {
+	for i := range r.Deployments {
+		if r.Deployments[i].Action == ActionUpdated {
+			r.Deployments[i].Action = ActionRolledBack
+		}
+	}
+}
+
+// finish sets the status and the duration of the host change.
+func (r *Result) finish(err error) {
+	r.Duration = metav1.Duration{Duration: time.Since(r.StartTime.Time)}
+
+	var partialFailure *PartialFailureError
+	switch {
+	case err == nil:
+		r.Status = StatusSucceeded
+	case errors.As(err, &partialFailure):
+		r.Status = StatusPartiallyFailed
+		r.Error = err.Error()
+	default:
+		r.Status = StatusFailed
+		r.Error = err.Error()
+	}
+}
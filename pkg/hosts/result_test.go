@@ -0,0 +1,135 @@
+package hosts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/cloudogu/k8s-host-change/pkg/deployment"
+	"github.com/cloudogu/k8s-host-change/pkg/rollout"
+)
+
+func Test_hostAliasUpdater_UpdateHostsWithResult(t *testing.T) {
+	previousHostAliases := []corev1.HostAlias{{IP: "4.3.2.1", Hostnames: []string{"old.example.com"}}}
+	cas := doguDeployment("cas")
+	cas.Spec.Template.Spec.HostAliases = previousHostAliases
+	ldap := doguDeployment("ldap")
+	deployments := []appsv1.Deployment{cas, ldap}
+
+	t.Run("should report failed generation", func(t *testing.T) {
+		// given
+		sut := &DefaultHostAliasUpdater{generator: failingHostAliasGenerator(t)}
+
+		// when
+		result, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, testNamespace, result.Namespace)
+		assert.Equal(t, StatusFailed, result.Status)
+		assert.Equal(t, FailurePolicyRollbackAll, result.FailurePolicy)
+		assert.Contains(t, result.Error, "failed to generate host aliases")
+		assert.Empty(t, result.Deployments)
+	})
+	t.Run("should report updated deployments", func(t *testing.T) {
+		// given
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(deployments, nil)
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, deployments, hostAliases).Return(nil)
+		sut := &DefaultHostAliasUpdater{generator: succeedingHostAliasGenerator(t), fetcher: fetcher, updater: updater,
+			confirmAliasRemoval: true}
+
+		// when
+		result, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, StatusSucceeded, result.Status)
+		assert.NotEmpty(t, result.RunID)
+		assert.Empty(t, result.Error)
+		assert.Equal(t, hostAliases, result.HostAliases)
+		require.Len(t, result.Deployments, 2)
+		assert.Equal(t, "cas", result.Deployments[0].Name)
+		assert.Equal(t, previousHostAliases, result.Deployments[0].PreviousHostAliases)
+		assert.Equal(t, hostAliases, result.Deployments[0].NewHostAliases)
+		assert.Equal(t, ActionUpdated, result.Deployments[0].Action)
+		assert.Equal(t, ActionUpdated, result.Deployments[1].Action)
+	})
+	t.Run("should report failed and rolled back deployments", func(t *testing.T) {
+		// given
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(deployments, nil)
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, deployments, hostAliases).Return(nil)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{cas}, previousHostAliases).Return(nil)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, []appsv1.Deployment{ldap}, []corev1.HostAlias(nil)).Return(nil)
+		waiter := NewMockRolloutWaiter(t)
+		waiter.EXPECT().WaitForRollout(mock.Anything, testNamespace, deployments).
+			Return(multierror.Append(nil, &rollout.DeploymentError{Deployment: "ldap", Err: assert.AnError}))
+		sut := &DefaultHostAliasUpdater{generator: succeedingHostAliasGenerator(t), fetcher: fetcher, updater: updater, waiter: waiter,
+			confirmAliasRemoval: true}
+
+		// when
+		result, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, StatusFailed, result.Status)
+		require.Len(t, result.Deployments, 2)
+		assert.Equal(t, ActionRolledBack, result.Deployments[0].Action)
+		assert.Empty(t, result.Deployments[0].Error)
+		assert.Equal(t, ActionFailed, result.Deployments[1].Action)
+		assert.Contains(t, result.Deployments[1].Error, assert.AnError.Error())
+	})
+	t.Run("should only report deployments as failed whose update failed", func(t *testing.T) {
+		// given
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(deployments, nil)
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, deployments, hostAliases).
+			Return(multierror.Append(nil, &deployment.UpdateError{Deployment: "ldap", Err: assert.AnError}))
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, mock.Anything, mock.Anything).Return(nil)
+		sut := &DefaultHostAliasUpdater{generator: succeedingHostAliasGenerator(t), fetcher: fetcher, updater: updater,
+			confirmAliasRemoval: true}
+
+		// when
+		result, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		require.Len(t, result.Deployments, 2)
+		assert.Equal(t, ActionRolledBack, result.Deployments[0].Action)
+		assert.Empty(t, result.Deployments[0].Error)
+		assert.Equal(t, ActionFailed, result.Deployments[1].Action)
+		assert.Equal(t, assert.AnError.Error(), result.Deployments[1].Error)
+	})
+	t.Run("should report kept failures and skipped deployments", func(t *testing.T) {
+		// given
+		fetcher := NewMockDeploymentFetcher(t)
+		fetcher.EXPECT().FetchAll(mock.Anything, testNamespace).Return(deployments, nil)
+		updater := NewMockDeploymentUpdater(t)
+		updater.EXPECT().UpdateHostAliases(mock.Anything, testNamespace, mock.Anything, hostAliases).Return(assert.AnError).Once()
+		sut := &DefaultHostAliasUpdater{generator: succeedingHostAliasGenerator(t), fetcher: fetcher, updater: updater,
+			failurePolicy: FailurePolicyFailFast, confirmAliasRemoval: true}
+
+		// when
+		result, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.Equal(t, StatusPartiallyFailed, result.Status)
+		assert.Equal(t, FailurePolicyFailFast, result.FailurePolicy)
+		require.Len(t, result.Deployments, 2)
+		assert.Equal(t, ActionFailed, result.Deployments[0].Action)
+		assert.Equal(t, assert.AnError.Error(), result.Deployments[0].Error)
+		assert.Equal(t, ActionSkipped, result.Deployments[1].Action)
+	})
+}
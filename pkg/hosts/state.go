@@ -0,0 +1,45 @@
+package hosts
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/k8s-host-change/pkg/logging"
+)
+
+const (
+	stateUpdated    = "updated"
+	stateRestored   = "restored"
+	stateUnexpected = "unexpected host aliases"
+)
+
+// logDeploymentStates logs for every dogu deployment whether it carries the new host aliases, its previous host
+// aliases or something else. This shows exactly what an interrupted or failed host change left behind.
+func (hau *DefaultHostAliasUpdater) logDeploymentStates(ctx context.Context, namespace string, hostAliases []corev1.HostAlias, previousHostAliases map[string][]corev1.HostAlias) {
+	logger := log.FromContext(ctx)
+	deployments, err := hau.fetcher.FetchAll(ctx, namespace)
+	if err != nil {
+		logger.Error(err, "Failed to fetch dogu deployments: state of dogu deployments is unknown")
+		return
+	}
+
+	for _, deploy := range deployments {
+		actual := deploy.Spec.Template.Spec.HostAliases
+		state := deploymentState(actual, hostAliases, previousHostAliases[deploy.Name])
+		logger.Info("Deployment state after host change", logging.DeploymentKey, deploy.Name, "state", state, "hostAliases", actual)
+	}
+}
+
+func deploymentState(actual []corev1.HostAlias, hostAliases []corev1.HostAlias, previous []corev1.HostAlias) string {
+	switch {
+	case equality.Semantic.DeepEqual(normalizeAliases(actual), normalizeAliases(previous)):
+		return stateRestored
+	case equality.Semantic.DeepEqual(normalizeAliases(actual), normalizeAliases(hostAliases)):
+		return stateUpdated
+	default:
+		return stateUnexpected
+	}
+}
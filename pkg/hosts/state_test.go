@@ -0,0 +1,19 @@
+package hosts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func Test_deploymentState(t *testing.T) {
+	previous := []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"old.example.com"}}}
+	other := []corev1.HostAlias{{IP: "10.0.0.2", Hostnames: []string{"other.example.com"}}}
+
+	assert.Equal(t, stateRestored, deploymentState(previous, hostAliases, previous))
+	assert.Equal(t, stateRestored, deploymentState([]corev1.HostAlias{}, hostAliases, nil))
+	assert.Equal(t, stateUpdated, deploymentState(hostAliases, hostAliases, previous))
+	assert.Equal(t, stateUnexpected, deploymentState(other, hostAliases, previous))
+}
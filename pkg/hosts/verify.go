@@ -0,0 +1,134 @@
+package hosts
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/go-multierror"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/k8s-host-change/pkg/hostsfile"
+	"github.com/cloudogu/k8s-host-change/pkg/logging"
+	"github.com/cloudogu/k8s-host-change/pkg/rollout"
+)
+
+// StalePodsError is returned if running pods of updated dogu deployments still do not carry the new host aliases after
+// waiting for their replacement.
+type StalePodsError struct {
+	Pods []rollout.StalePod
+}
+
+// Error lists all stale pods.
+func (e *StalePodsError) Error() string {
+	pods := make([]string, 0, len(e.Pods))
+	for _, pod := range e.Pods {
+		pods = append(pods, fmt.Sprintf("'%s' of deployment '%s'", pod.Name, pod.Deployment))
+	}
+
+	return fmt.Sprintf("%d running pods do not carry the new host aliases: %s", len(e.Pods), strings.Join(pods, ", "))
+}
+
+// HostsFileMismatchError is returned if the hosts files of pods of updated dogu deployments do not contain the new host
+// aliases.
+type HostsFileMismatchError struct {
+	Mismatches []hostsfile.Mismatch
+}
+
+// Error lists all mismatches.
+func (e *HostsFileMismatchError) Error() string {
+	mismatches := make([]string, 0, len(e.Mismatches))
+	for _, mismatch := range e.Mismatches {
+		mismatches = append(mismatches, fmt.Sprintf("'%s': %s", mismatch.Deployment, mismatch))
+	}
+
+	return fmt.Sprintf("hosts files of %d dogu deployments do not contain the new host aliases: %s",
+		len(e.Mismatches), strings.Join(mismatches, "; "))
+}
+
+// verifyUpdatedDeployments runs the configured verifications of the pods of all updated deployments.
+func (hau *DefaultHostAliasUpdater) verifyUpdatedDeployments(ctx context.Context, namespace string, hostAliases []corev1.HostAlias, result *Result) error {
+	if hau.podVerifier == nil && hau.hostsFileVerifier == nil {
+		return nil
+	}
+
+	ctx = logging.WithPhase(ctx, logging.PhaseVerify)
+	deployments, err := hau.fetcher.FetchAll(ctx, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to fetch dogu deployments for verification: %w", err)
+	}
+
+	var updated []appsv1.Deployment
+	for _, deploy := range deployments {
+		if result.action(deploy.Name) == ActionUpdated {
+			updated = append(updated, deploy)
+		}
+	}
+
+	var multiErr error
+	if hau.podVerifier != nil {
+		err = hau.verifyPods(ctx, namespace, updated, hostAliases, result)
+		if err != nil {
+			multiErr = multierror.Append(multiErr, err)
+		}
+	}
+
+	if hau.hostsFileVerifier != nil {
+		err = hau.verifyHostsFiles(ctx, namespace, updated, hostAliases, result)
+		if err != nil {
+			multiErr = multierror.Append(multiErr, err)
+		}
+	}
+
+	return multiErr
+}
+
+// verifyPods checks that the running pods of the given deployments carry the new host aliases and records stale pods
+// in the result. Stale pods are only an error if the verifier waits for their replacement.
+func (hau *DefaultHostAliasUpdater) verifyPods(ctx context.Context, namespace string, deployments []appsv1.Deployment, hostAliases []corev1.HostAlias, result *Result) error {
+	logger := log.FromContext(ctx)
+	logger.Info("Verify host aliases of running pods", "deployments", deploymentNames(deployments))
+	stalePods, err := hau.podVerifier.FindStalePods(ctx, namespace, deployments, hostAliases)
+	if err != nil {
+		return fmt.Errorf("failed to verify host aliases of running pods: %w", err)
+	}
+
+	if len(stalePods) == 0 {
+		logger.Info("All running pods carry the new host aliases")
+		return nil
+	}
+
+	for _, pod := range stalePods {
+		result.recordStalePod(pod.Deployment, pod.Name)
+	}
+	if !hau.waitForStalePods {
+		logger.Info("Running pods do not carry the new host aliases yet", "pods", len(stalePods))
+		return nil
+	}
+
+	return &StalePodsError{Pods: stalePods}
+}
+
+// verifyHostsFiles checks that the hosts file of a pod of every given deployment contains the new host aliases and
+// records mismatches in the result.
+func (hau *DefaultHostAliasUpdater) verifyHostsFiles(ctx context.Context, namespace string, deployments []appsv1.Deployment, hostAliases []corev1.HostAlias, result *Result) error {
+	logger := log.FromContext(ctx)
+	logger.Info("Verify hosts files of running pods", "deployments", deploymentNames(deployments))
+	mismatches, err := hau.hostsFileVerifier.VerifyHostsFiles(ctx, namespace, deployments, hostAliases)
+	if err != nil {
+		return fmt.Errorf("failed to verify hosts files of running pods: %w", err)
+	}
+
+	if len(mismatches) == 0 {
+		logger.Info("All verified hosts files contain the new host aliases")
+		return nil
+	}
+
+	for _, mismatch := range mismatches {
+		result.recordHostsFileMismatch(mismatch.Deployment, mismatch.String())
+	}
+
+	return &HostsFileMismatchError{Mismatches: mismatches}
+}
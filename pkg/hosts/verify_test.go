@@ -0,0 +1,192 @@
+package hosts
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	"github.com/cloudogu/k8s-host-change/pkg/hostsfile"
+	"github.com/cloudogu/k8s-host-change/pkg/rollout"
+)
+
+var stalePods = []rollout.StalePod{{Deployment: "cas", Name: "cas-old"}}
+
+func Test_hostAliasUpdater_UpdateHostsWithResult_verify(t *testing.T) {
+	t.Run("should succeed if all running pods carry the new host aliases", func(t *testing.T) {
+		// given
+		verifier := NewMockPodVerifier(t)
+		verifier.EXPECT().FindStalePods(mock.Anything, testNamespace, doguDeployments, hostAliases).Return(nil, nil)
+		sut := &DefaultHostAliasUpdater{
+			generator:   succeedingHostAliasGenerator(t),
+			fetcher:     succeedingDoguDeploymentFetcherOnRollback(t),
+			updater:     succeedingDeploymentUpdater(t),
+			podVerifier: verifier,
+		}
+
+		// when
+		result, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, StatusSucceeded, result.Status)
+		assert.Empty(t, result.Deployments[0].StalePods)
+	})
+	t.Run("should report stale pods without waiting", func(t *testing.T) {
+		// given
+		verifier := NewMockPodVerifier(t)
+		verifier.EXPECT().FindStalePods(mock.Anything, testNamespace, doguDeployments, hostAliases).Return(stalePods, nil)
+		sut := &DefaultHostAliasUpdater{
+			generator:   succeedingHostAliasGenerator(t),
+			fetcher:     succeedingDoguDeploymentFetcherOnRollback(t),
+			updater:     succeedingDeploymentUpdater(t),
+			podVerifier: verifier,
+		}
+
+		// when
+		result, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, StatusSucceeded, result.Status)
+		assert.Equal(t, []string{"cas-old"}, result.Deployments[0].StalePods)
+	})
+	t.Run("should fail on stale pods after waiting", func(t *testing.T) {
+		// given
+		verifier := NewMockPodVerifier(t)
+		verifier.EXPECT().FindStalePods(mock.Anything, testNamespace, doguDeployments, hostAliases).Return(stalePods, nil)
+		sut := &DefaultHostAliasUpdater{
+			generator:        succeedingHostAliasGenerator(t),
+			fetcher:          succeedingDoguDeploymentFetcherOnRollback(t),
+			updater:          succeedingDeploymentUpdater(t),
+			podVerifier:      verifier,
+			waitForStalePods: true,
+		}
+
+		// when
+		result, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		var stalePodsErr *StalePodsError
+		require.ErrorAs(t, err, &stalePodsErr)
+		assert.ErrorContains(t, err, "1 running pods do not carry the new host aliases: 'cas-old' of deployment 'cas'")
+		assert.Equal(t, StatusFailed, result.Status)
+		assert.Equal(t, []string{"cas-old"}, result.Deployments[0].StalePods)
+	})
+	t.Run("should fail to verify pods", func(t *testing.T) {
+		// given
+		verifier := NewMockPodVerifier(t)
+		verifier.EXPECT().FindStalePods(mock.Anything, testNamespace, doguDeployments, hostAliases).Return(nil, assert.AnError)
+		sut := &DefaultHostAliasUpdater{
+			generator:   succeedingHostAliasGenerator(t),
+			fetcher:     succeedingDoguDeploymentFetcherOnRollback(t),
+			updater:     succeedingDeploymentUpdater(t),
+			podVerifier: verifier,
+		}
+
+		// when
+		_, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to verify host aliases of running pods")
+	})
+	t.Run("should not verify pods of failed host change", func(t *testing.T) {
+		// given
+		sut := &DefaultHostAliasUpdater{
+			generator:   succeedingHostAliasGenerator(t),
+			fetcher:     succeedingDoguDeploymentFetcherOnRollback(t),
+			updater:     failingDeploymentUpdater(t),
+			podVerifier: NewMockPodVerifier(t),
+		}
+
+		// when
+		_, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to update host-aliases of dogu deployments in cluster")
+	})
+	t.Run("should succeed if hosts files contain the new host aliases", func(t *testing.T) {
+		// given
+		verifier := NewMockHostsFileVerifier(t)
+		verifier.EXPECT().VerifyHostsFiles(mock.Anything, testNamespace, doguDeployments, hostAliases).Return(nil, nil)
+		sut := &DefaultHostAliasUpdater{
+			generator:         succeedingHostAliasGenerator(t),
+			fetcher:           succeedingDoguDeploymentFetcherOnRollback(t),
+			updater:           succeedingDeploymentUpdater(t),
+			hostsFileVerifier: verifier,
+		}
+
+		// when
+		result, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, StatusSucceeded, result.Status)
+	})
+	t.Run("should fail on hosts file mismatch", func(t *testing.T) {
+		// given
+		mismatch := hostsfile.Mismatch{Deployment: "cas", Pod: "cas-1", MissingHostAliases: hostAliases}
+		verifier := NewMockHostsFileVerifier(t)
+		verifier.EXPECT().VerifyHostsFiles(mock.Anything, testNamespace, doguDeployments, hostAliases).Return([]hostsfile.Mismatch{mismatch}, nil)
+		sut := &DefaultHostAliasUpdater{
+			generator:         succeedingHostAliasGenerator(t),
+			fetcher:           succeedingDoguDeploymentFetcherOnRollback(t),
+			updater:           succeedingDeploymentUpdater(t),
+			hostsFileVerifier: verifier,
+		}
+
+		// when
+		result, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		var mismatchErr *HostsFileMismatchError
+		require.ErrorAs(t, err, &mismatchErr)
+		assert.ErrorContains(t, err, "hosts files of 1 dogu deployments do not contain the new host aliases: "+
+			"'cas': hosts file of pod 'cas-1': missing 1.2.3.4 www.example.com")
+		assert.Equal(t, StatusFailed, result.Status)
+		assert.Equal(t, "hosts file of pod 'cas-1': missing 1.2.3.4 www.example.com", result.Deployments[0].HostsFileMismatch)
+	})
+	t.Run("should fail to verify hosts files", func(t *testing.T) {
+		// given
+		verifier := NewMockHostsFileVerifier(t)
+		verifier.EXPECT().VerifyHostsFiles(mock.Anything, testNamespace, doguDeployments, hostAliases).Return(nil, assert.AnError)
+		sut := &DefaultHostAliasUpdater{
+			generator:         succeedingHostAliasGenerator(t),
+			fetcher:           succeedingDoguDeploymentFetcherOnRollback(t),
+			updater:           succeedingDeploymentUpdater(t),
+			hostsFileVerifier: verifier,
+		}
+
+		// when
+		_, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to verify hosts files of running pods")
+	})
+	t.Run("should fail to fetch deployments for verification", func(t *testing.T) {
+		// given
+		sut := &DefaultHostAliasUpdater{
+			generator:         succeedingHostAliasGenerator(t),
+			fetcher:           failingDoguDeploymentFetcherOnRollback(t),
+			updater:           succeedingDeploymentUpdater(t),
+			hostsFileVerifier: NewMockHostsFileVerifier(t),
+		}
+
+		// when
+		_, err := sut.UpdateHostsWithResult(context.TODO(), testNamespace)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to fetch dogu deployments for verification")
+	})
+}
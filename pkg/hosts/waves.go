@@ -0,0 +1,73 @@
+package hosts
+
+import (
+	"fmt"
+
+	appsv1 "k8s.io/api/apps/v1"
+)
+
+const doguNameLabelKey = "dogu.name"
+
+// planWaves groups the given dogu deployments into waves so that every dogu is updated after all dogus it depends on.
+// The first wave contains all dogus without dependencies to other deployed dogus. Dependencies to dogus without a
+// deployment are ignored. The order of the deployments inside a wave is preserved.
+func planWaves(deployments []appsv1.Deployment, dependencies map[string][]string) ([][]appsv1.Deployment, error) {
+	deployed := make(map[string]bool, len(deployments))
+	for _, deploy := range deployments {
+		deployed[deploy.Labels[doguNameLabelKey]] = true
+	}
+
+	levels := map[string]int{}
+	visiting := map[string]bool{}
+	var levelOf func(doguName string) (int, error)
+	levelOf = func(doguName string) (int, error) {
+		if level, ok := levels[doguName]; ok {
+			return level, nil
+		}
+		if visiting[doguName] {
+			return 0, fmt.Errorf("dependency cycle detected at dogu '%s'", doguName)
+		}
+		visiting[doguName] = true
+
+		level := 0
+		for _, dependency := range dependencies[doguName] {
+			if !deployed[dependency] {
+				continue
+			}
+
+			dependencyLevel, err := levelOf(dependency)
+			if err != nil {
+				return 0, err
+			}
+			level = max(level, dependencyLevel+1)
+		}
+
+		visiting[doguName] = false
+		levels[doguName] = level
+		return level, nil
+	}
+
+	var waves [][]appsv1.Deployment
+	for _, deploy := range deployments {
+		level, err := levelOf(deploy.Labels[doguNameLabelKey])
+		if err != nil {
+			return nil, err
+		}
+
+		for len(waves) <= level {
+			waves = append(waves, nil)
+		}
+		waves[level] = append(waves[level], deploy)
+	}
+
+	return waves, nil
+}
+
+func deploymentNames(deployments []appsv1.Deployment) []string {
+	names := make([]string, 0, len(deployments))
+	for _, deploy := range deployments {
+		names = append(names, deploy.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,76 @@
+package hosts
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func Test_planWaves(t *testing.T) {
+	postgresql := doguDeployment("postgresql")
+	ldap := doguDeployment("ldap")
+	cas := doguDeployment("cas")
+	redmine := doguDeployment("redmine")
+	nginx := doguDeployment("nginx")
+
+	t.Run("should put all deployments in one wave without dependencies", func(t *testing.T) {
+		// when
+		actual, err := planWaves([]appsv1.Deployment{cas, ldap}, map[string][]string{})
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, [][]appsv1.Deployment{{cas, ldap}}, actual)
+	})
+	t.Run("should order deployments by dependencies", func(t *testing.T) {
+		// given
+		dependencies := map[string][]string{
+			"cas":     {"ldap", "postfix"},
+			"redmine": {"cas", "postgresql"},
+			"ldap":    {},
+		}
+
+		// when
+		actual, err := planWaves([]appsv1.Deployment{redmine, cas, nginx, ldap, postgresql}, dependencies)
+
+		// then
+		require.NoError(t, err)
+		expected := [][]appsv1.Deployment{
+			{nginx, ldap, postgresql},
+			{cas},
+			{redmine},
+		}
+		assert.Equal(t, expected, actual)
+	})
+	t.Run("should fail on dependency cycle", func(t *testing.T) {
+		// given
+		dependencies := map[string][]string{
+			"cas":  {"ldap"},
+			"ldap": {"cas"},
+		}
+
+		// when
+		_, err := planWaves([]appsv1.Deployment{cas, ldap}, dependencies)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "dependency cycle detected at dogu 'cas'")
+	})
+}
+
+func Test_deploymentNames(t *testing.T) {
+	assert.Equal(t, []string{"cas", "ldap"}, deploymentNames([]appsv1.Deployment{doguDeployment("cas"), doguDeployment("ldap")}))
+}
+
+func doguDeployment(doguName string) appsv1.Deployment {
+	return appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      doguName,
+			Namespace: testNamespace,
+			Labels:    map[string]string{"dogu.name": doguName},
+		},
+	}
+}
@@ -0,0 +1,53 @@
+package hostsfile
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+type spdyExecutor struct {
+	restConfig *rest.Config
+	clientSet  kubernetes.Interface
+}
+
+// NewExecutor creates an executor which runs commands in containers via the pods/exec subresource of the api server.
+func NewExecutor(restConfig *rest.Config, clientSet kubernetes.Interface) *spdyExecutor {
+	return &spdyExecutor{restConfig: restConfig, clientSet: clientSet}
+}
+
+// Exec runs the given command in the container of the given pod and returns its standard output.
+func (e *spdyExecutor) Exec(ctx context.Context, namespace string, pod string, container string, command []string) ([]byte, error) {
+	request := e.clientSet.CoreV1().RESTClient().Post().
+		Namespace(namespace).
+		Resource("pods").
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	executor, err := remotecommand.NewSPDYExecutor(e.restConfig, "POST", request.URL())
+	if err != nil {
+		return nil, fmt.Errorf("failed to create executor for pod '%s': %w", pod, err)
+	}
+
+	stdout, stderr := &bytes.Buffer{}, &bytes.Buffer{}
+	err = executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: stdout, Stderr: stderr})
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute '%s' in container '%s' of pod '%s': %w: %s",
+			strings.Join(command, " "), container, pod, err, strings.TrimSpace(stderr.String()))
+	}
+
+	return stdout.Bytes(), nil
+}
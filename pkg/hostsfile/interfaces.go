@@ -0,0 +1,9 @@
+package hostsfile
+
+import "context"
+
+// PodExecutor runs commands in the containers of running pods.
+type PodExecutor interface {
+	// Exec runs the given command in the container of the given pod and returns its standard output.
+	Exec(ctx context.Context, namespace string, pod string, container string, command []string) ([]byte, error)
+}
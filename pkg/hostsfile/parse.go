@@ -0,0 +1,69 @@
+package hostsfile
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"net"
+	"strings"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+// hostAliasesHeader starts the section of the hosts file which the kubelet renders from the host aliases of a pod.
+const hostAliasesHeader = "# Entries added by HostAliases."
+
+// ParseHostAliases returns the entries of the host aliases section of a hosts file rendered by the kubelet.
+// Entries before the section, e.g. localhost and the pod's own host name, are ignored.
+func ParseHostAliases(content []byte) []corev1.HostAlias {
+	var aliases []corev1.HostAlias
+	inSection := false
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == hostAliasesHeader {
+			inSection = true
+			continue
+		}
+		if !inSection || line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			continue
+		}
+		aliases = append(aliases, corev1.HostAlias{IP: fields[0], Hostnames: fields[1:]})
+	}
+
+	return aliases
+}
+
+// Parse parses content in hosts file syntax. Every line maps an IPv4 or IPv6 address to one or more host names.
+// Everything after '#' is a comment. Lines with an invalid IP or without host names are rejected.
+func Parse(content []byte) ([]corev1.HostAlias, error) {
+	var aliases []corev1.HostAlias
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for number := 1; scanner.Scan(); number++ {
+		line, _, _ := strings.Cut(scanner.Text(), "#")
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if net.ParseIP(fields[0]) == nil {
+			return nil, fmt.Errorf("line %d: '%s' is not a valid ip", number, fields[0])
+		}
+		if len(fields) < 2 {
+			return nil, fmt.Errorf("line %d: no host names for ip '%s'", number, fields[0])
+		}
+		aliases = append(aliases, corev1.HostAlias{IP: fields[0], Hostnames: fields[1:]})
+	}
+
+	err := scanner.Err()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read hosts file: %w", err)
+	}
+
+	return aliases, nil
+}
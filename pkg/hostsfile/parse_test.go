@@ -0,0 +1,77 @@
+package hostsfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+const renderedHostsFile = `# Kubernetes-managed hosts file.
+127.0.0.1	localhost
+::1	localhost ip6-localhost ip6-loopback
+10.244.0.12	cas-7d9f8c6b5-x2v4q
+
+# Entries added by HostAliases.
+10.0.0.1	ces.example.com	fqdn.example.com
+10.0.0.2	git.example.com
+`
+
+func TestParseHostAliases(t *testing.T) {
+	t.Run("should return entries of the host aliases section", func(t *testing.T) {
+		// when
+		actual := ParseHostAliases([]byte(renderedHostsFile))
+
+		// then
+		assert.Equal(t, []corev1.HostAlias{
+			{IP: "10.0.0.1", Hostnames: []string{"ces.example.com", "fqdn.example.com"}},
+			{IP: "10.0.0.2", Hostnames: []string{"git.example.com"}},
+		}, actual)
+	})
+	t.Run("should return nothing without host aliases section", func(t *testing.T) {
+		// when
+		actual := ParseHostAliases([]byte("127.0.0.1\tlocalhost\n"))
+
+		// then
+		assert.Empty(t, actual)
+	})
+}
+
+func TestParse(t *testing.T) {
+	t.Run("should parse ipv4 and ipv6 entries with multiple host names", func(t *testing.T) {
+		// given
+		content := `# hosts of the network team
+10.0.0.1	ces.example.com fqdn.example.com # primary
+
+fd00::1 git.example.com
+`
+
+		// when
+		actual, err := Parse([]byte(content))
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []corev1.HostAlias{
+			{IP: "10.0.0.1", Hostnames: []string{"ces.example.com", "fqdn.example.com"}},
+			{IP: "fd00::1", Hostnames: []string{"git.example.com"}},
+		}, actual)
+	})
+	t.Run("should fail on invalid ip", func(t *testing.T) {
+		// when
+		_, err := Parse([]byte("10.0.0.1 ces.example.com\n10.0.0.300 git.example.com\n"))
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "line 2: '10.0.0.300' is not a valid ip")
+	})
+	t.Run("should fail on missing host names", func(t *testing.T) {
+		// when
+		_, err := Parse([]byte("10.0.0.1 # nothing\n"))
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "line 1: no host names for ip '10.0.0.1'")
+	})
+}
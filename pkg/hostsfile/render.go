@@ -0,0 +1,96 @@
+package hostsfile
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pmezard/go-difflib/difflib"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// PodIPPlaceholder replaces the IP of the pod in a rendered hosts file because it is only known at runtime.
+	PodIPPlaceholder = "<pod-ip>"
+	// PodNamePlaceholder replaces the host name of the pod in a rendered hosts file because it is only known at
+	// runtime.
+	PodNamePlaceholder = "<pod-name>"
+)
+
+const managedHostsHeader = "# Kubernetes-managed hosts file."
+
+// Render returns the hosts file the kubelet writes into a pod with the given host aliases. The IP and the host name
+// of the pod are replaced with placeholders.
+func Render(hostAliases []corev1.HostAlias) string {
+	var b strings.Builder
+	b.WriteString(managedHostsHeader + "\n")
+	b.WriteString("127.0.0.1\tlocalhost\n")
+	b.WriteString("::1\tlocalhost ip6-localhost ip6-loopback\n")
+	b.WriteString("fe00::0\tip6-localnet\n")
+	b.WriteString("fe00::0\tip6-mcastprefix\n")
+	b.WriteString("fe00::1\tip6-allnodes\n")
+	b.WriteString("fe00::2\tip6-allrouters\n")
+	_, _ = fmt.Fprintf(&b, "%s\t%s\n", PodIPPlaceholder, PodNamePlaceholder)
+
+	if len(hostAliases) == 0 {
+		return b.String()
+	}
+
+	b.WriteString("\n" + hostAliasesHeader + "\n")
+	for _, alias := range hostAliases {
+		_, _ = fmt.Fprintf(&b, "%s\t%s\n", alias.IP, strings.Join(alias.Hostnames, "\t"))
+	}
+
+	return b.String()
+}
+
+// Format renders the given host aliases in hosts file syntax which Parse accepts. The host names of the same ip are
+// joined into one line. Every line of the given header is written as comment before the entries.
+func Format(header string, hostAliases []corev1.HostAlias) string {
+	var b strings.Builder
+	if header != "" {
+		for _, line := range strings.Split(header, "\n") {
+			b.WriteString(strings.TrimSpace("# "+line) + "\n")
+		}
+	}
+
+	var ips []string
+	hostnames := map[string][]string{}
+	for _, alias := range hostAliases {
+		if _, found := hostnames[alias.IP]; !found {
+			ips = append(ips, alias.IP)
+		}
+		hostnames[alias.IP] = append(hostnames[alias.IP], alias.Hostnames...)
+	}
+	for _, ip := range ips {
+		_, _ = fmt.Fprintf(&b, "%s\t%s\n", ip, strings.Join(hostnames[ip], " "))
+	}
+
+	return b.String()
+}
+
+// Diff returns the unified diff between the hosts files rendered from the current and the new host aliases of the
+// deployment with the given name. The diff is empty if both hosts files are equal.
+func Diff(deployment string, current []corev1.HostAlias, hostAliases []corev1.HostAlias) (string, error) {
+	diff, err := difflib.GetUnifiedDiffString(difflib.UnifiedDiff{
+		A:        splitLines(Render(current)),
+		B:        splitLines(Render(hostAliases)),
+		FromFile: fmt.Sprintf("%s:/etc/hosts (current)", deployment),
+		ToFile:   fmt.Sprintf("%s:/etc/hosts (new)", deployment),
+		Context:  3,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to diff hosts file of deployment '%s': %w", deployment, err)
+	}
+
+	return diff, nil
+}
+
+// splitLines splits the text after every line break. Unlike difflib.SplitLines it adds no empty line at the end.
+func splitLines(text string) []string {
+	lines := strings.SplitAfter(text, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+
+	return lines
+}
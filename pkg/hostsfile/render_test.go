@@ -0,0 +1,102 @@
+package hostsfile
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestRender(t *testing.T) {
+	t.Run("should render host aliases section", func(t *testing.T) {
+		// given
+		aliases := []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"ces.example.com", "fqdn.example.com"}}}
+
+		// when
+		actual := Render(aliases)
+
+		// then
+		assert.Equal(t, "# Kubernetes-managed hosts file.\n"+
+			"127.0.0.1\tlocalhost\n"+
+			"::1\tlocalhost ip6-localhost ip6-loopback\n"+
+			"fe00::0\tip6-localnet\n"+
+			"fe00::0\tip6-mcastprefix\n"+
+			"fe00::1\tip6-allnodes\n"+
+			"fe00::2\tip6-allrouters\n"+
+			"<pod-ip>\t<pod-name>\n"+
+			"\n"+
+			"# Entries added by HostAliases.\n"+
+			"10.0.0.1\tces.example.com\tfqdn.example.com\n", actual)
+		assert.Equal(t, aliases, ParseHostAliases([]byte(actual)))
+	})
+	t.Run("should omit host aliases section without host aliases", func(t *testing.T) {
+		// when
+		actual := Render(nil)
+
+		// then
+		assert.NotContains(t, actual, hostAliasesHeader)
+		assert.Contains(t, actual, "<pod-ip>\t<pod-name>\n")
+	})
+}
+
+func TestFormat(t *testing.T) {
+	t.Run("should join host names of the same ip", func(t *testing.T) {
+		// given
+		aliases := []corev1.HostAlias{
+			{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}},
+			{IP: "fd00::2", Hostnames: []string{"git.local"}},
+			{IP: "10.0.0.1", Hostnames: []string{"mail.local"}},
+		}
+
+		// when
+		actual := Format("generated\nby test", aliases)
+
+		// then
+		assert.Equal(t, "# generated\n# by test\n10.0.0.1\tces.example.com mail.local\nfd00::2\tgit.local\n", actual)
+		parsed, err := Parse([]byte(actual))
+		require.NoError(t, err)
+		assert.Len(t, parsed, 2)
+	})
+	t.Run("should render nothing without header and host aliases", func(t *testing.T) {
+		// when
+		actual := Format("", nil)
+
+		// then
+		assert.Empty(t, actual)
+	})
+}
+
+func TestDiff(t *testing.T) {
+	t.Run("should return unified diff", func(t *testing.T) {
+		// given
+		current := []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}}}
+		hostAliases := []corev1.HostAlias{{IP: "10.0.0.2", Hostnames: []string{"ces.example.com"}}}
+
+		// when
+		actual, err := Diff("cas", current, hostAliases)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "--- cas:/etc/hosts (current)\n"+
+			"+++ cas:/etc/hosts (new)\n"+
+			"@@ -8,4 +8,4 @@\n"+
+			" <pod-ip>\t<pod-name>\n"+
+			" \n"+
+			" # Entries added by HostAliases.\n"+
+			"-10.0.0.1\tces.example.com\n"+
+			"+10.0.0.2\tces.example.com\n", actual)
+	})
+	t.Run("should return empty diff for equal host aliases", func(t *testing.T) {
+		// given
+		aliases := []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}}}
+
+		// when
+		actual, err := Diff("cas", aliases, aliases)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+}
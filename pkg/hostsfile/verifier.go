@@ -0,0 +1,141 @@
+package hostsfile
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/k8s-host-change/pkg/deployment"
+	"github.com/cloudogu/k8s-host-change/pkg/logging"
+)
+
+// hostsFilePath is the hosts file the kubelet renders the host aliases of a pod into.
+const hostsFilePath = "/etc/hosts"
+
+// Mismatch describes a pod whose hosts file does not contain the expected host aliases.
+type Mismatch struct {
+	// Deployment is the name of the deployment the pod belongs to.
+	Deployment string `json:"deployment"`
+	// Pod is the name of the verified pod. It is empty if the deployment has no running pod with the expected host
+	// aliases in its spec.
+	Pod string `json:"pod,omitempty"`
+	// MissingHostAliases are the expected host names which are not in the hosts file.
+	MissingHostAliases []corev1.HostAlias `json:"missingHostAliases,omitempty"`
+	// UnexpectedHostAliases are the host names of the host aliases section which were not expected.
+	UnexpectedHostAliases []corev1.HostAlias `json:"unexpectedHostAliases,omitempty"`
+	// Error is the reason why the hosts file could not be verified.
+	Error string `json:"error,omitempty"`
+}
+
+// String describes the mismatch in a single line.
+func (m Mismatch) String() string {
+	if m.Error != "" {
+		return m.Error
+	}
+
+	var parts []string
+	for _, alias := range m.MissingHostAliases {
+		parts = append(parts, fmt.Sprintf("missing %s %s", alias.IP, strings.Join(alias.Hostnames, " ")))
+	}
+	for _, alias := range m.UnexpectedHostAliases {
+		parts = append(parts, fmt.Sprintf("unexpected %s %s", alias.IP, strings.Join(alias.Hostnames, " ")))
+	}
+
+	return fmt.Sprintf("hosts file of pod '%s': %s", m.Pod, strings.Join(parts, ", "))
+}
+
+type verifier struct {
+	clientSet kubernetes.Interface
+	executor  PodExecutor
+}
+
+// NewVerifier creates a verifier which reads the hosts file of pods with the given executor.
+func NewVerifier(clientSet kubernetes.Interface, executor PodExecutor) *verifier {
+	return &verifier{clientSet: clientSet, executor: executor}
+}
+
+// VerifyHostsFiles reads the hosts file of one running pod of every given deployment and compares its host aliases
+// section with the given host aliases. Only pods carrying the given host aliases in their spec are considered, so that
+// the check proves that the kubelet rendered them. Deployments without such a pod and pods whose hosts file cannot be
+// read are reported as mismatch with an error. An error is only returned if the pods cannot be listed.
+func (v *verifier) VerifyHostsFiles(ctx context.Context, namespace string, deployments []appsv1.Deployment, hostAliases []corev1.HostAlias) ([]Mismatch, error) {
+	var mismatches []Mismatch
+	for _, deploy := range deployments {
+		logger := log.FromContext(ctx).WithValues(logging.DeploymentKey, deploy.Name)
+		pod, err := v.findPod(ctx, namespace, deploy, hostAliases)
+		if err != nil {
+			return nil, err
+		}
+		if pod == nil {
+			logger.Info("No running pod with the expected host aliases found to verify the hosts file")
+			mismatches = append(mismatches, Mismatch{
+				Deployment: deploy.Name,
+				Error:      fmt.Sprintf("no running pod of deployment '%s' carries the expected host aliases", deploy.Name),
+			})
+			continue
+		}
+
+		content, err := v.executor.Exec(ctx, namespace, pod.Name, pod.Spec.Containers[0].Name, []string{"cat", hostsFilePath})
+		if err != nil {
+			logger.Error(err, "Failed to read hosts file", "pod", pod.Name)
+			mismatches = append(mismatches, Mismatch{Deployment: deploy.Name, Pod: pod.Name, Error: err.Error()})
+			continue
+		}
+
+		actual := ParseHostAliases(content)
+		missing := deployment.SubtractHostAliases(hostAliases, actual)
+		unexpected := deployment.SubtractHostAliases(actual, hostAliases)
+		if len(missing) == 0 && len(unexpected) == 0 {
+			logger.Info("Hosts file contains the expected host aliases", "pod", pod.Name)
+			continue
+		}
+
+		logger.Info("Hosts file does not contain the expected host aliases", "pod", pod.Name,
+			"missingHostAliases", missing, "unexpectedHostAliases", unexpected)
+		mismatches = append(mismatches, Mismatch{
+			Deployment:            deploy.Name,
+			Pod:                   pod.Name,
+			MissingHostAliases:    missing,
+			UnexpectedHostAliases: unexpected,
+		})
+	}
+
+	return mismatches, nil
+}
+
+// findPod returns the newest running pod of the given deployment which carries the given host aliases in its spec or
+// nil if there is none.
+func (v *verifier) findPod(ctx context.Context, namespace string, deploy appsv1.Deployment, hostAliases []corev1.HostAlias) (*corev1.Pod, error) {
+	if deploy.Spec.Selector == nil {
+		return nil, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pod selector of deployment '%s': %w", deploy.Name, err)
+	}
+
+	pods, err := v.clientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods of deployment '%s': %w", deploy.Name, err)
+	}
+
+	var newest *corev1.Pod
+	for i, pod := range pods.Items {
+		if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil || len(pod.Spec.Containers) == 0 ||
+			!deployment.SameHostAliases(pod.Spec.HostAliases, hostAliases) {
+			continue
+		}
+		if newest == nil || newest.CreationTimestamp.Before(&pod.CreationTimestamp) {
+			newest = &pods.Items[i]
+		}
+	}
+
+	return newest, nil
+}
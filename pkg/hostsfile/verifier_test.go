@@ -0,0 +1,175 @@
+package hostsfile
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const testNamespace = "ecosystem"
+
+var (
+	expectedHostAliases = []corev1.HostAlias{
+		{IP: "10.0.0.1", Hostnames: []string{"ces.example.com", "fqdn.example.com"}},
+		{IP: "10.0.0.2", Hostnames: []string{"git.example.com"}},
+	}
+	catHostsFile = []string{"cat", "/etc/hosts"}
+	creationTime = time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+)
+
+func TestNewVerifier(t *testing.T) {
+	// given
+	clientSet := fake.NewSimpleClientset()
+	executor := NewMockPodExecutor(t)
+
+	// when
+	sut := NewVerifier(clientSet, executor)
+
+	// then
+	require.NotNil(t, sut)
+	assert.Equal(t, clientSet, sut.clientSet)
+	assert.Equal(t, executor, sut.executor)
+}
+
+func Test_verifier_VerifyHostsFiles(t *testing.T) {
+	deployments := []appsv1.Deployment{doguDeployment("cas")}
+
+	t.Run("should succeed if hosts file contains the expected host aliases", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(pod("cas-1", "cas", 0, expectedHostAliases))
+		executor := NewMockPodExecutor(t)
+		executor.EXPECT().Exec(mock.Anything, testNamespace, "cas-1", "cas", catHostsFile).Return([]byte(renderedHostsFile), nil)
+		sut := NewVerifier(clientSet, executor)
+
+		// when
+		actual, err := sut.VerifyHostsFiles(context.TODO(), testNamespace, deployments, expectedHostAliases)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+	t.Run("should verify the newest pod with the expected host aliases", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(
+			pod("cas-1", "cas", 0, expectedHostAliases),
+			pod("cas-2", "cas", time.Minute, expectedHostAliases),
+			pod("cas-old", "cas", 2*time.Minute, nil),
+		)
+		executor := NewMockPodExecutor(t)
+		executor.EXPECT().Exec(mock.Anything, testNamespace, "cas-2", "cas", catHostsFile).Return([]byte(renderedHostsFile), nil)
+		sut := NewVerifier(clientSet, executor)
+
+		// when
+		actual, err := sut.VerifyHostsFiles(context.TODO(), testNamespace, deployments, expectedHostAliases)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+	t.Run("should report missing and unexpected host names", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(pod("cas-1", "cas", 0, expectedHostAliases))
+		executor := NewMockPodExecutor(t)
+		executor.EXPECT().Exec(mock.Anything, testNamespace, "cas-1", "cas", catHostsFile).
+			Return([]byte("# Entries added by HostAliases.\n10.0.0.1\tces.example.com\n10.0.0.9\told.example.com\n"), nil)
+		sut := NewVerifier(clientSet, executor)
+
+		// when
+		actual, err := sut.VerifyHostsFiles(context.TODO(), testNamespace, deployments, expectedHostAliases)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, Mismatch{
+			Deployment: "cas",
+			Pod:        "cas-1",
+			MissingHostAliases: []corev1.HostAlias{
+				{IP: "10.0.0.1", Hostnames: []string{"fqdn.example.com"}},
+				{IP: "10.0.0.2", Hostnames: []string{"git.example.com"}},
+			},
+			UnexpectedHostAliases: []corev1.HostAlias{{IP: "10.0.0.9", Hostnames: []string{"old.example.com"}}},
+		}, actual[0])
+		assert.Equal(t, "hosts file of pod 'cas-1': missing 10.0.0.1 fqdn.example.com, missing 10.0.0.2 git.example.com, "+
+			"unexpected 10.0.0.9 old.example.com", actual[0].String())
+	})
+	t.Run("should report deployment without pod carrying the expected host aliases", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(pod("cas-old", "cas", 0, nil))
+		sut := NewVerifier(clientSet, NewMockPodExecutor(t))
+
+		// when
+		actual, err := sut.VerifyHostsFiles(context.TODO(), testNamespace, deployments, expectedHostAliases)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, "no running pod of deployment 'cas' carries the expected host aliases", actual[0].String())
+	})
+	t.Run("should report failed exec", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(pod("cas-1", "cas", 0, expectedHostAliases))
+		executor := NewMockPodExecutor(t)
+		executor.EXPECT().Exec(mock.Anything, testNamespace, "cas-1", "cas", catHostsFile).Return(nil, assert.AnError)
+		sut := NewVerifier(clientSet, executor)
+
+		// when
+		actual, err := sut.VerifyHostsFiles(context.TODO(), testNamespace, deployments, expectedHostAliases)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, Mismatch{Deployment: "cas", Pod: "cas-1", Error: assert.AnError.Error()}, actual[0])
+	})
+	t.Run("should fail to list pods", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		clientSet.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, assert.AnError
+		})
+		sut := NewVerifier(clientSet, NewMockPodExecutor(t))
+
+		// when
+		_, err := sut.VerifyHostsFiles(context.TODO(), testNamespace, deployments, expectedHostAliases)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to list pods of deployment 'cas'")
+	})
+}
+
+func doguDeployment(name string) appsv1.Deployment {
+	return appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"dogu.name": name}},
+		},
+	}
+}
+
+func pod(name string, dogu string, createdAfter time.Duration, aliases []corev1.HostAlias) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              name,
+			Namespace:         testNamespace,
+			Labels:            map[string]string{"dogu.name": dogu},
+			CreationTimestamp: metav1.NewTime(creationTime.Add(createdAfter)),
+		},
+		Spec: corev1.PodSpec{
+			Containers:  []corev1.Container{{Name: dogu}},
+			HostAliases: aliases,
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+}
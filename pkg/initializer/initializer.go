@@ -1,47 +1,188 @@
 package initializer
 
 import (
+	"errors"
+	"fmt"
 	"os"
+	"strings"
+	"time"
 
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
-	ctrl "sigs.k8s.io/controller-runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const namespaceEnvName = "NAMESPACE"
 
+const defaultUserAgent = "k8s-host-change"
+
+// serviceAccountNamespaceFile contains the namespace of the pod if this program runs inside a cluster.
+var serviceAccountNamespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+
+// inClusterConfig is replaced in tests.
+var inClusterConfig = rest.InClusterConfig
+
+// newClient is replaced in tests.
+var newClient = client.New
+
 // Initializer is used for populating this program with configuration values.
 type Initializer interface {
 	// GetNamespace retrieves the namespace this program should work in.
 	GetNamespace() string
+	// RestConfig loads the kubernetes rest config and applies the client settings.
+	RestConfig() (*rest.Config, error)
 	// CreateClientSet creates a client set from a kubernetes rest config.
 	CreateClientSet() (kubernetes.Interface, error)
+	// CreateClient creates a controller-runtime client from a kubernetes rest config. In contrast to the client set
+	// it supports custom resources registered in the scheme.
+	CreateClient() (client.Client, error)
+}
+
+// Options overrides the configuration detected from the environment. Empty fields are ignored.
+type Options struct {
+	// Kubeconfig is the path of the kubeconfig file.
+	// If neither a kubeconfig, a context nor $KUBECONFIG is given, the in-cluster config is preferred.
+	Kubeconfig string
+	// Context is the kubeconfig context.
+	Context string
+	// Namespace is the namespace this program should work in.
+	Namespace string
+	// QPS is the maximum number of queries per second to the api server.
+	QPS float32
+	// Burst is the maximum burst of queries to the api server.
+	Burst int
+	// Timeout limits a single request to the api server.
+	Timeout time.Duration
+	// UserAgent identifies this program at the api server. Defaults to "k8s-host-change".
+	UserAgent string
+	// Scheme is used by the controller-runtime client. Defaults to the client-go scheme.
+	Scheme *runtime.Scheme
 }
 
 type defaultInitializer struct {
+	opts Options
+	// restConfig is loaded once by RestConfig and shared with the clients.
+	restConfig *rest.Config
 }
 
-var New = func() Initializer {
-	return &defaultInitializer{}
+var New = func(opts Options) Initializer {
+	return &defaultInitializer{opts: opts}
 }
 
-// GetNamespace retrieves the namespace this program should work in from the NAMESPACE environment variable.
-// If the NAMESPACE var is not set or contains an empty string, the 'default' namespace is returned instead.
+// GetNamespace retrieves the namespace this program should work in. If no namespace is given in the options, it is
+// read from the NAMESPACE environment variable and then from the service account if running inside a cluster.
+// If no namespace is found, the 'default' namespace is returned instead.
 func (i *defaultInitializer) GetNamespace() string {
+	if i.opts.Namespace != "" {
+		return i.opts.Namespace
+	}
+
 	env, present := os.LookupEnv(namespaceEnvName)
 	if present && env != "" {
 		return env
 	}
 
+	namespace, err := os.ReadFile(serviceAccountNamespaceFile)
+	if err == nil && strings.TrimSpace(string(namespace)) != "" {
+		return strings.TrimSpace(string(namespace))
+	}
+
 	return "default"
 }
 
-// CreateClientSet creates a client set from a kubernetes rest config.
+// RestConfig loads the kubernetes rest config. The config is loaded from the kubeconfig and context in the options
+// or from $KUBECONFIG if any of them is given. Otherwise, the in-cluster config is used and, outside a cluster, the
+// default kubeconfig ~/.kube/config. The config is only loaded once.
+func (i *defaultInitializer) RestConfig() (*rest.Config, error) {
+	if i.restConfig != nil {
+		return i.restConfig, nil
+	}
+
+	restConfig, err := i.loadRestConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	if i.opts.QPS > 0 {
+		restConfig.QPS = i.opts.QPS
+	}
+	if i.opts.Burst > 0 {
+		restConfig.Burst = i.opts.Burst
+	}
+	if i.opts.Timeout > 0 {
+		restConfig.Timeout = i.opts.Timeout
+	}
+
+	restConfig.UserAgent = defaultUserAgent
+	if i.opts.UserAgent != "" {
+		restConfig.UserAgent = i.opts.UserAgent
+	}
+
+	i.restConfig = restConfig
+	return restConfig, nil
+}
+
+func (i *defaultInitializer) loadRestConfig() (*rest.Config, error) {
+	if i.opts.Kubeconfig == "" && i.opts.Context == "" && os.Getenv(clientcmd.RecommendedConfigPathEnvVar) == "" {
+		restConfig, err := inClusterConfig()
+		if err == nil {
+			return restConfig, nil
+		}
+		if !errors.Is(err, rest.ErrNotInCluster) {
+			return nil, fmt.Errorf("failed to load in-cluster config: %w", err)
+		}
+	}
+
+	loadingRules := clientcmd.NewDefaultClientConfigLoadingRules()
+	loadingRules.ExplicitPath = i.opts.Kubeconfig
+	overrides := &clientcmd.ConfigOverrides{CurrentContext: i.opts.Context}
+
+	restConfig, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loadingRules, overrides).ClientConfig()
+	if err != nil {
+		if clientcmd.IsEmptyConfig(err) {
+			return nil, fmt.Errorf("failed to load kubeconfig: not running inside a cluster and no kubeconfig found: %w", err)
+		}
+		return nil, fmt.Errorf("failed to load kubeconfig: %w", err)
+	}
+
+	return restConfig, nil
+}
+
+// CreateClientSet creates a client set from the kubernetes rest config loaded by RestConfig.
 func (i *defaultInitializer) CreateClientSet() (kubernetes.Interface, error) {
-	restConfig := ctrl.GetConfigOrDie()
-	clientSet, err := kubernetes.NewForConfig(restConfig)
+	restConfig, err := i.RestConfig()
 	if err != nil {
 		return nil, err
 	}
 
+	clientSet, err := kubernetes.NewForConfig(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client set: %w", err)
+	}
+
 	return clientSet, nil
 }
+
+// CreateClient creates a controller-runtime client from the kubernetes rest config loaded by RestConfig, so that it
+// shares the client settings with the client set.
+func (i *defaultInitializer) CreateClient() (client.Client, error) {
+	restConfig, err := i.RestConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	clientScheme := i.opts.Scheme
+	if clientScheme == nil {
+		clientScheme = scheme.Scheme
+	}
+
+	k8sClient, err := newClient(restConfig, client.Options{Scheme: clientScheme})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create controller-runtime client: %w", err)
+	}
+
+	return k8sClient, nil
+}
@@ -2,21 +2,29 @@ package initializer
 
 import (
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
 	"k8s.io/client-go/rest"
 	"k8s.io/client-go/tools/clientcmd/api"
-	ctrl "sigs.k8s.io/controller-runtime"
-	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
 func Test_initializer_GetNamespace(t *testing.T) {
+	originalNamespaceFile := serviceAccountNamespaceFile
+	defer func() { serviceAccountNamespaceFile = originalNamespaceFile }()
+	serviceAccountNamespaceFile = filepath.Join(t.TempDir(), "namespace")
+
 	t.Run("should return default namespace if not present", func(t *testing.T) {
 		// given
-		sut := New()
+		sut := New(Options{})
 		prevValue, present := os.LookupEnv(namespaceEnvName)
 		defer resetEnv(t, namespaceEnvName, prevValue, present)
 		err := os.Unsetenv("NAMESPACE")
@@ -30,7 +38,7 @@ func Test_initializer_GetNamespace(t *testing.T) {
 	})
 	t.Run("should return default namespace if empty string", func(t *testing.T) {
 		// given
-		sut := New()
+		sut := New(Options{})
 		prevValue, present := os.LookupEnv(namespaceEnvName)
 		defer resetEnv(t, namespaceEnvName, prevValue, present)
 		err := os.Setenv("NAMESPACE", "")
@@ -44,7 +52,7 @@ func Test_initializer_GetNamespace(t *testing.T) {
 	})
 	t.Run("should return namespace from env", func(t *testing.T) {
 		// given
-		sut := New()
+		sut := New(Options{})
 		prevValue, present := os.LookupEnv(namespaceEnvName)
 		defer resetEnv(t, namespaceEnvName, prevValue, present)
 		err := os.Setenv("NAMESPACE", "quark")
@@ -56,6 +64,30 @@ func Test_initializer_GetNamespace(t *testing.T) {
 		// then
 		assert.Equal(t, "quark", actual)
 	})
+	t.Run("should prefer namespace from options", func(t *testing.T) {
+		// given
+		t.Setenv(namespaceEnvName, "quark")
+		sut := New(Options{Namespace: "ecosystem"})
+
+		// when
+		actual := sut.GetNamespace()
+
+		// then
+		assert.Equal(t, "ecosystem", actual)
+	})
+	t.Run("should return namespace of service account", func(t *testing.T) {
+		// given
+		t.Setenv(namespaceEnvName, "")
+		require.NoError(t, os.WriteFile(serviceAccountNamespaceFile, []byte("ecosystem\n"), 0600))
+		defer func() { _ = os.Remove(serviceAccountNamespaceFile) }()
+		sut := New(Options{})
+
+		// when
+		actual := sut.GetNamespace()
+
+		// then
+		assert.Equal(t, "ecosystem", actual)
+	})
 }
 
 func resetEnv(t *testing.T, name, value string, present bool) {
@@ -69,15 +101,142 @@ func resetEnv(t *testing.T, name, value string, present bool) {
 	require.NoError(t, err)
 }
 
+func Test_initializer_RestConfig(t *testing.T) {
+	t.Run("should prefer in-cluster config", func(t *testing.T) {
+		// given
+		setInClusterConfig(t, &rest.Config{Host: "https://kubernetes.default.svc"}, nil)
+		sut := &defaultInitializer{}
+
+		// when
+		actual, err := sut.RestConfig()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "https://kubernetes.default.svc", actual.Host)
+		assert.Equal(t, defaultUserAgent, actual.UserAgent)
+	})
+	t.Run("should prefer kubeconfig from environment over in-cluster config", func(t *testing.T) {
+		// given
+		setInClusterConfig(t, &rest.Config{Host: "https://kubernetes.default.svc"}, nil)
+		t.Setenv("KUBECONFIG", writeKubeconfig(t))
+		sut := &defaultInitializer{}
+
+		// when
+		actual, err := sut.RestConfig()
+
+		// then
+		require.NoError(t, err)
+		assert.NotEqual(t, "https://kubernetes.default.svc", actual.Host)
+	})
+	t.Run("should fail on broken in-cluster config", func(t *testing.T) {
+		// given
+		setInClusterConfig(t, nil, assert.AnError)
+		sut := &defaultInitializer{}
+
+		// when
+		_, err := sut.RestConfig()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to load in-cluster config")
+	})
+	t.Run("should fall back to kubeconfig outside a cluster", func(t *testing.T) {
+		// given
+		setInClusterConfig(t, nil, rest.ErrNotInCluster)
+		t.Setenv("KUBECONFIG", writeKubeconfig(t))
+		sut := &defaultInitializer{}
+
+		// when
+		actual, err := sut.RestConfig()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "https://production.example.com:6443", actual.Host)
+	})
+	t.Run("should fail without kubeconfig outside a cluster", func(t *testing.T) {
+		// given
+		setInClusterConfig(t, nil, rest.ErrNotInCluster)
+		t.Setenv("KUBECONFIG", filepath.Join(t.TempDir(), "missing"))
+		t.Setenv("HOME", t.TempDir())
+		sut := &defaultInitializer{}
+
+		// when
+		_, err := sut.RestConfig()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "not running inside a cluster and no kubeconfig found")
+	})
+	t.Run("should load explicit kubeconfig and context", func(t *testing.T) {
+		// given
+		setInClusterConfig(t, &rest.Config{Host: "https://kubernetes.default.svc"}, nil)
+		sut := &defaultInitializer{opts: Options{Kubeconfig: writeKubeconfig(t), Context: "staging"}}
+
+		// when
+		actual, err := sut.RestConfig()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "https://staging.example.com:6443", actual.Host)
+	})
+	t.Run("should fail on unknown context", func(t *testing.T) {
+		// given
+		sut := &defaultInitializer{opts: Options{Kubeconfig: writeKubeconfig(t), Context: "unknown"}}
+
+		// when
+		_, err := sut.RestConfig()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to load kubeconfig")
+	})
+	t.Run("should apply client settings", func(t *testing.T) {
+		// given
+		setInClusterConfig(t, &rest.Config{Host: "https://kubernetes.default.svc"}, nil)
+		sut := &defaultInitializer{opts: Options{QPS: 50, Burst: 100, Timeout: time.Minute, UserAgent: "host-change-test"}}
+
+		// when
+		actual, err := sut.RestConfig()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, float32(50), actual.QPS)
+		assert.Equal(t, 100, actual.Burst)
+		assert.Equal(t, time.Minute, actual.Timeout)
+		assert.Equal(t, "host-change-test", actual.UserAgent)
+	})
+}
+
+func Test_initializer_RestConfigOnce(t *testing.T) {
+	t.Run("should load the rest config only once", func(t *testing.T) {
+		// given
+		calls := 0
+		original := inClusterConfig
+		t.Cleanup(func() { inClusterConfig = original })
+		inClusterConfig = func() (*rest.Config, error) {
+			calls++
+			return &rest.Config{Host: "https://kubernetes.default.svc"}, nil
+		}
+		t.Setenv("KUBECONFIG", "")
+		sut := &defaultInitializer{}
+
+		// when
+		restConfig, err := sut.RestConfig()
+		require.NoError(t, err)
+		_, err = sut.CreateClientSet()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "https://kubernetes.default.svc", restConfig.Host)
+		assert.Equal(t, 1, calls)
+	})
+}
+
 func Test_initializer_CreateClientSet(t *testing.T) {
 	t.Run("success", func(t *testing.T) {
 		// given
-		defer func() {
-			ctrl.GetConfigOrDie = config.GetConfigOrDie
-		}()
-		ctrl.GetConfigOrDie = func() *rest.Config {
-			return &rest.Config{}
-		}
+		setInClusterConfig(t, &rest.Config{}, nil)
 		sut := defaultInitializer{}
 
 		// when
@@ -87,15 +246,9 @@ func Test_initializer_CreateClientSet(t *testing.T) {
 		require.NoError(t, err)
 		require.NotNil(t, clientSet)
 	})
-
 	t.Run("should return error on invalid config", func(t *testing.T) {
 		// given
-		defer func() {
-			ctrl.GetConfigOrDie = config.GetConfigOrDie
-		}()
-		ctrl.GetConfigOrDie = func() *rest.Config {
-			return &rest.Config{ExecProvider: &api.ExecConfig{}, AuthProvider: &api.AuthProviderConfig{}}
-		}
+		setInClusterConfig(t, &rest.Config{ExecProvider: &api.ExecConfig{}, AuthProvider: &api.AuthProviderConfig{}}, nil)
 		sut := defaultInitializer{}
 
 		// when
@@ -103,5 +256,123 @@ func Test_initializer_CreateClientSet(t *testing.T) {
 
 		// then
 		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to create client set")
+	})
+	t.Run("should return error on missing config", func(t *testing.T) {
+		// given
+		setInClusterConfig(t, nil, assert.AnError)
+		sut := defaultInitializer{}
+
+		// when
+		_, err := sut.CreateClientSet()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+	})
+}
+
+func Test_initializer_CreateClient(t *testing.T) {
+	t.Run("success", func(t *testing.T) {
+		// given
+		setInClusterConfig(t, &rest.Config{Host: "https://kubernetes.default.svc"}, nil)
+		sut := defaultInitializer{}
+
+		// when
+		k8sClient, err := sut.CreateClient()
+
+		// then
+		require.NoError(t, err)
+		require.NotNil(t, k8sClient)
+		assert.Same(t, scheme.Scheme, k8sClient.Scheme())
+	})
+	t.Run("should use scheme of options", func(t *testing.T) {
+		// given
+		setInClusterConfig(t, &rest.Config{Host: "https://kubernetes.default.svc"}, nil)
+		customScheme := runtime.NewScheme()
+		sut := defaultInitializer{opts: Options{Scheme: customScheme}}
+
+		// when
+		k8sClient, err := sut.CreateClient()
+
+		// then
+		require.NoError(t, err)
+		assert.Same(t, customScheme, k8sClient.Scheme())
+	})
+	t.Run("should share client settings with client set", func(t *testing.T) {
+		// given
+		setInClusterConfig(t, &rest.Config{Host: "https://kubernetes.default.svc"}, nil)
+		var clientConfig *rest.Config
+		original := newClient
+		t.Cleanup(func() { newClient = original })
+		newClient = func(config *rest.Config, options client.Options) (client.Client, error) {
+			clientConfig = config
+			return original(config, options)
+		}
+		sut := defaultInitializer{opts: Options{QPS: 50, Burst: 100, Timeout: time.Minute, UserAgent: "host-change-test"}}
+
+		// when
+		clientSet, err := sut.CreateClientSet()
+		require.NoError(t, err)
+		_, err = sut.CreateClient()
+
+		// then
+		require.NoError(t, err)
+		assert.Same(t, sut.restConfig, clientConfig)
+		assert.Equal(t, float32(50), clientConfig.QPS)
+		assert.Equal(t, 100, clientConfig.Burst)
+		assert.Equal(t, time.Minute, clientConfig.Timeout)
+		assert.Equal(t, "host-change-test", clientConfig.UserAgent)
+		restClient := clientSet.(*kubernetes.Clientset).CoreV1().RESTClient().(*rest.RESTClient)
+		assert.Equal(t, clientConfig.QPS, restClient.GetRateLimiter().QPS())
+		assert.Equal(t, clientConfig.Timeout, restClient.Client.Timeout)
+	})
+	t.Run("should return error on invalid config", func(t *testing.T) {
+		// given
+		setInClusterConfig(t, &rest.Config{ExecProvider: &api.ExecConfig{}, AuthProvider: &api.AuthProviderConfig{}}, nil)
+		sut := defaultInitializer{}
+
+		// when
+		_, err := sut.CreateClient()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to create controller-runtime client")
 	})
 }
+
+func setInClusterConfig(t *testing.T, restConfig *rest.Config, err error) {
+	t.Helper()
+	original := inClusterConfig
+	t.Cleanup(func() { inClusterConfig = original })
+	inClusterConfig = func() (*rest.Config, error) {
+		return restConfig, err
+	}
+	t.Setenv("KUBECONFIG", "")
+}
+
+func writeKubeconfig(t *testing.T) string {
+	t.Helper()
+	kubeconfig := `apiVersion: v1
+kind: Config
+clusters:
+- name: production
+  cluster:
+    server: https://production.example.com:6443
+- name: staging
+  cluster:
+    server: https://staging.example.com:6443
+contexts:
+- name: production
+  context:
+    cluster: production
+- name: staging
+  context:
+    cluster: staging
+current-context: production
+`
+	path := filepath.Join(t.TempDir(), "kubeconfig")
+	require.NoError(t, os.WriteFile(path, []byte(kubeconfig), 0600))
+
+	return path
+}
@@ -5,6 +5,8 @@ package initializer
 import (
 	mock "github.com/stretchr/testify/mock"
 	kubernetes "k8s.io/client-go/kubernetes"
+	rest "k8s.io/client-go/rest"
+	client "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 // MockInitializer is an autogenerated mock type for the Initializer type
@@ -20,6 +22,61 @@ func (_m *MockInitializer) EXPECT() *MockInitializer_Expecter {
 	return &MockInitializer_Expecter{mock: &_m.Mock}
 }
 
+// CreateClient provides a mock function with given fields:
+func (_m *MockInitializer) CreateClient() (client.Client, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for CreateClient")
+	}
+
+	var r0 client.Client
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (client.Client, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() client.Client); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(client.Client)
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockInitializer_CreateClient_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'CreateClient'
+type MockInitializer_CreateClient_Call struct {
+	*mock.Call
+}
+
+// CreateClient is a helper method to define mock.On call
+func (_e *MockInitializer_Expecter) CreateClient() *MockInitializer_CreateClient_Call {
+	return &MockInitializer_CreateClient_Call{Call: _e.mock.On("CreateClient")}
+}
+
+func (_c *MockInitializer_CreateClient_Call) Run(run func()) *MockInitializer_CreateClient_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockInitializer_CreateClient_Call) Return(_a0 client.Client, _a1 error) *MockInitializer_CreateClient_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockInitializer_CreateClient_Call) RunAndReturn(run func() (client.Client, error)) *MockInitializer_CreateClient_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // CreateClientSet provides a mock function with given fields:
 func (_m *MockInitializer) CreateClientSet() (kubernetes.Interface, error) {
 	ret := _m.Called()
@@ -36,9 +93,7 @@ func (_m *MockInitializer) CreateClientSet() (kubernetes.Interface, error) {
 	if rf, ok := ret.Get(0).(func() kubernetes.Interface); ok {
 		r0 = rf()
 	} else {
-		if ret.Get(0) != nil {
-			r0 = ret.Get(0).(kubernetes.Interface)
-		}
+		r0 = ret.Get(0).(kubernetes.Interface)
 	}
 
 	if rf, ok := ret.Get(1).(func() error); ok {
@@ -122,6 +177,63 @@ func (_c *MockInitializer_GetNamespace_Call) RunAndReturn(run func() string) *Mo
 	return _c
 }
 
+// RestConfig provides a mock function with given fields:
+func (_m *MockInitializer) RestConfig() (*rest.Config, error) {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for RestConfig")
+	}
+
+	var r0 *rest.Config
+	var r1 error
+	if rf, ok := ret.Get(0).(func() (*rest.Config, error)); ok {
+		return rf()
+	}
+	if rf, ok := ret.Get(0).(func() *rest.Config); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*rest.Config)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func() error); ok {
+		r1 = rf()
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MockInitializer_RestConfig_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'RestConfig'
+type MockInitializer_RestConfig_Call struct {
+	*mock.Call
+}
+
+// RestConfig is a helper method to define mock.On call
+func (_e *MockInitializer_Expecter) RestConfig() *MockInitializer_RestConfig_Call {
+	return &MockInitializer_RestConfig_Call{Call: _e.mock.On("RestConfig")}
+}
+
+func (_c *MockInitializer_RestConfig_Call) Run(run func()) *MockInitializer_RestConfig_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run()
+	})
+	return _c
+}
+
+func (_c *MockInitializer_RestConfig_Call) Return(_a0 *rest.Config, _a1 error) *MockInitializer_RestConfig_Call {
+	_c.Call.Return(_a0, _a1)
+	return _c
+}
+
+func (_c *MockInitializer_RestConfig_Call) RunAndReturn(run func() (*rest.Config, error)) *MockInitializer_RestConfig_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewMockInitializer creates a new instance of MockInitializer. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewMockInitializer(t interface {
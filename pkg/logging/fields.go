@@ -0,0 +1,43 @@
+package logging
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+// Keys of the fields which are attached to the log entries of a host change.
+const (
+	NamespaceKey  = "namespace"
+	DeploymentKey = "deployment"
+	RunIDKey      = "runID"
+	PhaseKey      = "phase"
+)
+
+// Phases of a host change.
+const (
+	PhaseGenerate    = "generate"
+	PhasePlan        = "plan"
+	PhaseMaintenance = "maintenance"
+	PhaseCanary      = "canary"
+	PhaseUpdate      = "update"
+	PhaseRollout     = "rollout"
+	PhaseRollback    = "rollback"
+	PhaseVerify      = "verify"
+	PhaseReport      = "report"
+)
+
+// WithRun returns a context whose logger carries the namespace and the id of the host change run.
+func WithRun(ctx context.Context, namespace string, runID string) context.Context {
+	return log.IntoContext(ctx, log.FromContext(ctx).WithValues(NamespaceKey, namespace, RunIDKey, runID))
+}
+
+// WithPhase returns a context whose logger carries the given phase of the host change.
+func WithPhase(ctx context.Context, phase string) context.Context {
+	return log.IntoContext(ctx, log.FromContext(ctx).WithValues(PhaseKey, phase))
+}
+
+// WithDeployment returns a context whose logger carries the name of the given deployment.
+func WithDeployment(ctx context.Context, name string) context.Context {
+	return log.IntoContext(ctx, log.FromContext(ctx).WithValues(DeploymentKey, name))
+}
@@ -0,0 +1,29 @@
+package logging
+
+import (
+	"context"
+	"testing"
+
+	"github.com/go-logr/logr/funcr"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+)
+
+func TestWithRun(t *testing.T) {
+	t.Run("should attach run, phase and deployment fields", func(t *testing.T) {
+		// given
+		var actual string
+		logger := funcr.New(func(prefix, args string) { actual = args }, funcr.Options{})
+		ctx := log.IntoContext(context.TODO(), logger)
+
+		// when
+		ctx = WithDeployment(WithPhase(WithRun(ctx, "ecosystem", "1234"), PhaseUpdate), "cas")
+		log.FromContext(ctx).Info("Update deployment")
+
+		// then
+		assert.Contains(t, actual, `"namespace"="ecosystem"`)
+		assert.Contains(t, actual, `"runID"="1234"`)
+		assert.Contains(t, actual, `"phase"="update"`)
+		assert.Contains(t, actual, `"deployment"="cas"`)
+	})
+}
@@ -10,7 +10,17 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 )
 
-const logLevelEnvName = "LOG_LEVEL"
+const (
+	logLevelEnvName  = "LOG_LEVEL"
+	logFormatEnvName = "LOG_FORMAT"
+)
+
+const (
+	// FormatText logs human-readable lines with key=value fields.
+	FormatText = "text"
+	// FormatJSON logs one JSON object per line with the fields as keys.
+	FormatJSON = "json"
+)
 
 const (
 	errorLevel int = iota
@@ -76,31 +86,68 @@ func (l *libraryLogger) Errorf(format string, args ...interface{}) {
 	l.logf(errorLevel, format, args...)
 }
 
-func getLogLevelFromEnv() (logrus.Level, error) {
-	logLevel, found := os.LookupEnv(logLevelEnvName)
-	if !found {
+func getLogLevel(logLevel string) (logrus.Level, error) {
+	if logLevel == "" {
 		return logrus.ErrorLevel, nil
 	}
 
 	level, err := logrus.ParseLevel(logLevel)
 	if err != nil {
-		return logrus.ErrorLevel, fmt.Errorf("value of log environment variable [%s] is not a valid log level: %w", logLevelEnvName, err)
+		return logrus.ErrorLevel, fmt.Errorf("log level '%s' is not valid: %w", logLevel, err)
 	}
 
 	return level, nil
 }
 
-// ConfigureLogger sets the logrus logger as for all logging implementations from the controller-runtime.
-func ConfigureLogger() error {
-	level, err := getLogLevelFromEnv()
+func getLogFormat(logFormat string) (string, error) {
+	switch logFormat {
+	case "", FormatText:
+		return FormatText, nil
+	case FormatJSON:
+		return FormatJSON, nil
+	default:
+		return "", fmt.Errorf("log format '%s' is not valid: expected one of '%s' or '%s'", logFormat, FormatText, FormatJSON)
+	}
+}
+
+// Options configures the logger. Empty fields fall back to the defaults.
+type Options struct {
+	// Level is the log level, e.g. info or debug. Defaults to error.
+	Level string
+	// Format is either FormatText or FormatJSON. Defaults to FormatText.
+	Format string
+}
+
+// Validate checks that the level and the format of the options are valid.
+func (o Options) Validate() error {
+	_, err := getLogLevel(o.Level)
 	if err != nil {
 		return err
 	}
 
-	// create logrus logger that can be styled and formatted
-	logrusLog := logrus.New()
-	logrusLog.SetFormatter(&logrus.TextFormatter{})
-	logrusLog.SetLevel(level)
+	_, err = getLogFormat(o.Format)
+	return err
+}
+
+// ConfigureLogger sets the logrus logger as for all logging implementations from the controller-runtime. It reads the
+// log level and format from the environment variables LOG_LEVEL and LOG_FORMAT.
+func ConfigureLogger() error {
+	logLevel := os.Getenv(logLevelEnvName)
+	_, err := getLogLevel(logLevel)
+	if err != nil {
+		return fmt.Errorf("value of log environment variable [%s] is not a valid log level: %w", logLevelEnvName, err)
+	}
+
+	return Configure(Options{Level: logLevel, Format: os.Getenv(logFormatEnvName)})
+}
+
+// Configure works like ConfigureLogger but uses the given options instead of the environment. The command line reads
+// the environment through the settings package.
+func Configure(opts Options) error {
+	logrusLog, err := newLogrusLogger(opts)
+	if err != nil {
+		return err
+	}
 
 	// convert logrus logger to logr logger
 	logrusrLogger := logrusr.New(logrusLog)
@@ -110,3 +157,26 @@ func ConfigureLogger() error {
 
 	return nil
 }
+
+// newLogrusLogger creates a logrus logger that can be styled and formatted.
+func newLogrusLogger(opts Options) (*logrus.Logger, error) {
+	level, err := getLogLevel(opts.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	format, err := getLogFormat(opts.Format)
+	if err != nil {
+		return nil, err
+	}
+
+	logrusLog := logrus.New()
+	if format == FormatJSON {
+		logrusLog.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logrusLog.SetFormatter(&logrus.TextFormatter{})
+	}
+	logrusLog.SetLevel(level)
+
+	return logrusLog, nil
+}
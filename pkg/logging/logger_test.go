@@ -4,8 +4,10 @@ import (
 	"os"
 	"testing"
 
+	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 )
@@ -51,6 +53,101 @@ func TestConfigureLogger(t *testing.T) {
 	})
 }
 
+func TestConfigure(t *testing.T) {
+	originalControllerLogger := ctrl.Log
+	defer func() {
+		ctrl.Log = originalControllerLogger
+	}()
+
+	t.Run("create logger with given log level", func(t *testing.T) {
+		// given
+		t.Setenv(logLevelEnvName, "TEST_LEVEL")
+
+		// when
+		err := Configure(Options{Level: "debug"})
+
+		// then
+		assert.NoError(t, err)
+	})
+
+	t.Run("create logger with invalid log level", func(t *testing.T) {
+		// given
+		t.Setenv(logLevelEnvName, "INFO")
+
+		// when
+		err := Configure(Options{Level: "TEST_LEVEL"})
+
+		// then
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "log level 'TEST_LEVEL' is not valid")
+	})
+}
+
+func TestOptions_Validate(t *testing.T) {
+	t.Run("should accept empty options", func(t *testing.T) {
+		assert.NoError(t, Options{}.Validate())
+	})
+	t.Run("should fail on invalid level", func(t *testing.T) {
+		err := Options{Level: "loud"}.Validate()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "log level 'loud' is not valid")
+	})
+	t.Run("should fail on invalid format", func(t *testing.T) {
+		err := Options{Level: "info", Format: "xml"}.Validate()
+
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "log format 'xml' is not valid")
+	})
+}
+
+func Test_newLogrusLogger(t *testing.T) {
+	t.Run("should use text format by default", func(t *testing.T) {
+		// given
+		t.Setenv(logFormatEnvName, "")
+
+		// when
+		logger, err := newLogrusLogger(Options{Level: "info"})
+
+		// then
+		require.NoError(t, err)
+		assert.IsType(t, &logrus.TextFormatter{}, logger.Formatter)
+		assert.Equal(t, logrus.InfoLevel, logger.Level)
+	})
+	t.Run("should use json format from options", func(t *testing.T) {
+		// given
+		t.Setenv(logFormatEnvName, FormatText)
+
+		// when
+		logger, err := newLogrusLogger(Options{Level: "info", Format: FormatJSON})
+
+		// then
+		require.NoError(t, err)
+		assert.IsType(t, &logrus.JSONFormatter{}, logger.Formatter)
+	})
+	t.Run("should ignore the environment", func(t *testing.T) {
+		// given
+		t.Setenv(logLevelEnvName, "debug")
+		t.Setenv(logFormatEnvName, FormatJSON)
+
+		// when
+		logger, err := newLogrusLogger(Options{})
+
+		// then
+		require.NoError(t, err)
+		assert.IsType(t, &logrus.TextFormatter{}, logger.Formatter)
+		assert.Equal(t, logrus.ErrorLevel, logger.Level)
+	})
+	t.Run("should fail on invalid format", func(t *testing.T) {
+		// when
+		_, err := newLogrusLogger(Options{Level: "info", Format: "xml"})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "log format 'xml' is not valid")
+	})
+}
+
 func Test_libraryLogger_Debug(t *testing.T) {
 	// given
 	loggerSink := newMockLogSink(t)
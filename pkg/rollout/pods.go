@@ -0,0 +1,99 @@
+package rollout
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/k8s-host-change/pkg/deployment"
+	"github.com/cloudogu/k8s-host-change/pkg/logging"
+)
+
+// StalePod is a running pod of a deployment which does not carry the expected host aliases, e.g. a pod of an old
+// replica set which was not replaced yet.
+type StalePod struct {
+	// Deployment is the name of the deployment the pod belongs to.
+	Deployment string `json:"deployment"`
+	Name       string `json:"name"`
+	// HostAliases are the host aliases the pod carries.
+	HostAliases []corev1.HostAlias `json:"hostAliases"`
+}
+
+type podVerifier struct {
+	clientSet kubernetes.Interface
+	timeout   time.Duration
+	interval  time.Duration
+}
+
+// NewPodVerifier creates a new instance of a podVerifier which checks that the running pods of deployments carry the
+// expected host aliases. A timeout greater than zero waits for stale pods to be replaced.
+func NewPodVerifier(clientSet kubernetes.Interface, timeout time.Duration) *podVerifier {
+	return &podVerifier{
+		clientSet: clientSet,
+		timeout:   timeout,
+		interval:  defaultPollInterval,
+	}
+}
+
+// FindStalePods returns the running pods of the given deployments which do not carry the given host aliases. Terminating
+// pods are not stale because they are already being replaced.
+// If the verifier has a timeout, it waits until no stale pods are left or the timeout expired and returns the pods
+// which are still stale. The order of the host aliases is not considered.
+func (v *podVerifier) FindStalePods(ctx context.Context, namespace string, deployments []appsv1.Deployment, hostAliases []corev1.HostAlias) ([]StalePod, error) {
+	stalePods, err := v.findStalePods(ctx, namespace, deployments, hostAliases)
+	if err != nil || len(stalePods) == 0 || v.timeout <= 0 {
+		return stalePods, err
+	}
+
+	logger := log.FromContext(ctx)
+	logger.Info("Wait for stale pods to be replaced", "pods", len(stalePods), "timeout", v.timeout)
+	waitCtx, cancel := context.WithTimeout(ctx, v.timeout)
+	defer cancel()
+
+	err = wait.PollUntilContextCancel(waitCtx, v.interval, false, func(ctx context.Context) (bool, error) {
+		stalePods, err = v.findStalePods(ctx, namespace, deployments, hostAliases)
+		return len(stalePods) == 0, err
+	})
+	if err != nil && !wait.Interrupted(err) {
+		return stalePods, err
+	}
+
+	return stalePods, nil
+}
+
+func (v *podVerifier) findStalePods(ctx context.Context, namespace string, deployments []appsv1.Deployment, hostAliases []corev1.HostAlias) ([]StalePod, error) {
+	var stalePods []StalePod
+	for _, deploy := range deployments {
+		if deploy.Spec.Selector == nil {
+			continue
+		}
+
+		selector, err := metav1.LabelSelectorAsSelector(deploy.Spec.Selector)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse pod selector of deployment '%s': %w", deploy.Name, err)
+		}
+
+		pods, err := v.clientSet.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector.String()})
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pods of deployment '%s': %w", deploy.Name, err)
+		}
+
+		for _, pod := range pods.Items {
+			if pod.Status.Phase != corev1.PodRunning || pod.DeletionTimestamp != nil || deployment.SameHostAliases(pod.Spec.HostAliases, hostAliases) {
+				continue
+			}
+
+			log.FromContext(ctx).Info("Pod does not carry the expected host aliases", logging.DeploymentKey, deploy.Name, "pod", pod.Name)
+			stalePods = append(stalePods, StalePod{Deployment: deploy.Name, Name: pod.Name, HostAliases: pod.Spec.HostAliases})
+		}
+	}
+
+	return stalePods, nil
+}
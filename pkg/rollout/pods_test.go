@@ -0,0 +1,142 @@
+package rollout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+var (
+	newHostAliases = []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}}}
+	oldHostAliases = []corev1.HostAlias{{IP: "10.0.0.9", Hostnames: []string{"ces.example.com"}}}
+)
+
+func TestNewPodVerifier(t *testing.T) {
+	// given
+	clientSet := fake.NewSimpleClientset()
+
+	// when
+	sut := NewPodVerifier(clientSet, time.Minute)
+
+	// then
+	require.NotNil(t, sut)
+	assert.Equal(t, clientSet, sut.clientSet)
+	assert.Equal(t, time.Minute, sut.timeout)
+	assert.Equal(t, defaultPollInterval, sut.interval)
+}
+
+func Test_podVerifier_FindStalePods(t *testing.T) {
+	deployments := []appsv1.Deployment{doguDeployment("cas"), doguDeployment("ldap")}
+
+	t.Run("should return running pods with other host aliases", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(
+			pod("cas-new", "cas", corev1.PodRunning, newHostAliases),
+			pod("cas-old", "cas", corev1.PodRunning, oldHostAliases),
+			pod("cas-failed", "cas", corev1.PodFailed, oldHostAliases),
+			pod("ldap-new", "ldap", corev1.PodRunning, newHostAliases),
+			pod("postfix-old", "postfix", corev1.PodRunning, oldHostAliases),
+		)
+		sut := &podVerifier{clientSet: clientSet, interval: time.Millisecond}
+
+		// when
+		actual, err := sut.FindStalePods(context.TODO(), testNamespace, deployments, newHostAliases)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, []StalePod{{Deployment: "cas", Name: "cas-old", HostAliases: oldHostAliases}}, actual)
+	})
+	t.Run("should ignore terminating pods", func(t *testing.T) {
+		// given
+		terminating := pod("cas-old", "cas", corev1.PodRunning, oldHostAliases)
+		terminating.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+		terminating.Finalizers = []string{"kubernetes"}
+		clientSet := fake.NewSimpleClientset(terminating, pod("cas-new", "cas", corev1.PodRunning, newHostAliases))
+		sut := &podVerifier{clientSet: clientSet, interval: time.Millisecond}
+
+		// when
+		actual, err := sut.FindStalePods(context.TODO(), testNamespace, deployments, newHostAliases)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+	})
+	t.Run("should wait until stale pods are replaced", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(pod("cas-old", "cas", corev1.PodRunning, oldHostAliases))
+		lists := 0
+		clientSet.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+			lists++
+			if lists == 3 {
+				err := clientSet.Tracker().Delete(corev1.SchemeGroupVersion.WithResource("pods"), testNamespace, "cas-old")
+				require.NoError(t, err)
+			}
+			return false, nil, nil
+		})
+		sut := &podVerifier{clientSet: clientSet, timeout: time.Second, interval: time.Millisecond}
+
+		// when
+		actual, err := sut.FindStalePods(context.TODO(), testNamespace, deployments[:1], newHostAliases)
+
+		// then
+		require.NoError(t, err)
+		assert.Empty(t, actual)
+		assert.Equal(t, 3, lists)
+	})
+	t.Run("should return pods which are still stale after the timeout", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(pod("cas-old", "cas", corev1.PodRunning, oldHostAliases))
+		sut := &podVerifier{clientSet: clientSet, timeout: 10 * time.Millisecond, interval: time.Millisecond}
+
+		// when
+		actual, err := sut.FindStalePods(context.TODO(), testNamespace, deployments[:1], newHostAliases)
+
+		// then
+		require.NoError(t, err)
+		require.Len(t, actual, 1)
+		assert.Equal(t, "cas-old", actual[0].Name)
+	})
+	t.Run("should fail to list pods", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		clientSet.PrependReactor("list", "pods", func(k8stesting.Action) (bool, runtime.Object, error) {
+			return true, nil, assert.AnError
+		})
+		sut := &podVerifier{clientSet: clientSet, interval: time.Millisecond}
+
+		// when
+		_, err := sut.FindStalePods(context.TODO(), testNamespace, deployments, newHostAliases)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, assert.AnError)
+		assert.ErrorContains(t, err, "failed to list pods of deployment 'cas'")
+	})
+}
+
+func doguDeployment(name string) appsv1.Deployment {
+	return appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace},
+		Spec: appsv1.DeploymentSpec{
+			Selector: &metav1.LabelSelector{MatchLabels: map[string]string{"dogu.name": name}},
+		},
+	}
+}
+
+func pod(name string, dogu string, phase corev1.PodPhase, aliases []corev1.HostAlias) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: testNamespace, Labels: map[string]string{"dogu.name": dogu}},
+		Spec:       corev1.PodSpec{HostAliases: aliases},
+		Status:     corev1.PodStatus{Phase: phase},
+	}
+}
@@ -0,0 +1,152 @@
+package rollout
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/client-go/kubernetes"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+
+	"github.com/cloudogu/k8s-host-change/pkg/logging"
+)
+
+const (
+	defaultPollInterval = 2 * time.Second
+	// progressDeadlineExceededReason is set by the deployment controller when a rollout stalls longer than
+	// the deployment's progressDeadlineSeconds.
+	progressDeadlineExceededReason = "ProgressDeadlineExceeded"
+)
+
+// DeploymentError is the error of the rollout of a single deployment.
+type DeploymentError struct {
+	// Deployment is the name of the failed deployment.
+	Deployment string
+	Err        error
+}
+
+// Error returns the message of the underlying error.
+func (e *DeploymentError) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *DeploymentError) Unwrap() error {
+	return e.Err
+}
+
+type waiter struct {
+	clientSet     kubernetes.Interface
+	timeout       time.Duration
+	globalTimeout time.Duration
+	interval      time.Duration
+}
+
+// NewWaiter creates a new instance of a waiter which is used to wait for deployment rollouts.
+// The timeout limits the wait for a single deployment, the globalTimeout limits the wait for all deployments.
+// A timeout of zero means no limit.
+func NewWaiter(clientSet kubernetes.Interface, timeout time.Duration, globalTimeout time.Duration) *waiter {
+	return &waiter{
+		clientSet:     clientSet,
+		timeout:       timeout,
+		globalTimeout: globalTimeout,
+		interval:      defaultPollInterval,
+	}
+}
+
+// WaitForRollout blocks until all given deployments are rolled out completely.
+// A rollout is complete if the deployment controller observed the current generation and all replicas are updated and
+// available. An error is returned if a rollout failed, i.e. exceeded its progress deadline, or did not finish in time.
+// The error of every failed deployment is a *DeploymentError.
+func (w *waiter) WaitForRollout(ctx context.Context, namespace string, deployments []appsv1.Deployment) error {
+	if w.globalTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.globalTimeout)
+		defer cancel()
+	}
+
+	var multiErr error
+	for _, deploy := range deployments {
+		err := w.waitForDeployment(ctx, namespace, deploy.Name)
+		if err != nil {
+			multiErr = multierror.Append(multiErr, &DeploymentError{Deployment: deploy.Name, Err: err})
+		}
+	}
+
+	return multiErr
+}
+
+func (w *waiter) waitForDeployment(ctx context.Context, namespace string, name string) error {
+	logger := log.FromContext(ctx).WithValues(logging.DeploymentKey, name)
+	logger.Info("Wait for rollout of deployment")
+
+	if w.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, w.timeout)
+		defer cancel()
+	}
+
+	var lastStatus string
+	err := wait.PollUntilContextCancel(ctx, w.interval, true, func(ctx context.Context) (bool, error) {
+		deployment, err := w.clientSet.AppsV1().Deployments(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("failed to get deployment '%s': %w", name, err)
+		}
+
+		var done bool
+		lastStatus, done, err = rolloutStatus(deployment)
+		return done, err
+	})
+	if err != nil {
+		if errors.Is(ctx.Err(), context.Canceled) {
+			return fmt.Errorf("wait for rollout of deployment '%s' interrupted: %w", name, ctx.Err())
+		}
+		if wait.Interrupted(err) {
+			return fmt.Errorf("rollout of deployment '%s' did not finish in time: %s", name, lastStatus)
+		}
+
+		return fmt.Errorf("rollout of deployment '%s' failed: %w", name, err)
+	}
+
+	logger.Info("Deployment successfully rolled out")
+	return nil
+}
+
+// rolloutStatus returns a message describing the rollout state, whether the rollout is finished, and an error if
+// the rollout failed permanently.
+func rolloutStatus(deployment *appsv1.Deployment) (string, bool, error) {
+	if deployment.Generation > deployment.Status.ObservedGeneration {
+		return "waiting for the deployment spec update to be observed", false, nil
+	}
+
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentProgressing && condition.Status == corev1.ConditionFalse &&
+			condition.Reason == progressDeadlineExceededReason {
+			return "", false, fmt.Errorf("progress deadline exceeded: %s", condition.Message)
+		}
+	}
+
+	replicas := int32(1)
+	if deployment.Spec.Replicas != nil {
+		replicas = *deployment.Spec.Replicas
+	}
+
+	status := deployment.Status
+	if status.UpdatedReplicas < replicas {
+		return fmt.Sprintf("%d of %d replicas updated", status.UpdatedReplicas, replicas), false, nil
+	}
+	if status.Replicas > status.UpdatedReplicas {
+		return fmt.Sprintf("%d old replicas pending termination", status.Replicas-status.UpdatedReplicas), false, nil
+	}
+	if status.AvailableReplicas < status.UpdatedReplicas {
+		return fmt.Sprintf("%d of %d updated replicas available", status.AvailableReplicas, status.UpdatedReplicas), false, nil
+	}
+
+	return "rolled out", true, nil
+}
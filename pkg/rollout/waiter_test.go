@@ -0,0 +1,204 @@
+package rollout
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	"k8s.io/utils/ptr"
+)
+
+const testNamespace = "ecosystem"
+
+func TestNewWaiter(t *testing.T) {
+	// given
+	clientSet := fake.NewSimpleClientset()
+
+	// when
+	sut := NewWaiter(clientSet, time.Minute, time.Hour)
+
+	// then
+	require.NotNil(t, sut)
+	assert.Equal(t, clientSet, sut.clientSet)
+	assert.Equal(t, time.Minute, sut.timeout)
+	assert.Equal(t, time.Hour, sut.globalTimeout)
+	assert.Equal(t, defaultPollInterval, sut.interval)
+}
+
+func Test_waiter_WaitForRollout(t *testing.T) {
+	t.Run("should succeed if all deployments are rolled out", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset(rolledOutDeployment("cas"), rolledOutDeployment("ldap"))
+		sut := &waiter{clientSet: clientSet, timeout: time.Second, interval: time.Millisecond}
+
+		// when
+		err := sut.WaitForRollout(context.TODO(), testNamespace, []appsv1.Deployment{
+			{ObjectMeta: metav1.ObjectMeta{Name: "cas"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "ldap"}},
+		})
+
+		// then
+		require.NoError(t, err)
+	})
+	t.Run("should fail if deployment does not exist", func(t *testing.T) {
+		// given
+		clientSet := fake.NewSimpleClientset()
+		sut := &waiter{clientSet: clientSet, timeout: time.Second, interval: time.Millisecond}
+
+		// when
+		err := sut.WaitForRollout(context.TODO(), testNamespace, []appsv1.Deployment{{ObjectMeta: metav1.ObjectMeta{Name: "cas"}}})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "rollout of deployment 'cas' failed: failed to get deployment 'cas'")
+		var deploymentErr *DeploymentError
+		require.ErrorAs(t, err, &deploymentErr)
+		assert.Equal(t, "cas", deploymentErr.Deployment)
+	})
+	t.Run("should fail if progress deadline is exceeded", func(t *testing.T) {
+		// given
+		deploy := rolledOutDeployment("cas")
+		deploy.Status.Conditions = []appsv1.DeploymentCondition{{
+			Type:    appsv1.DeploymentProgressing,
+			Status:  corev1.ConditionFalse,
+			Reason:  progressDeadlineExceededReason,
+			Message: "ReplicaSet has timed out progressing.",
+		}}
+		clientSet := fake.NewSimpleClientset(deploy)
+		sut := &waiter{clientSet: clientSet, timeout: time.Second, interval: time.Millisecond}
+
+		// when
+		err := sut.WaitForRollout(context.TODO(), testNamespace, []appsv1.Deployment{{ObjectMeta: metav1.ObjectMeta{Name: "cas"}}})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "rollout of deployment 'cas' failed: progress deadline exceeded: ReplicaSet has timed out progressing.")
+	})
+	t.Run("should fail if rollout does not finish within the deployment timeout", func(t *testing.T) {
+		// given
+		deploy := rolledOutDeployment("cas")
+		deploy.Status.AvailableReplicas = 0
+		clientSet := fake.NewSimpleClientset(deploy)
+		sut := &waiter{clientSet: clientSet, timeout: 10 * time.Millisecond, interval: time.Millisecond}
+
+		// when
+		err := sut.WaitForRollout(context.TODO(), testNamespace, []appsv1.Deployment{{ObjectMeta: metav1.ObjectMeta{Name: "cas"}}})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "rollout of deployment 'cas' did not finish in time: 0 of 1 updated replicas available")
+	})
+	t.Run("should fail if interrupted", func(t *testing.T) {
+		// given
+		ctx, cancel := context.WithCancel(context.TODO())
+		cancel()
+		deploy := rolledOutDeployment("cas")
+		deploy.Generation = 2
+		clientSet := fake.NewSimpleClientset(deploy)
+		sut := &waiter{clientSet: clientSet, timeout: time.Second, interval: time.Millisecond}
+
+		// when
+		err := sut.WaitForRollout(ctx, testNamespace, []appsv1.Deployment{{ObjectMeta: metav1.ObjectMeta{Name: "cas"}}})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorContains(t, err, "wait for rollout of deployment 'cas' interrupted")
+	})
+	t.Run("should fail for all deployments if global timeout is exceeded", func(t *testing.T) {
+		// given
+		cas := rolledOutDeployment("cas")
+		cas.Generation = 2
+		ldap := rolledOutDeployment("ldap")
+		ldap.Generation = 2
+		clientSet := fake.NewSimpleClientset(cas, ldap)
+		sut := &waiter{clientSet: clientSet, globalTimeout: 10 * time.Millisecond, interval: time.Millisecond}
+
+		// when
+		err := sut.WaitForRollout(context.TODO(), testNamespace, []appsv1.Deployment{
+			{ObjectMeta: metav1.ObjectMeta{Name: "cas"}},
+			{ObjectMeta: metav1.ObjectMeta{Name: "ldap"}},
+		})
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "2 errors occurred")
+		assert.ErrorContains(t, err, "rollout of deployment 'cas' did not finish in time")
+		assert.ErrorContains(t, err, "rollout of deployment 'ldap' did not finish in time")
+	})
+}
+
+func Test_rolloutStatus(t *testing.T) {
+	tests := []struct {
+		name        string
+		modify      func(deploy *appsv1.Deployment)
+		wantMessage string
+		wantDone    bool
+	}{
+		{
+			name:        "should be done",
+			modify:      func(deploy *appsv1.Deployment) {},
+			wantMessage: "rolled out",
+			wantDone:    true,
+		},
+		{
+			name:        "should wait for observed generation",
+			modify:      func(deploy *appsv1.Deployment) { deploy.Generation = 3 },
+			wantMessage: "waiting for the deployment spec update to be observed",
+		},
+		{
+			name:        "should wait for updated replicas",
+			modify:      func(deploy *appsv1.Deployment) { deploy.Status.UpdatedReplicas = 0 },
+			wantMessage: "0 of 1 replicas updated",
+		},
+		{
+			name:        "should wait for old replicas",
+			modify:      func(deploy *appsv1.Deployment) { deploy.Status.Replicas = 2 },
+			wantMessage: "1 old replicas pending termination",
+		},
+		{
+			name: "should default to one replica",
+			modify: func(deploy *appsv1.Deployment) {
+				deploy.Spec.Replicas = nil
+				deploy.Status.AvailableReplicas = 0
+			},
+			wantMessage: "0 of 1 updated replicas available",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			deploy := rolledOutDeployment("cas")
+			tt.modify(deploy)
+
+			message, done, err := rolloutStatus(deploy)
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantMessage, message)
+			assert.Equal(t, tt.wantDone, done)
+		})
+	}
+}
+
+func rolledOutDeployment(name string) *appsv1.Deployment {
+	return &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       name,
+			Namespace:  testNamespace,
+			Generation: 1,
+		},
+		Spec: appsv1.DeploymentSpec{Replicas: ptr.To(int32(1))},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 1,
+			Replicas:           1,
+			UpdatedReplicas:    1,
+			AvailableReplicas:  1,
+		},
+	}
+}
@@ -0,0 +1,248 @@
+package settings
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// ConfigAPIVersion is the only supported version of the configuration file.
+	ConfigAPIVersion = "k8s.cloudogu.com/v1"
+	// ConfigKind is the kind of the configuration file.
+	ConfigKind = "HostChangeConfiguration"
+)
+
+// configFile is the format of the configuration file. Fields which are not set keep their previous value.
+type configFile struct {
+	APIVersion      string              `json:"apiVersion"`
+	Kind            string              `json:"kind"`
+	Namespace       string              `json:"namespace,omitempty"`
+	LogLevel        string              `json:"logLevel,omitempty"`
+	LogFormat       string              `json:"logFormat,omitempty"`
+	Rollout         *rolloutConfig      `json:"rollout,omitempty"`
+	Canary          *canaryConfig       `json:"canary,omitempty"`
+	MaintenanceMode *bool               `json:"maintenanceMode,omitempty"`
+	Timeout         *metav1.Duration    `json:"timeout,omitempty"`
+	ShutdownTimeout *metav1.Duration    `json:"shutdownTimeout,omitempty"`
+	AliasRemoval    *aliasRemovalConfig `json:"aliasRemoval,omitempty"`
+	FailurePolicy   string              `json:"failurePolicy,omitempty"`
+	Report          *reportConfig       `json:"report,omitempty"`
+	History         *historyConfig      `json:"history,omitempty"`
+	Pods            *podsConfig         `json:"pods,omitempty"`
+	AliasSources    *aliasSourcesConfig `json:"aliasSources,omitempty"`
+	Webhook         *webhookConfig      `json:"webhook,omitempty"`
+	Backend         string              `json:"backend,omitempty"`
+	CoreDNS         *coreDNSConfig      `json:"coreDNS,omitempty"`
+}
+
+type coreDNSConfig struct {
+	Namespace string `json:"namespace,omitempty"`
+	ConfigMap string `json:"configMap,omitempty"`
+}
+
+type webhookConfig struct {
+	Port     *int   `json:"port,omitempty"`
+	CertDir  string `json:"certDir,omitempty"`
+	FailOpen *bool  `json:"failOpen,omitempty"`
+}
+
+type aliasSourcesConfig struct {
+	ConfigMap string            `json:"configMap,omitempty"`
+	File      string            `json:"file,omitempty"`
+	Hosts     map[string]string `json:"hosts,omitempty"`
+}
+
+type podsConfig struct {
+	Verify          *bool            `json:"verify,omitempty"`
+	Timeout         *metav1.Duration `json:"timeout,omitempty"`
+	VerifyHostsFile *bool            `json:"verifyHostsFile,omitempty"`
+}
+
+type historyConfig struct {
+	Retention   *int   `json:"retention,omitempty"`
+	TriggeredBy string `json:"triggeredBy,omitempty"`
+}
+
+type reportConfig struct {
+	File                   string `json:"file,omitempty"`
+	TerminationMessagePath string `json:"terminationMessagePath,omitempty"`
+}
+
+type rolloutConfig struct {
+	Wait          *bool            `json:"wait,omitempty"`
+	Timeout       *metav1.Duration `json:"timeout,omitempty"`
+	GlobalTimeout *metav1.Duration `json:"globalTimeout,omitempty"`
+	Staged        *bool            `json:"staged,omitempty"`
+}
+
+type canaryConfig struct {
+	Enabled *bool  `json:"enabled,omitempty"`
+	Dogu    string `json:"dogu,omitempty"`
+}
+
+type aliasRemovalConfig struct {
+	MaxShare  *float64 `json:"maxShare,omitempty"`
+	Confirmed *bool    `json:"confirmed,omitempty"`
+}
+
+// applyFile reads and validates the configuration file at the given path and applies its values.
+func (s *Settings) applyFile(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read configuration file '%s': %w", path, err)
+	}
+
+	file := &configFile{}
+	err = yaml.UnmarshalStrict(raw, file)
+	if err != nil {
+		return fmt.Errorf("failed to parse configuration file '%s': %w", path, err)
+	}
+
+	err = file.validate()
+	if err != nil {
+		return fmt.Errorf("invalid configuration file '%s': %w", path, err)
+	}
+
+	file.applyTo(s)
+	return nil
+}
+
+func (f *configFile) validate() error {
+	if f.APIVersion != ConfigAPIVersion {
+		return fmt.Errorf("unsupported apiVersion '%s': expected '%s'", f.APIVersion, ConfigAPIVersion)
+	}
+	if f.Kind != ConfigKind {
+		return fmt.Errorf("unsupported kind '%s': expected '%s'", f.Kind, ConfigKind)
+	}
+
+	durations := map[string]*metav1.Duration{
+		"timeout":         f.Timeout,
+		"shutdownTimeout": f.ShutdownTimeout,
+	}
+	if f.Rollout != nil {
+		durations["rollout.timeout"] = f.Rollout.Timeout
+		durations["rollout.globalTimeout"] = f.Rollout.GlobalTimeout
+	}
+	if f.Pods != nil {
+		durations["pods.timeout"] = f.Pods.Timeout
+	}
+	for name, duration := range durations {
+		if duration != nil && duration.Duration < 0 {
+			return fmt.Errorf("%s must not be negative", name)
+		}
+	}
+
+	if f.History != nil && f.History.Retention != nil && *f.History.Retention < 0 {
+		return fmt.Errorf("history.retention must not be negative")
+	}
+
+	if f.AliasRemoval != nil && f.AliasRemoval.MaxShare != nil {
+		share := *f.AliasRemoval.MaxShare
+		if share < 0 || share > 1 {
+			return fmt.Errorf("aliasRemoval.maxShare must be between 0 and 1")
+		}
+	}
+
+	if f.AliasSources != nil {
+		for host, ip := range f.AliasSources.Hosts {
+			if net.ParseIP(ip) == nil {
+				return fmt.Errorf("aliasSources.hosts: value '%s' of host '%s' is not a valid ip", ip, host)
+			}
+		}
+	}
+
+	if f.Webhook != nil && f.Webhook.Port != nil {
+		port := *f.Webhook.Port
+		if port < 1 || port > maxPort {
+			return fmt.Errorf("webhook.port must be between 1 and %d", maxPort)
+		}
+	}
+
+	return nil
+}
+
+func (f *configFile) applyTo(s *Settings) {
+	setString(&s.Namespace, f.Namespace)
+	setString(&s.LogLevel, f.LogLevel)
+	setString(&s.LogFormat, f.LogFormat)
+	setString(&s.FailurePolicy, f.FailurePolicy)
+	set(&s.MaintenanceMode, f.MaintenanceMode)
+	setDuration(&s.Timeout, f.Timeout)
+	setDuration(&s.ShutdownTimeout, f.ShutdownTimeout)
+
+	if f.Rollout != nil {
+		set(&s.Rollout.Wait, f.Rollout.Wait)
+		setDuration(&s.Rollout.Timeout, f.Rollout.Timeout)
+		setDuration(&s.Rollout.GlobalTimeout, f.Rollout.GlobalTimeout)
+		set(&s.Rollout.Staged, f.Rollout.Staged)
+	}
+
+	if f.Canary != nil {
+		set(&s.Canary.Enabled, f.Canary.Enabled)
+		setString(&s.Canary.Dogu, f.Canary.Dogu)
+	}
+
+	if f.AliasRemoval != nil {
+		set(&s.AliasRemoval.MaxShare, f.AliasRemoval.MaxShare)
+		set(&s.AliasRemoval.Confirmed, f.AliasRemoval.Confirmed)
+	}
+
+	if f.Report != nil {
+		setString(&s.Report.File, f.Report.File)
+		setString(&s.Report.TerminationMessagePath, f.Report.TerminationMessagePath)
+	}
+
+	if f.History != nil {
+		set(&s.History.Retention, f.History.Retention)
+		setString(&s.History.TriggeredBy, f.History.TriggeredBy)
+	}
+
+	if f.Pods != nil {
+		set(&s.Pods.Verify, f.Pods.Verify)
+		setDuration(&s.Pods.Timeout, f.Pods.Timeout)
+		set(&s.Pods.VerifyHostsFile, f.Pods.VerifyHostsFile)
+	}
+
+	if f.AliasSources != nil {
+		setString(&s.AliasSources.ConfigMap, f.AliasSources.ConfigMap)
+		setString(&s.AliasSources.File, f.AliasSources.File)
+		if len(f.AliasSources.Hosts) > 0 {
+			s.AliasSources.Hosts = f.AliasSources.Hosts
+		}
+	}
+
+	if f.Webhook != nil {
+		set(&s.Webhook.Port, f.Webhook.Port)
+		setString(&s.Webhook.CertDir, f.Webhook.CertDir)
+		set(&s.Webhook.FailOpen, f.Webhook.FailOpen)
+	}
+
+	setString(&s.Backend, f.Backend)
+	if f.CoreDNS != nil {
+		setString(&s.CoreDNS.Namespace, f.CoreDNS.Namespace)
+		setString(&s.CoreDNS.ConfigMap, f.CoreDNS.ConfigMap)
+	}
+}
+
+func set[T any](target *T, value *T) {
+	if value != nil {
+		*target = *value
+	}
+}
+
+func setString(target *string, value string) {
+	if value != "" {
+		*target = value
+	}
+}
+
+func setDuration(target *time.Duration, value *metav1.Duration) {
+	if value != nil {
+		*target = value.Duration
+	}
+}
@@ -0,0 +1,242 @@
+package settings
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const completeConfigFile = `apiVersion: k8s.cloudogu.com/v1
+kind: HostChangeConfiguration
+namespace: ecosystem
+logLevel: debug
+logFormat: json
+rollout:
+  wait: true
+  timeout: 2m
+  globalTimeout: 20m
+  staged: true
+canary:
+  enabled: true
+  dogu: nginx
+maintenanceMode: true
+timeout: 1h
+shutdownTimeout: 40s
+aliasRemoval:
+  maxShare: 0.25
+  confirmed: true
+failurePolicy: continue-on-error
+report:
+  file: /tmp/report.json
+  terminationMessagePath: /dev/termination-log
+history:
+  retention: 5
+  triggeredBy: admin
+pods:
+  verify: true
+  timeout: 3m
+  verifyHostsFile: true
+aliasSources:
+  configMap: additional-hosts
+  file: /etc/k8s-host-change/hosts
+  hosts:
+    git.local: 10.0.0.2
+webhook:
+  port: 8443
+  certDir: /certs
+  failOpen: true
+backend: coredns
+coreDNS:
+  namespace: dns
+  configMap: coredns-import
+`
+
+func TestLoad(t *testing.T) {
+	clearEnv(t)
+
+	t.Run("should read all settings from file", func(t *testing.T) {
+		// given
+		path := writeConfigFile(t, completeConfigFile)
+
+		// when
+		actual, err := Load(path)
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, &Settings{
+			Namespace: "ecosystem",
+			LogLevel:  "debug",
+			LogFormat: "json",
+			Rollout: Rollout{
+				Wait:          true,
+				Timeout:       2 * time.Minute,
+				GlobalTimeout: 20 * time.Minute,
+				Staged:        true,
+			},
+			Canary:          Canary{Enabled: true, Dogu: "nginx"},
+			MaintenanceMode: true,
+			Timeout:         time.Hour,
+			ShutdownTimeout: 40 * time.Second,
+			AliasRemoval:    AliasRemoval{MaxShare: 0.25, Confirmed: true},
+			FailurePolicy:   "continue-on-error",
+			Report:          Report{File: "/tmp/report.json", TerminationMessagePath: "/dev/termination-log"},
+			History:         History{Retention: 5, TriggeredBy: "admin"},
+			Pods:            Pods{Verify: true, Timeout: 3 * time.Minute, VerifyHostsFile: true},
+			AliasSources: AliasSources{
+				ConfigMap: "additional-hosts",
+				File:      "/etc/k8s-host-change/hosts",
+				Hosts:     map[string]string{"git.local": "10.0.0.2"},
+			},
+			Webhook: Webhook{Port: 8443, CertDir: "/certs", FailOpen: true},
+			Backend: "coredns",
+			CoreDNS: CoreDNS{Namespace: "dns", ConfigMap: "coredns-import"},
+		}, actual)
+	})
+	t.Run("should keep defaults for missing settings", func(t *testing.T) {
+		// given
+		path := writeConfigFile(t, "apiVersion: k8s.cloudogu.com/v1\nkind: HostChangeConfiguration\nrollout:\n  wait: true\n")
+
+		// when
+		actual, err := Load(path)
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, actual.Rollout.Wait)
+		assert.Equal(t, defaultRolloutTimeout, actual.Rollout.Timeout)
+		assert.Equal(t, defaultShutdownTimeout, actual.ShutdownTimeout)
+		assert.Equal(t, defaultMaxAliasRemovalShare, actual.AliasRemoval.MaxShare)
+	})
+	t.Run("should override file with environment", func(t *testing.T) {
+		// given
+		path := writeConfigFile(t, completeConfigFile)
+		t.Setenv(waitForRolloutEnvName, "false")
+		t.Setenv(namespaceEnvName, "ces")
+		t.Setenv(failurePolicyEnvName, "fail-fast")
+
+		// when
+		actual, err := Load(path)
+
+		// then
+		require.NoError(t, err)
+		assert.False(t, actual.Rollout.Wait)
+		assert.Equal(t, "ces", actual.Namespace)
+		assert.Equal(t, "fail-fast", actual.FailurePolicy)
+		assert.Equal(t, 2*time.Minute, actual.Rollout.Timeout)
+	})
+	t.Run("should fail on missing file", func(t *testing.T) {
+		// when
+		_, err := Load(filepath.Join(t.TempDir(), "missing.yaml"))
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to read configuration file")
+	})
+	t.Run("should fail on unknown field", func(t *testing.T) {
+		// given
+		path := writeConfigFile(t, "apiVersion: k8s.cloudogu.com/v1\nkind: HostChangeConfiguration\nconcurrency: 3\n")
+
+		// when
+		_, err := Load(path)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to parse configuration file")
+		assert.ErrorContains(t, err, "concurrency")
+	})
+	t.Run("should fail on invalid duration", func(t *testing.T) {
+		// given
+		path := writeConfigFile(t, "apiVersion: k8s.cloudogu.com/v1\nkind: HostChangeConfiguration\ntimeout: soon\n")
+
+		// when
+		_, err := Load(path)
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "failed to parse configuration file")
+	})
+}
+
+func Test_configFile_validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{
+			name:    "should fail on unsupported version",
+			content: "apiVersion: k8s.cloudogu.com/v2\nkind: HostChangeConfiguration\n",
+			wantErr: "unsupported apiVersion 'k8s.cloudogu.com/v2': expected 'k8s.cloudogu.com/v1'",
+		},
+		{
+			name:    "should fail on missing version",
+			content: "kind: HostChangeConfiguration\n",
+			wantErr: "unsupported apiVersion ''",
+		},
+		{
+			name:    "should fail on unsupported kind",
+			content: "apiVersion: k8s.cloudogu.com/v1\nkind: Config\n",
+			wantErr: "unsupported kind 'Config': expected 'HostChangeConfiguration'",
+		},
+		{
+			name:    "should fail on negative duration",
+			content: "apiVersion: k8s.cloudogu.com/v1\nkind: HostChangeConfiguration\nrollout:\n  globalTimeout: -1m\n",
+			wantErr: "rollout.globalTimeout must not be negative",
+		},
+		{
+			name:    "should fail on share out of range",
+			content: "apiVersion: k8s.cloudogu.com/v1\nkind: HostChangeConfiguration\naliasRemoval:\n  maxShare: 2\n",
+			wantErr: "aliasRemoval.maxShare must be between 0 and 1",
+		},
+		{
+			name:    "should fail on invalid host ip",
+			content: "apiVersion: k8s.cloudogu.com/v1\nkind: HostChangeConfiguration\naliasSources:\n  hosts:\n    git.local: git\n",
+			wantErr: "aliasSources.hosts: value 'git' of host 'git.local' is not a valid ip",
+		},
+		{
+			name:    "should fail on invalid webhook port",
+			content: "apiVersion: k8s.cloudogu.com/v1\nkind: HostChangeConfiguration\nwebhook:\n  port: 70000\n",
+			wantErr: "webhook.port must be between 1 and 65535",
+		},
+		{
+			name:    "should fail on webhook port 0",
+			content: "apiVersion: k8s.cloudogu.com/v1\nkind: HostChangeConfiguration\nwebhook:\n  port: 0\n",
+			wantErr: "webhook.port must be between 1 and 65535",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := writeConfigFile(t, tt.content)
+
+			_, err := Load(path)
+
+			require.Error(t, err)
+			assert.ErrorContains(t, err, "invalid configuration file '"+path+"'")
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte(content), 0600))
+
+	return path
+}
+
+func clearEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{waitForRolloutEnvName, rolloutTimeoutEnvName, rolloutGlobalTimeoutEnvName,
+		stagedRestartEnvName, canaryEnvName, canaryDoguEnvName, maintenanceModeEnvName, timeoutEnvName,
+		shutdownTimeoutEnvName, maxAliasRemovalShareEnvName, confirmAliasRemovalEnvName, failurePolicyEnvName,
+		namespaceEnvName, logLevelEnvName, logFormatEnvName, reportFileEnvName, terminationMessageEnvName,
+		historyRetentionEnvName, triggeredByEnvName, verifyPodsEnvName, podTimeoutEnvName,
+		verifyHostsFileEnvName, aliasConfigMapEnvName, aliasFileEnvName, additionalHostsEnvName, webhookPortEnvName,
+		webhookCertDirEnvName, webhookFailOpenEnvName, backendEnvName, coreDNSNamespaceEnvName, coreDNSConfigMapEnvName} {
+		t.Setenv(name, "")
+	}
+}
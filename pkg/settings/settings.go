@@ -0,0 +1,451 @@
+package settings
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/cloudogu/k8s-host-change/pkg/logging"
+)
+
+// ConfigFileEnvName is the environment variable containing the path of the configuration file.
+const ConfigFileEnvName = "CONFIG_FILE"
+
+const (
+	waitForRolloutEnvName       = "WAIT_FOR_ROLLOUT"
+	rolloutTimeoutEnvName       = "ROLLOUT_TIMEOUT"
+	rolloutGlobalTimeoutEnvName = "ROLLOUT_GLOBAL_TIMEOUT"
+	stagedRestartEnvName        = "STAGED_RESTART"
+	canaryEnvName               = "CANARY"
+	canaryDoguEnvName           = "CANARY_DOGU"
+	maintenanceModeEnvName      = "MAINTENANCE_MODE"
+	timeoutEnvName              = "TIMEOUT"
+	shutdownTimeoutEnvName      = "SHUTDOWN_TIMEOUT"
+	maxAliasRemovalShareEnvName = "MAX_ALIAS_REMOVAL_SHARE"
+	confirmAliasRemovalEnvName  = "CONFIRM_ALIAS_REMOVAL"
+	failurePolicyEnvName        = "FAILURE_POLICY"
+	namespaceEnvName            = "NAMESPACE"
+	logLevelEnvName             = "LOG_LEVEL"
+	logFormatEnvName            = "LOG_FORMAT"
+	reportFileEnvName           = "REPORT_FILE"
+	terminationMessageEnvName   = "TERMINATION_MESSAGE_PATH"
+	historyRetentionEnvName     = "HISTORY_RETENTION"
+	triggeredByEnvName          = "TRIGGERED_BY"
+	verifyPodsEnvName           = "VERIFY_PODS"
+	podTimeoutEnvName           = "POD_VERIFICATION_TIMEOUT"
+	verifyHostsFileEnvName      = "VERIFY_HOSTS_FILE"
+	aliasConfigMapEnvName       = "ALIAS_CONFIG_MAP"
+	aliasFileEnvName            = "ALIAS_FILE"
+	additionalHostsEnvName      = "ADDITIONAL_HOSTS"
+	webhookPortEnvName          = "WEBHOOK_PORT"
+	webhookCertDirEnvName       = "WEBHOOK_CERT_DIR"
+	webhookFailOpenEnvName      = "WEBHOOK_FAIL_OPEN"
+	backendEnvName              = "BACKEND"
+	coreDNSNamespaceEnvName     = "COREDNS_NAMESPACE"
+	coreDNSConfigMapEnvName     = "COREDNS_CONFIG_MAP"
+)
+
+const (
+	defaultRolloutTimeout       = 5 * time.Minute
+	defaultRolloutGlobalTimeout = 30 * time.Minute
+	defaultShutdownTimeout      = 25 * time.Second
+	defaultMaxAliasRemovalShare = 0.5
+	defaultHistoryRetention     = 20
+	defaultWebhookPort          = 9443
+	maxPort                     = 65535
+)
+
+// Settings contains the optional behaviour of a host change run.
+type Settings struct {
+	// Namespace is the namespace of the ecosystem. Empty means the namespace is detected.
+	Namespace string
+	// LogLevel is the log level. Empty means the default log level.
+	LogLevel string
+	// LogFormat is either text or json. Empty means text.
+	LogFormat string
+	Rollout   Rollout
+	Canary    Canary
+	// MaintenanceMode enables the maintenance mode of the ecosystem while the dogus are updated.
+	MaintenanceMode bool
+	// Timeout limits the whole host change. Zero means no limit.
+	Timeout time.Duration
+	// ShutdownTimeout limits the rollback after the host change was interrupted.
+	ShutdownTimeout time.Duration
+	AliasRemoval    AliasRemoval
+	// FailurePolicy is the name of the policy applied on failed dogu deployments. Empty means the default policy.
+	FailurePolicy string
+	Report        Report
+	History       History
+	Pods          Pods
+	AliasSources  AliasSources
+	Webhook       Webhook
+	// Backend is the name of the backend publishing the host aliases. Empty means the host aliases of the dogu
+	// deployments.
+	Backend string
+	CoreDNS CoreDNS
+}
+
+// CoreDNS configures the config map the coredns backend publishes the host aliases to.
+type CoreDNS struct {
+	// Namespace is the namespace of the config map. Empty means kube-system.
+	Namespace string
+	// ConfigMap is the name of the config map CoreDNS imports server blocks from. Empty means coredns-custom.
+	ConfigMap string
+}
+
+// Webhook configures the mutating admission webhook which injects the host aliases into new dogu deployments.
+type Webhook struct {
+	// Port is the port the webhook server listens on.
+	Port int
+	// CertDir contains the serving certificate tls.crt and its key tls.key. Empty means the default of the
+	// controller-runtime.
+	CertDir string
+	// FailOpen admits dogu deployments without host aliases if they cannot be generated. Otherwise, they are rejected.
+	FailOpen bool
+}
+
+// AliasSources configures where host aliases are read from in addition to the global config. The sources take
+// precedence in the order global config < config map < file < hosts.
+type AliasSources struct {
+	// ConfigMap is the name of a config map in the namespace of the ecosystem whose entries map host names to ips.
+	// Empty disables the config map.
+	ConfigMap string
+	// File is the path of a file in hosts file syntax. Empty disables the file.
+	File string
+	// Hosts maps host names to ips.
+	Hosts map[string]string
+}
+
+// Pods configures the verification of the running pods after the host change.
+type Pods struct {
+	// Verify enables checking that the running pods of the updated dogu deployments carry the new host aliases.
+	Verify bool
+	// Timeout limits the wait for stale pods to be replaced. Zero only reports stale pods.
+	Timeout time.Duration
+	// VerifyHostsFile enables reading the hosts file of a pod of every updated dogu deployment via exec.
+	VerifyHostsFile bool
+}
+
+// History configures the run history stored in the cluster.
+type History struct {
+	// Retention is the number of host changes kept in the history. Zero disables the history.
+	Retention int
+	// TriggeredBy is recorded as the identity which ran the host change. If empty, the identity of the kubernetes
+	// client is used.
+	TriggeredBy string
+}
+
+// Report configures where the result report of a host change is written to in addition to stdout.
+type Report struct {
+	// File is the path of a file the report is written to. The format is YAML for files ending with .yaml or .yml
+	// and JSON otherwise. Empty disables the file.
+	File string
+	// TerminationMessagePath is the path of the container termination message. Empty disables the termination message.
+	TerminationMessagePath string
+}
+
+// AliasRemoval configures the guard against removing existing host aliases from the dogu deployments.
+type AliasRemoval struct {
+	// MaxShare is the share of existing host names which may be removed without confirmation. Zero means no limit.
+	// The removal of all host names always needs a confirmation.
+	MaxShare float64
+	// Confirmed confirms the removal of host names beyond the allowed share.
+	Confirmed bool
+}
+
+// Rollout configures the wait for the rollout of updated dogu deployments.
+type Rollout struct {
+	// Wait enables waiting for rollouts.
+	Wait bool
+	// Timeout limits the wait for a single deployment.
+	Timeout time.Duration
+	// GlobalTimeout limits the wait for all deployments.
+	GlobalTimeout time.Duration
+	// Staged enables updating the deployments in waves ordered by their dogu dependencies.
+	Staged bool
+}
+
+// Canary configures the update of a single dogu before all other dogus.
+type Canary struct {
+	// Enabled enables the canary update.
+	Enabled bool
+	// Dogu is the name of the canary dogu. If empty, the first dogu of the update plan is used.
+	Dogu string
+}
+
+// FromEnv reads the settings from environment variables. Variables which are not set are replaced with defaults.
+func FromEnv() (*Settings, error) {
+	return Load("")
+}
+
+// Load reads the settings from the given configuration file and overrides them with the environment variables which
+// are set. Settings missing in both are replaced with defaults. An empty path skips the configuration file.
+func Load(path string) (*Settings, error) {
+	s := defaults()
+
+	if path != "" {
+		err := s.applyFile(path)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err := s.applyEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// Validators check the settings whose values are defined by the packages using them, e.g. hosts.ParseBackendType.
+// They are passed in, so that the settings do not depend on these packages.
+type Validators struct {
+	// FailurePolicy checks the name of the failure policy.
+	FailurePolicy func(name string) error
+	// Backend checks the name of the backend.
+	Backend func(name string) error
+}
+
+// Validate checks the log level, the log format and, with the given validators, the failure policy and the backend.
+// It is called after the configuration file, the environment and the flags are merged, so that an invalid value fails
+// before any command runs regardless of its source.
+func (s *Settings) Validate(validators Validators) error {
+	err := logging.Options{Level: s.LogLevel, Format: s.LogFormat}.Validate()
+	if err != nil {
+		return fmt.Errorf("invalid settings: %w", err)
+	}
+
+	if validators.FailurePolicy != nil {
+		err = validators.FailurePolicy(s.FailurePolicy)
+		if err != nil {
+			return fmt.Errorf("invalid settings: failurePolicy: %w", err)
+		}
+	}
+
+	if validators.Backend != nil {
+		err = validators.Backend(s.Backend)
+		if err != nil {
+			return fmt.Errorf("invalid settings: backend: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func defaults() *Settings {
+	return &Settings{
+		Rollout: Rollout{
+			Timeout:       defaultRolloutTimeout,
+			GlobalTimeout: defaultRolloutGlobalTimeout,
+		},
+		ShutdownTimeout: defaultShutdownTimeout,
+		AliasRemoval:    AliasRemoval{MaxShare: defaultMaxAliasRemovalShare},
+		History:         History{Retention: defaultHistoryRetention},
+		Webhook:         Webhook{Port: defaultWebhookPort},
+	}
+}
+
+func (s *Settings) applyEnv() error {
+	var err error
+	s.Rollout.Wait, err = getBoolFromEnv(waitForRolloutEnvName, s.Rollout.Wait)
+	if err != nil {
+		return err
+	}
+
+	s.Rollout.Timeout, err = getDurationFromEnv(rolloutTimeoutEnvName, s.Rollout.Timeout)
+	if err != nil {
+		return err
+	}
+
+	s.Rollout.GlobalTimeout, err = getDurationFromEnv(rolloutGlobalTimeoutEnvName, s.Rollout.GlobalTimeout)
+	if err != nil {
+		return err
+	}
+
+	s.Rollout.Staged, err = getBoolFromEnv(stagedRestartEnvName, s.Rollout.Staged)
+	if err != nil {
+		return err
+	}
+
+	s.Canary.Enabled, err = getBoolFromEnv(canaryEnvName, s.Canary.Enabled)
+	if err != nil {
+		return err
+	}
+	s.Canary.Dogu = getStringFromEnv(canaryDoguEnvName, s.Canary.Dogu)
+
+	s.MaintenanceMode, err = getBoolFromEnv(maintenanceModeEnvName, s.MaintenanceMode)
+	if err != nil {
+		return err
+	}
+
+	s.Timeout, err = getDurationFromEnv(timeoutEnvName, s.Timeout)
+	if err != nil {
+		return err
+	}
+
+	s.ShutdownTimeout, err = getDurationFromEnv(shutdownTimeoutEnvName, s.ShutdownTimeout)
+	if err != nil {
+		return err
+	}
+
+	s.AliasRemoval.MaxShare, err = getShareFromEnv(maxAliasRemovalShareEnvName, s.AliasRemoval.MaxShare)
+	if err != nil {
+		return err
+	}
+
+	s.AliasRemoval.Confirmed, err = getBoolFromEnv(confirmAliasRemovalEnvName, s.AliasRemoval.Confirmed)
+	if err != nil {
+		return err
+	}
+
+	s.FailurePolicy = getStringFromEnv(failurePolicyEnvName, s.FailurePolicy)
+	s.Namespace = getStringFromEnv(namespaceEnvName, s.Namespace)
+	s.LogLevel = getStringFromEnv(logLevelEnvName, s.LogLevel)
+	s.LogFormat = getStringFromEnv(logFormatEnvName, s.LogFormat)
+	s.Report.File = getStringFromEnv(reportFileEnvName, s.Report.File)
+	s.Report.TerminationMessagePath = getStringFromEnv(terminationMessageEnvName, s.Report.TerminationMessagePath)
+
+	s.History.Retention, err = getCountFromEnv(historyRetentionEnvName, s.History.Retention)
+	if err != nil {
+		return err
+	}
+	s.History.TriggeredBy = getStringFromEnv(triggeredByEnvName, s.History.TriggeredBy)
+
+	s.Pods.Verify, err = getBoolFromEnv(verifyPodsEnvName, s.Pods.Verify)
+	if err != nil {
+		return err
+	}
+
+	s.Pods.Timeout, err = getDurationFromEnv(podTimeoutEnvName, s.Pods.Timeout)
+	if err != nil {
+		return err
+	}
+
+	s.Pods.VerifyHostsFile, err = getBoolFromEnv(verifyHostsFileEnvName, s.Pods.VerifyHostsFile)
+	if err != nil {
+		return err
+	}
+
+	s.AliasSources.ConfigMap = getStringFromEnv(aliasConfigMapEnvName, s.AliasSources.ConfigMap)
+	s.AliasSources.File = getStringFromEnv(aliasFileEnvName, s.AliasSources.File)
+	s.AliasSources.Hosts, err = getHostsFromEnv(additionalHostsEnvName, s.AliasSources.Hosts)
+	if err != nil {
+		return err
+	}
+
+	s.Webhook.Port, err = getCountFromEnv(webhookPortEnvName, s.Webhook.Port)
+	if err != nil {
+		return err
+	}
+	if s.Webhook.Port < 1 || s.Webhook.Port > maxPort {
+		return fmt.Errorf("value of environment variable [%s] must be between 1 and %d", webhookPortEnvName, maxPort)
+	}
+	s.Webhook.CertDir = getStringFromEnv(webhookCertDirEnvName, s.Webhook.CertDir)
+	s.Webhook.FailOpen, err = getBoolFromEnv(webhookFailOpenEnvName, s.Webhook.FailOpen)
+	if err != nil {
+		return err
+	}
+
+	s.Backend = getStringFromEnv(backendEnvName, s.Backend)
+	s.CoreDNS.Namespace = getStringFromEnv(coreDNSNamespaceEnvName, s.CoreDNS.Namespace)
+	s.CoreDNS.ConfigMap = getStringFromEnv(coreDNSConfigMapEnvName, s.CoreDNS.ConfigMap)
+
+	return nil
+}
+
+func getStringFromEnv(name string, defaultValue string) string {
+	value, found := os.LookupEnv(name)
+	if !found || value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+func getBoolFromEnv(name string, defaultValue bool) (bool, error) {
+	raw, found := os.LookupEnv(name)
+	if !found || raw == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.ParseBool(raw)
+	if err != nil {
+		return defaultValue, fmt.Errorf("value of environment variable [%s] is not a valid boolean: %w", name, err)
+	}
+
+	return value, nil
+}
+
+func getDurationFromEnv(name string, defaultValue time.Duration) (time.Duration, error) {
+	raw, found := os.LookupEnv(name)
+	if !found || raw == "" {
+		return defaultValue, nil
+	}
+
+	value, err := time.ParseDuration(raw)
+	if err != nil {
+		return defaultValue, fmt.Errorf("value of environment variable [%s] is not a valid duration: %w", name, err)
+	}
+	if value < 0 {
+		return defaultValue, fmt.Errorf("value of environment variable [%s] must not be negative", name)
+	}
+
+	return value, nil
+}
+
+func getCountFromEnv(name string, defaultValue int) (int, error) {
+	raw, found := os.LookupEnv(name)
+	if !found || raw == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.Atoi(raw)
+	if err != nil {
+		return defaultValue, fmt.Errorf("value of environment variable [%s] is not a valid number: %w", name, err)
+	}
+	if value < 0 {
+		return defaultValue, fmt.Errorf("value of environment variable [%s] must not be negative", name)
+	}
+
+	return value, nil
+}
+
+// getHostsFromEnv reads a comma separated list of host=ip pairs.
+func getHostsFromEnv(name string, defaultValue map[string]string) (map[string]string, error) {
+	raw, found := os.LookupEnv(name)
+	if !found || raw == "" {
+		return defaultValue, nil
+	}
+
+	hosts := map[string]string{}
+	for _, pair := range strings.Split(raw, ",") {
+		host, ip, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || host == "" || net.ParseIP(ip) == nil {
+			return defaultValue, fmt.Errorf("value of environment variable [%s] is not a valid list of host=ip pairs: '%s'", name, pair)
+		}
+		hosts[host] = ip
+	}
+
+	return hosts, nil
+}
+
+func getShareFromEnv(name string, defaultValue float64) (float64, error) {
+	raw, found := os.LookupEnv(name)
+	if !found || raw == "" {
+		return defaultValue, nil
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return defaultValue, fmt.Errorf("value of environment variable [%s] is not a valid number: %w", name, err)
+	}
+	if value < 0 || value > 1 {
+		return defaultValue, fmt.Errorf("value of environment variable [%s] must be between 0 and 1", name)
+	}
+
+	return value, nil
+}
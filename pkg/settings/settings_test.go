@@ -0,0 +1,381 @@
+package settings
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFromEnv(t *testing.T) {
+	t.Run("should return defaults if nothing is set", func(t *testing.T) {
+		// given
+		t.Setenv(waitForRolloutEnvName, "")
+		t.Setenv(rolloutTimeoutEnvName, "")
+		t.Setenv(rolloutGlobalTimeoutEnvName, "")
+		t.Setenv(stagedRestartEnvName, "")
+		t.Setenv(canaryEnvName, "")
+		t.Setenv(canaryDoguEnvName, "")
+		t.Setenv(maintenanceModeEnvName, "")
+		t.Setenv(timeoutEnvName, "")
+		t.Setenv(shutdownTimeoutEnvName, "")
+		t.Setenv(maxAliasRemovalShareEnvName, "")
+		t.Setenv(confirmAliasRemovalEnvName, "")
+		t.Setenv(failurePolicyEnvName, "")
+		t.Setenv(namespaceEnvName, "")
+		t.Setenv(logLevelEnvName, "")
+		t.Setenv(historyRetentionEnvName, "")
+		t.Setenv(triggeredByEnvName, "")
+		t.Setenv(verifyPodsEnvName, "")
+		t.Setenv(podTimeoutEnvName, "")
+		t.Setenv(verifyHostsFileEnvName, "")
+		t.Setenv(aliasConfigMapEnvName, "")
+		t.Setenv(aliasFileEnvName, "")
+		t.Setenv(additionalHostsEnvName, "")
+		t.Setenv(webhookPortEnvName, "")
+		t.Setenv(webhookCertDirEnvName, "")
+		t.Setenv(webhookFailOpenEnvName, "")
+		t.Setenv(backendEnvName, "")
+		t.Setenv(coreDNSNamespaceEnvName, "")
+		t.Setenv(coreDNSConfigMapEnvName, "")
+
+		// when
+		actual, err := FromEnv()
+
+		// then
+		require.NoError(t, err)
+		assert.Zero(t, actual.Timeout)
+		assert.Equal(t, defaultShutdownTimeout, actual.ShutdownTimeout)
+		assert.Equal(t, Canary{}, actual.Canary)
+		assert.False(t, actual.MaintenanceMode)
+		assert.False(t, actual.Rollout.Wait)
+		assert.False(t, actual.Rollout.Staged)
+		assert.Equal(t, defaultRolloutTimeout, actual.Rollout.Timeout)
+		assert.Equal(t, defaultRolloutGlobalTimeout, actual.Rollout.GlobalTimeout)
+		assert.Equal(t, AliasRemoval{MaxShare: defaultMaxAliasRemovalShare}, actual.AliasRemoval)
+		assert.Empty(t, actual.FailurePolicy)
+		assert.Empty(t, actual.Namespace)
+		assert.Empty(t, actual.LogLevel)
+		assert.Equal(t, History{Retention: defaultHistoryRetention}, actual.History)
+		assert.Equal(t, Pods{}, actual.Pods)
+		assert.Equal(t, AliasSources{}, actual.AliasSources)
+		assert.Equal(t, Webhook{Port: defaultWebhookPort}, actual.Webhook)
+		assert.Empty(t, actual.Backend)
+		assert.Equal(t, CoreDNS{}, actual.CoreDNS)
+	})
+	t.Run("should read rollout settings", func(t *testing.T) {
+		// given
+		t.Setenv(waitForRolloutEnvName, "true")
+		t.Setenv(rolloutTimeoutEnvName, "90s")
+		t.Setenv(rolloutGlobalTimeoutEnvName, "1h")
+		t.Setenv(stagedRestartEnvName, "1")
+
+		// when
+		actual, err := FromEnv()
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, actual.Rollout.Wait)
+		assert.True(t, actual.Rollout.Staged)
+		assert.Equal(t, 90*time.Second, actual.Rollout.Timeout)
+		assert.Equal(t, time.Hour, actual.Rollout.GlobalTimeout)
+	})
+	t.Run("should read canary settings", func(t *testing.T) {
+		// given
+		t.Setenv(canaryEnvName, "true")
+		t.Setenv(canaryDoguEnvName, "nginx")
+
+		// when
+		actual, err := FromEnv()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, Canary{Enabled: true, Dogu: "nginx"}, actual.Canary)
+	})
+	t.Run("should read maintenance mode setting", func(t *testing.T) {
+		// given
+		t.Setenv(maintenanceModeEnvName, "true")
+
+		// when
+		actual, err := FromEnv()
+
+		// then
+		require.NoError(t, err)
+		assert.True(t, actual.MaintenanceMode)
+	})
+	t.Run("should read timeouts", func(t *testing.T) {
+		// given
+		t.Setenv(timeoutEnvName, "2h")
+		t.Setenv(shutdownTimeoutEnvName, "50s")
+
+		// when
+		actual, err := FromEnv()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, 2*time.Hour, actual.Timeout)
+		assert.Equal(t, 50*time.Second, actual.ShutdownTimeout)
+	})
+	t.Run("should read alias removal settings", func(t *testing.T) {
+		// given
+		t.Setenv(maxAliasRemovalShareEnvName, "0.25")
+		t.Setenv(confirmAliasRemovalEnvName, "true")
+
+		// when
+		actual, err := FromEnv()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, AliasRemoval{MaxShare: 0.25, Confirmed: true}, actual.AliasRemoval)
+	})
+	t.Run("should read failure policy", func(t *testing.T) {
+		// given
+		t.Setenv(failurePolicyEnvName, "fail-fast")
+
+		// when
+		actual, err := FromEnv()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "fail-fast", actual.FailurePolicy)
+	})
+	t.Run("should fail on invalid boolean", func(t *testing.T) {
+		// given
+		t.Setenv(waitForRolloutEnvName, "maybe")
+
+		// when
+		_, err := FromEnv()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "value of environment variable [WAIT_FOR_ROLLOUT] is not a valid boolean")
+	})
+	t.Run("should fail on invalid duration", func(t *testing.T) {
+		// given
+		t.Setenv(waitForRolloutEnvName, "")
+		t.Setenv(rolloutTimeoutEnvName, "soon")
+
+		// when
+		_, err := FromEnv()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "value of environment variable [ROLLOUT_TIMEOUT] is not a valid duration")
+	})
+	t.Run("should fail on negative duration", func(t *testing.T) {
+		// given
+		t.Setenv(waitForRolloutEnvName, "")
+		t.Setenv(rolloutTimeoutEnvName, "")
+		t.Setenv(rolloutGlobalTimeoutEnvName, "-1m")
+
+		// when
+		_, err := FromEnv()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "value of environment variable [ROLLOUT_GLOBAL_TIMEOUT] must not be negative")
+	})
+	t.Run("should fail on invalid share", func(t *testing.T) {
+		// given
+		t.Setenv(maxAliasRemovalShareEnvName, "half")
+
+		// when
+		_, err := FromEnv()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "value of environment variable [MAX_ALIAS_REMOVAL_SHARE] is not a valid number")
+	})
+	t.Run("should fail on share out of range", func(t *testing.T) {
+		// given
+		t.Setenv(maxAliasRemovalShareEnvName, "1.5")
+
+		// when
+		_, err := FromEnv()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "value of environment variable [MAX_ALIAS_REMOVAL_SHARE] must be between 0 and 1")
+	})
+	t.Run("should read history settings", func(t *testing.T) {
+		// given
+		t.Setenv(historyRetentionEnvName, "5")
+		t.Setenv(triggeredByEnvName, "admin")
+
+		// when
+		actual, err := FromEnv()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, History{Retention: 5, TriggeredBy: "admin"}, actual.History)
+	})
+	t.Run("should fail on negative history retention", func(t *testing.T) {
+		// given
+		t.Setenv(historyRetentionEnvName, "-1")
+
+		// when
+		_, err := FromEnv()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "value of environment variable [HISTORY_RETENTION] must not be negative")
+	})
+	t.Run("should read pod verification settings", func(t *testing.T) {
+		// given
+		t.Setenv(verifyPodsEnvName, "true")
+		t.Setenv(podTimeoutEnvName, "2m")
+		t.Setenv(verifyHostsFileEnvName, "true")
+
+		// when
+		actual, err := FromEnv()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, Pods{Verify: true, Timeout: 2 * time.Minute, VerifyHostsFile: true}, actual.Pods)
+	})
+	t.Run("should fail on invalid pod verification setting", func(t *testing.T) {
+		// given
+		t.Setenv(verifyPodsEnvName, "maybe")
+
+		// when
+		_, err := FromEnv()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "value of environment variable [VERIFY_PODS] is not a valid boolean")
+	})
+	t.Run("should read alias sources", func(t *testing.T) {
+		// given
+		t.Setenv(aliasConfigMapEnvName, "additional-hosts")
+		t.Setenv(aliasFileEnvName, "/etc/k8s-host-change/hosts")
+		t.Setenv(additionalHostsEnvName, "git.local=10.0.0.2, ldap.local=fd00::4")
+
+		// when
+		actual, err := FromEnv()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, AliasSources{
+			ConfigMap: "additional-hosts",
+			File:      "/etc/k8s-host-change/hosts",
+			Hosts:     map[string]string{"git.local": "10.0.0.2", "ldap.local": "fd00::4"},
+		}, actual.AliasSources)
+	})
+	t.Run("should fail on invalid additional hosts", func(t *testing.T) {
+		// given
+		t.Setenv(additionalHostsEnvName, "git.local=10.0.0.2,ldap.local")
+
+		// when
+		_, err := FromEnv()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "value of environment variable [ADDITIONAL_HOSTS] is not a valid list of host=ip pairs: 'ldap.local'")
+	})
+	t.Run("should read webhook settings", func(t *testing.T) {
+		// given
+		t.Setenv(webhookPortEnvName, "8443")
+		t.Setenv(webhookCertDirEnvName, "/certs")
+		t.Setenv(webhookFailOpenEnvName, "true")
+
+		// when
+		actual, err := FromEnv()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, Webhook{Port: 8443, CertDir: "/certs", FailOpen: true}, actual.Webhook)
+	})
+	t.Run("should fail on invalid webhook port", func(t *testing.T) {
+		// given
+		t.Setenv(webhookPortEnvName, "70000")
+
+		// when
+		_, err := FromEnv()
+
+		// then
+		require.Error(t, err)
+		assert.ErrorContains(t, err, "value of environment variable [WEBHOOK_PORT] must be between 1 and 65535")
+	})
+	t.Run("should read backend settings", func(t *testing.T) {
+		// given
+		t.Setenv(backendEnvName, "coredns")
+		t.Setenv(coreDNSNamespaceEnvName, "dns")
+		t.Setenv(coreDNSConfigMapEnvName, "coredns-import")
+
+		// when
+		actual, err := FromEnv()
+
+		// then
+		require.NoError(t, err)
+		assert.Equal(t, "coredns", actual.Backend)
+		assert.Equal(t, CoreDNS{Namespace: "dns", ConfigMap: "coredns-import"}, actual.CoreDNS)
+	})
+}
+
+func TestSettings_Validate(t *testing.T) {
+	rejectInvalid := func(name string) error {
+		if name == "invalid" {
+			return assert.AnError
+		}
+		return nil
+	}
+	validators := Validators{FailurePolicy: rejectInvalid, Backend: rejectInvalid}
+
+	tests := []struct {
+		name       string
+		settings   Settings
+		validators Validators
+		wantErr    string
+	}{
+		{
+			name:       "should accept valid settings",
+			settings:   Settings{LogLevel: "debug", LogFormat: "json", FailurePolicy: "fail-fast", Backend: "coredns"},
+			validators: validators,
+		},
+		{
+			name:       "should accept empty settings",
+			validators: validators,
+		},
+		{
+			name:       "should fail on invalid log level",
+			settings:   Settings{LogLevel: "loud"},
+			validators: validators,
+			wantErr:    "invalid settings: log level 'loud' is not valid",
+		},
+		{
+			name:       "should fail on invalid log format",
+			settings:   Settings{LogFormat: "xml"},
+			validators: validators,
+			wantErr:    "invalid settings: log format 'xml' is not valid",
+		},
+		{
+			name:       "should fail on invalid failure policy",
+			settings:   Settings{FailurePolicy: "invalid"},
+			validators: validators,
+			wantErr:    "invalid settings: failurePolicy: " + assert.AnError.Error(),
+		},
+		{
+			name:       "should fail on invalid backend",
+			settings:   Settings{Backend: "invalid"},
+			validators: validators,
+			wantErr:    "invalid settings: backend: " + assert.AnError.Error(),
+		},
+		{
+			name:     "should skip enums without validators",
+			settings: Settings{FailurePolicy: "invalid", Backend: "invalid"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.settings.Validate(tt.validators)
+
+			if tt.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.Error(t, err)
+			assert.ErrorContains(t, err, tt.wantErr)
+		})
+	}
+}
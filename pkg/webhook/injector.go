@@ -0,0 +1,101 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/cloudogu/k8s-host-change/pkg/deployment"
+	"github.com/cloudogu/k8s-host-change/pkg/logging"
+)
+
+const doguNameLabelKey = "dogu.name"
+
+// InjectorOptions configures which dogu deployments the HostAliasInjector mutates.
+type InjectorOptions struct {
+	// Namespace is the namespace of the ecosystem. Deployments in other namespaces are admitted unchanged.
+	Namespace string
+	// FailOpen admits deployments unchanged if the host aliases cannot be generated. Otherwise, they are rejected.
+	FailOpen bool
+}
+
+// HostAliasInjector is a mutating admission handler which sets the host aliases of dogu deployments on creation and
+// update, so that newly installed or upgraded dogus carry the host aliases without running the host change again.
+type HostAliasInjector struct {
+	generator hostAliasGenerator
+	decoder   admission.Decoder
+	opts      InjectorOptions
+}
+
+// NewHostAliasInjector creates an admission handler which injects the host aliases of the given generator.
+func NewHostAliasInjector(generator hostAliasGenerator, opts InjectorOptions) *HostAliasInjector {
+	return &HostAliasInjector{
+		generator: generator,
+		decoder:   admission.NewDecoder(scheme.Scheme),
+		opts:      opts,
+	}
+}
+
+// Handle sets the generated host aliases in the pod template of the dogu deployment of the given request. Requests for
+// other objects, other namespaces or by the field manager of the host change are admitted unchanged.
+func (i *HostAliasInjector) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Namespace != i.opts.Namespace {
+		return admission.Allowed(fmt.Sprintf("namespace '%s' is not managed", req.Namespace))
+	}
+	if fieldManager(req) == deployment.FieldManager {
+		return admission.Allowed(fmt.Sprintf("writes of field manager '%s' are managed by the host change", deployment.FieldManager))
+	}
+
+	deploy := &appsv1.Deployment{}
+	err := i.decoder.Decode(req, deploy)
+	if err != nil {
+		return admission.Errored(http.StatusBadRequest, fmt.Errorf("failed to decode deployment: %w", err))
+	}
+	if _, isDogu := deploy.Labels[doguNameLabelKey]; !isDogu {
+		return admission.Allowed(fmt.Sprintf("deployment '%s' is no dogu deployment", deploy.Name))
+	}
+
+	ctx = logging.WithDeployment(ctx, deploy.Name)
+	logger := log.FromContext(ctx)
+	hostAliases, err := i.generator.Generate(ctx)
+	if err != nil {
+		err = fmt.Errorf("failed to generate host aliases for deployment '%s': %w", deploy.Name, err)
+		if i.opts.FailOpen {
+			logger.Error(err, "Admit deployment without host aliases")
+			return admission.Allowed("host aliases could not be generated").WithWarnings(err.Error())
+		}
+		return admission.Errored(http.StatusInternalServerError, err)
+	}
+
+	if deployment.SameHostAliases(deploy.Spec.Template.Spec.HostAliases, hostAliases) {
+		return admission.Allowed("host aliases are up to date")
+	}
+
+	logger.Info("Inject host aliases", "operation", req.Operation, "hostAliases", hostAliases)
+	deploy.Spec.Template.Spec.HostAliases = hostAliases
+	mutated, err := json.Marshal(deploy)
+	if err != nil {
+		return admission.Errored(http.StatusInternalServerError, fmt.Errorf("failed to encode deployment '%s': %w", deploy.Name, err))
+	}
+
+	return admission.PatchResponseFromRaw(req.Object.Raw, mutated)
+}
+
+// fieldManager returns the field manager of the create or update options of the given request. It is empty if the
+// request has no options.
+func fieldManager(req admission.Request) string {
+	var opts struct {
+		FieldManager string `json:"fieldManager"`
+	}
+	if len(req.Options.Raw) == 0 || json.Unmarshal(req.Options.Raw, &opts) != nil {
+		return ""
+	}
+
+	return opts.FieldManager
+}
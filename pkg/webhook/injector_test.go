@@ -0,0 +1,194 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	appsv1 "k8s.io/api/apps/v1"
+	authenticationv1 "k8s.io/api/authentication/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const testNamespace = "ecosystem"
+
+var hostAliases = []corev1.HostAlias{{IP: "10.0.0.1", Hostnames: []string{"ces.example.com"}}}
+
+func TestHostAliasInjector_Handle(t *testing.T) {
+	t.Run("should inject host aliases into new dogu deployment", func(t *testing.T) {
+		// given
+		generatorMock := newMockHostAliasGenerator(t)
+		generatorMock.EXPECT().Generate(mock.Anything).Return(hostAliases, nil)
+		injector := NewHostAliasInjector(generatorMock, InjectorOptions{Namespace: testNamespace})
+
+		// when
+		actual := injector.Handle(context.TODO(), admissionRequest(t, admissionv1.Create, doguDeployment(nil), "system:serviceaccount:ecosystem:k8s-dogu-operator"))
+
+		// then
+		assert.True(t, actual.Allowed)
+		require.Len(t, actual.Patches, 1)
+		assert.Equal(t, "add", actual.Patches[0].Operation)
+		assert.Equal(t, "/spec/template/spec/hostAliases", actual.Patches[0].Path)
+	})
+	t.Run("should replace outdated host aliases on update", func(t *testing.T) {
+		// given
+		generatorMock := newMockHostAliasGenerator(t)
+		generatorMock.EXPECT().Generate(mock.Anything).Return(hostAliases, nil)
+		injector := NewHostAliasInjector(generatorMock, InjectorOptions{Namespace: testNamespace})
+		outdated := []corev1.HostAlias{{IP: "10.0.0.9", Hostnames: []string{"ces.example.com"}}}
+
+		// when
+		actual := injector.Handle(context.TODO(), admissionRequest(t, admissionv1.Update, doguDeployment(outdated), "admin"))
+
+		// then
+		assert.True(t, actual.Allowed)
+		assert.NotEmpty(t, actual.Patches)
+	})
+	t.Run("should not patch up to date deployment", func(t *testing.T) {
+		// given
+		generatorMock := newMockHostAliasGenerator(t)
+		generatorMock.EXPECT().Generate(mock.Anything).Return(hostAliases, nil)
+		injector := NewHostAliasInjector(generatorMock, InjectorOptions{Namespace: testNamespace})
+
+		// when
+		actual := injector.Handle(context.TODO(), admissionRequest(t, admissionv1.Update, doguDeployment(hostAliases), "admin"))
+
+		// then
+		assert.True(t, actual.Allowed)
+		assert.Empty(t, actual.Patches)
+		assert.Equal(t, "host aliases are up to date", actual.Result.Message)
+	})
+	t.Run("should ignore deployment without dogu label", func(t *testing.T) {
+		// given
+		injector := NewHostAliasInjector(newMockHostAliasGenerator(t), InjectorOptions{Namespace: testNamespace})
+		deploy := doguDeployment(nil)
+		deploy.Labels = nil
+
+		// when
+		actual := injector.Handle(context.TODO(), admissionRequest(t, admissionv1.Create, deploy, "admin"))
+
+		// then
+		assert.True(t, actual.Allowed)
+		assert.Empty(t, actual.Patches)
+		assert.Equal(t, "deployment 'redmine' is no dogu deployment", actual.Result.Message)
+	})
+	t.Run("should ignore other namespaces", func(t *testing.T) {
+		// given
+		injector := NewHostAliasInjector(newMockHostAliasGenerator(t), InjectorOptions{Namespace: "other"})
+
+		// when
+		actual := injector.Handle(context.TODO(), admissionRequest(t, admissionv1.Create, doguDeployment(nil), "admin"))
+
+		// then
+		assert.True(t, actual.Allowed)
+		assert.Empty(t, actual.Patches)
+	})
+	t.Run("should ignore writes of the host change", func(t *testing.T) {
+		// given
+		injector := NewHostAliasInjector(newMockHostAliasGenerator(t), InjectorOptions{Namespace: testNamespace})
+		req := admissionRequest(t, admissionv1.Update, doguDeployment(nil), "admin")
+		req.Options.Raw = []byte(`{"kind":"UpdateOptions","apiVersion":"meta.k8s.io/v1","fieldManager":"k8s-host-change"}`)
+
+		// when
+		actual := injector.Handle(context.TODO(), req)
+
+		// then
+		assert.True(t, actual.Allowed)
+		assert.Empty(t, actual.Patches)
+		assert.Equal(t, "writes of field manager 'k8s-host-change' are managed by the host change", actual.Result.Message)
+	})
+	t.Run("should inject host aliases on writes of other field managers", func(t *testing.T) {
+		// given
+		generatorMock := newMockHostAliasGenerator(t)
+		generatorMock.EXPECT().Generate(mock.Anything).Return(hostAliases, nil)
+		injector := NewHostAliasInjector(generatorMock, InjectorOptions{Namespace: testNamespace})
+		req := admissionRequest(t, admissionv1.Update, doguDeployment(nil), "admin")
+		req.Options.Raw = []byte(`{"kind":"UpdateOptions","apiVersion":"meta.k8s.io/v1","fieldManager":"kubectl-edit"}`)
+
+		// when
+		actual := injector.Handle(context.TODO(), req)
+
+		// then
+		assert.True(t, actual.Allowed)
+		assert.NotEmpty(t, actual.Patches)
+	})
+	t.Run("should reject deployment if host aliases cannot be generated", func(t *testing.T) {
+		// given
+		generatorMock := newMockHostAliasGenerator(t)
+		generatorMock.EXPECT().Generate(mock.Anything).Return(nil, assert.AnError)
+		injector := NewHostAliasInjector(generatorMock, InjectorOptions{Namespace: testNamespace})
+
+		// when
+		actual := injector.Handle(context.TODO(), admissionRequest(t, admissionv1.Create, doguDeployment(nil), "admin"))
+
+		// then
+		assert.False(t, actual.Allowed)
+		assert.Equal(t, int32(http.StatusInternalServerError), actual.Result.Code)
+		assert.Contains(t, actual.Result.Message, "failed to generate host aliases for deployment 'redmine'")
+	})
+	t.Run("should admit deployment with warning if host aliases cannot be generated and fail open", func(t *testing.T) {
+		// given
+		generatorMock := newMockHostAliasGenerator(t)
+		generatorMock.EXPECT().Generate(mock.Anything).Return(nil, assert.AnError)
+		injector := NewHostAliasInjector(generatorMock, InjectorOptions{Namespace: testNamespace, FailOpen: true})
+
+		// when
+		actual := injector.Handle(context.TODO(), admissionRequest(t, admissionv1.Create, doguDeployment(nil), "admin"))
+
+		// then
+		assert.True(t, actual.Allowed)
+		assert.Empty(t, actual.Patches)
+		require.Len(t, actual.Warnings, 1)
+		assert.Contains(t, actual.Warnings[0], assert.AnError.Error())
+	})
+	t.Run("should fail on invalid object", func(t *testing.T) {
+		// given
+		injector := NewHostAliasInjector(newMockHostAliasGenerator(t), InjectorOptions{Namespace: testNamespace})
+		req := admissionRequest(t, admissionv1.Create, doguDeployment(nil), "admin")
+		req.Object.Raw = []byte("{")
+
+		// when
+		actual := injector.Handle(context.TODO(), req)
+
+		// then
+		assert.False(t, actual.Allowed)
+		assert.Equal(t, int32(http.StatusBadRequest), actual.Result.Code)
+	})
+}
+
+func doguDeployment(hostAliases []corev1.HostAlias) *appsv1.Deployment {
+	deploy := &appsv1.Deployment{
+		TypeMeta: metav1.TypeMeta{Kind: "Deployment", APIVersion: "apps/v1"},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "redmine",
+			Namespace: testNamespace,
+			Labels:    map[string]string{"dogu.name": "redmine"},
+		},
+	}
+	deploy.Spec.Template.Spec.HostAliases = hostAliases
+	return deploy
+}
+
+func admissionRequest(t *testing.T, operation admissionv1.Operation, deploy *appsv1.Deployment, user string) admission.Request {
+	t.Helper()
+	raw, err := json.Marshal(deploy)
+	require.NoError(t, err)
+
+	return admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		UID:       "0b7c8c4a-0d4e-4b9f-8d5e-1f0f8c1f2a3b",
+		Kind:      metav1.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"},
+		Name:      deploy.Name,
+		Namespace: testNamespace,
+		Operation: operation,
+		UserInfo:  authenticationv1.UserInfo{Username: user},
+		Object:    runtime.RawExtension{Raw: raw},
+	}}
+}
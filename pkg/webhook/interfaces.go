@@ -0,0 +1,12 @@
+package webhook
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+type hostAliasGenerator interface {
+	// Generate returns the host aliases all dogu deployments should carry.
+	Generate(ctx context.Context) (hostAliases []corev1.HostAlias, err error)
+}
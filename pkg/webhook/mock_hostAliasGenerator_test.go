@@ -1,12 +1,12 @@
 // Code generated by mockery v2.42.1. DO NOT EDIT.
 
-package hosts
+package webhook
 
 import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
-	v1 "k8s.io/api/core/v1"
+	corev1 "k8s.io/api/core/v1"
 )
 
 // mockHostAliasGenerator is an autogenerated mock type for the hostAliasGenerator type
@@ -23,23 +23,23 @@ func (_m *mockHostAliasGenerator) EXPECT() *mockHostAliasGenerator_Expecter {
 }
 
 // Generate provides a mock function with given fields: ctx
-func (_m *mockHostAliasGenerator) Generate(ctx context.Context) ([]v1.HostAlias, error) {
+func (_m *mockHostAliasGenerator) Generate(ctx context.Context) ([]corev1.HostAlias, error) {
 	ret := _m.Called(ctx)
 
 	if len(ret) == 0 {
 		panic("no return value specified for Generate")
 	}
 
-	var r0 []v1.HostAlias
+	var r0 []corev1.HostAlias
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context) ([]v1.HostAlias, error)); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) ([]corev1.HostAlias, error)); ok {
 		return rf(ctx)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context) []v1.HostAlias); ok {
+	if rf, ok := ret.Get(0).(func(context.Context) []corev1.HostAlias); ok {
 		r0 = rf(ctx)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]v1.HostAlias)
+			r0 = ret.Get(0).([]corev1.HostAlias)
 		}
 	}
 
@@ -70,12 +70,12 @@ func (_c *mockHostAliasGenerator_Generate_Call) Run(run func(ctx context.Context
 	return _c
 }
 
-func (_c *mockHostAliasGenerator_Generate_Call) Return(hostAliases []v1.HostAlias, err error) *mockHostAliasGenerator_Generate_Call {
-	_c.Call.Return(hostAliases, err)
+func (_c *mockHostAliasGenerator_Generate_Call) Return(_a0 []corev1.HostAlias, _a1 error) *mockHostAliasGenerator_Generate_Call {
+	_c.Call.Return(_a0, _a1)
 	return _c
 }
 
-func (_c *mockHostAliasGenerator_Generate_Call) RunAndReturn(run func(context.Context) ([]v1.HostAlias, error)) *mockHostAliasGenerator_Generate_Call {
+func (_c *mockHostAliasGenerator_Generate_Call) RunAndReturn(run func(context.Context) ([]corev1.HostAlias, error)) *mockHostAliasGenerator_Generate_Call {
 	_c.Call.Return(run)
 	return _c
 }
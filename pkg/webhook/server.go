@@ -0,0 +1,34 @@
+package webhook
+
+import (
+	ctrlwebhook "sigs.k8s.io/controller-runtime/pkg/webhook"
+)
+
+const (
+	// MutateDeploymentPath is the path the HostAliasInjector is served at.
+	MutateDeploymentPath = "/mutate-dogu-deployment"
+	// ValidateGlobalConfigPath is the path the GlobalConfigValidator is served at.
+	ValidateGlobalConfigPath = "/validate-global-config"
+)
+
+// ServerOptions configures the webhook server.
+type ServerOptions struct {
+	// Port is the port the server listens on. Zero means 9443.
+	Port int
+	// CertDir contains the serving certificate tls.crt and its key tls.key. The server reloads them when they change,
+	// e.g. after the secret was rotated. Empty means <temp-dir>/k8s-webhook-server/serving-certs.
+	CertDir string
+}
+
+// NewServer creates a TLS server which serves the given injector at MutateDeploymentPath and the given validator at
+// ValidateGlobalConfigPath. A nil injector is not served, e.g. if the host aliases are not published in the dogu
+// deployments.
+func NewServer(injector *HostAliasInjector, validator *GlobalConfigValidator, opts ServerOptions) ctrlwebhook.Server {
+	server := ctrlwebhook.NewServer(ctrlwebhook.Options{Port: opts.Port, CertDir: opts.CertDir})
+	if injector != nil {
+		server.Register(MutateDeploymentPath, &ctrlwebhook.Admission{Handler: injector})
+	}
+	server.Register(ValidateGlobalConfigPath, &ctrlwebhook.Admission{Handler: validator})
+
+	return server
+}
@@ -0,0 +1,120 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestNewServer(t *testing.T) {
+	t.Run("should serve injector over tls", func(t *testing.T) {
+		// given
+		certDir := t.TempDir()
+		certPool := writeServingCert(t, certDir)
+		port := freePort(t)
+
+		generatorMock := newMockHostAliasGenerator(t)
+		generatorMock.EXPECT().Generate(mock.Anything).Return(hostAliases, nil)
+		injector := NewHostAliasInjector(generatorMock, InjectorOptions{Namespace: testNamespace})
+		server := NewServer(injector, NewGlobalConfigValidator(testNamespace), ServerOptions{Port: port, CertDir: certDir})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() {
+			_ = server.Start(ctx)
+		}()
+
+		req := admissionRequest(t, admissionv1.Create, doguDeployment(nil), "admin")
+		review := admissionv1.AdmissionReview{
+			TypeMeta: metav1.TypeMeta{APIVersion: "admission.k8s.io/v1", Kind: "AdmissionReview"},
+			Request:  &req.AdmissionRequest,
+		}
+		body, err := json.Marshal(review)
+		require.NoError(t, err)
+
+		client := &http.Client{Transport: &http.Transport{TLSClientConfig: &tls.Config{RootCAs: certPool}}}
+		url := "https://localhost:" + strconv.Itoa(port) + MutateDeploymentPath
+
+		// when
+		var resp *http.Response
+		require.Eventually(t, func() bool {
+			resp, err = client.Post(url, "application/json", bytes.NewReader(body))
+			return err == nil
+		}, 10*time.Second, 50*time.Millisecond)
+		defer resp.Body.Close()
+
+		// then
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		actual := admissionv1.AdmissionReview{}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&actual))
+		require.NotNil(t, actual.Response)
+		assert.Equal(t, req.UID, actual.Response.UID)
+		assert.True(t, actual.Response.Allowed)
+		require.NotNil(t, actual.Response.PatchType)
+		assert.Equal(t, admissionv1.PatchTypeJSONPatch, *actual.Response.PatchType)
+		assert.Contains(t, string(actual.Response.Patch), "/spec/template/spec/hostAliases")
+	})
+}
+
+// writeServingCert writes a self-signed certificate for localhost into the given directory and returns a pool
+// trusting it.
+func writeServingCert(t *testing.T, dir string) *x509.CertPool {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "localhost"},
+		DNSNames:              []string{"localhost"},
+		IPAddresses:           []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:             time.Now().Add(-time.Minute),
+		NotAfter:              time.Now().Add(time.Hour),
+		KeyUsage:              x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign,
+		ExtKeyUsage:           []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+		BasicConstraintsValid: true,
+		IsCA:                  true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDer, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tls.crt"), certPEM, 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tls.key"), pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDer}), 0o600))
+
+	pool := x509.NewCertPool()
+	require.True(t, pool.AppendCertsFromPEM(certPEM))
+	return pool
+}
+
+func freePort(t *testing.T) int {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	return listener.Addr().(*net.TCPAddr).Port
+}
@@ -0,0 +1,92 @@
+package webhook
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"strings"
+
+	"github.com/cloudogu/k8s-registry-lib/config"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	"github.com/cloudogu/k8s-host-change/pkg/alias"
+)
+
+const (
+	globalConfigName = "global-config"
+	// globalConfigDataKey is the key of the global config in the data of its config map.
+	globalConfigDataKey = "config.yaml"
+)
+
+// GlobalConfigValidator is a validating admission handler which rejects writes of the global config whose host keys
+// the host aliases cannot be generated from. It applies the same rules as the host change, see alias.HostsConfig.
+type GlobalConfigValidator struct {
+	decoder admission.Decoder
+	// namespace is the namespace of the ecosystem. Config maps in other namespaces are admitted unchanged.
+	namespace string
+}
+
+// NewGlobalConfigValidator creates an admission handler which validates the global config in the given namespace.
+func NewGlobalConfigValidator(namespace string) *GlobalConfigValidator {
+	return &GlobalConfigValidator{
+		decoder:   admission.NewDecoder(scheme.Scheme),
+		namespace: namespace,
+	}
+}
+
+// Handle validates the host keys of the global config of the given request. Updates which do not change the host keys
+// are admitted, so that an already invalid global config does not block unrelated changes.
+func (v *GlobalConfigValidator) Handle(ctx context.Context, req admission.Request) admission.Response {
+	if req.Namespace != v.namespace || req.Name != globalConfigName {
+		return admission.Allowed(fmt.Sprintf("config map '%s/%s' is not the global config", req.Namespace, req.Name))
+	}
+
+	newEntries, err := v.hostEntries(req.Object)
+	if err != nil {
+		return admission.Denied(fmt.Sprintf("invalid global config: %s", err))
+	}
+
+	if req.Operation == admissionv1.Update {
+		oldEntries, err := v.hostEntries(req.OldObject)
+		if err == nil && maps.Equal(oldEntries, newEntries) {
+			return admission.Allowed("host keys are unchanged")
+		}
+	}
+
+	hostsConfig, err := alias.ParseHostsConfig(config.CreateGlobalConfig(newEntries))
+	if err == nil {
+		err = hostsConfig.Validate()
+	}
+	if err != nil {
+		log.FromContext(ctx).Info("Reject global config", "user", req.UserInfo.Username, "error", err.Error())
+		return admission.Denied(fmt.Sprintf("invalid host configuration in global config: %s", err))
+	}
+
+	return admission.Allowed("host configuration is valid")
+}
+
+// hostEntries decodes the given global config map and returns its host keys.
+func (v *GlobalConfigValidator) hostEntries(object runtime.RawExtension) (config.Entries, error) {
+	configMap := &corev1.ConfigMap{}
+	err := v.decoder.DecodeRaw(object, configMap)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode config map: %w", err)
+	}
+
+	raw := configMap.Data[globalConfigDataKey]
+	if strings.TrimSpace(raw) == "" {
+		return config.Entries{}, nil
+	}
+
+	entries, err := (&config.YamlConverter{}).Read(strings.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse key '%s': %w", globalConfigDataKey, err)
+	}
+
+	return alias.HostEntries(config.CreateGlobalConfig(entries)), nil
+}
@@ -0,0 +1,138 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	admissionv1 "k8s.io/api/admission/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+const validGlobalConfig = `fqdn: ces.example.com
+k8s:
+  use_internal_ip: "true"
+  internal_ip: 10.0.0.1
+containers:
+  additional_hosts:
+    git: 10.0.0.2
+`
+
+func TestGlobalConfigValidator_Handle(t *testing.T) {
+	tests := []struct {
+		name        string
+		req         admission.Request
+		wantAllowed bool
+		wantMessage string
+	}{
+		{
+			name:        "should admit valid global config",
+			req:         globalConfigRequest(t, admissionv1.Create, globalConfigMap(validGlobalConfig), nil),
+			wantAllowed: true,
+			wantMessage: "host configuration is valid",
+		},
+		{
+			name:        "should admit global config without host keys",
+			req:         globalConfigRequest(t, admissionv1.Create, globalConfigMap(""), nil),
+			wantAllowed: true,
+			wantMessage: "host configuration is valid",
+		},
+		{
+			name:        "should reject invalid internal ip",
+			req:         globalConfigRequest(t, admissionv1.Update, globalConfigMap("fqdn: ces.example.com\nk8s:\n  use_internal_ip: \"true\"\n  internal_ip: 10.0.0\n"), globalConfigMap(validGlobalConfig)),
+			wantMessage: "invalid host configuration in global config: failed to parse value '10.0.0' of field 'k8s/internal_ip' in global config: not a valid ip",
+		},
+		{
+			name:        "should reject missing internal ip if it is used",
+			req:         globalConfigRequest(t, admissionv1.Create, globalConfigMap("fqdn: ces.example.com\nk8s:\n  use_internal_ip: \"true\"\n"), nil),
+			wantMessage: "invalid host configuration in global config: key: k8s/internal_ip does not exist in global config",
+		},
+		{
+			name:        "should reject invalid ip of additional host",
+			req:         globalConfigRequest(t, admissionv1.Create, globalConfigMap("containers:\n  additional_hosts:\n    git: git.example.com\n"), nil),
+			wantMessage: "failed to parse value 'git.example.com' of field 'containers/additional_hosts/git' in global config: not a valid ip",
+		},
+		{
+			name:        "should reject invalid host name of additional host",
+			req:         globalConfigRequest(t, admissionv1.Create, globalConfigMap("containers:\n  additional_hosts:\n    git_server: 10.0.0.2\n"), nil),
+			wantMessage: "invalid key 'containers/additional_hosts/git_server' in global config",
+		},
+		{
+			name:        "should reject malformed global config",
+			req:         globalConfigRequest(t, admissionv1.Create, globalConfigMap("fqdn: [\n"), nil),
+			wantMessage: "invalid global config: failed to parse key 'config.yaml'",
+		},
+		{
+			name: "should admit update which does not change invalid host keys",
+			req: globalConfigRequest(t, admissionv1.Update,
+				globalConfigMap("k8s:\n  use_internal_ip: \"true\"\n  internal_ip: 10.0.0\nadmin_group: admins\n"),
+				globalConfigMap("k8s:\n  use_internal_ip: \"true\"\n  internal_ip: 10.0.0\nadmin_group: users\n")),
+			wantAllowed: true,
+			wantMessage: "host keys are unchanged",
+		},
+		{
+			name:        "should admit other config maps",
+			req:         globalConfigRequest(t, admissionv1.Create, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "other", Namespace: testNamespace}}, nil),
+			wantAllowed: true,
+			wantMessage: "config map 'ecosystem/other' is not the global config",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			validator := NewGlobalConfigValidator(testNamespace)
+
+			// when
+			actual := validator.Handle(context.TODO(), tt.req)
+
+			// then
+			assert.Equal(t, tt.wantAllowed, actual.Allowed)
+			assert.Contains(t, actual.Result.Message, tt.wantMessage)
+		})
+	}
+	t.Run("should admit global config of other namespace", func(t *testing.T) {
+		// given
+		validator := NewGlobalConfigValidator("other")
+
+		// when
+		actual := validator.Handle(context.TODO(), globalConfigRequest(t, admissionv1.Create, globalConfigMap("k8s:\n  internal_ip: 10.0.0\n"), nil))
+
+		// then
+		assert.True(t, actual.Allowed)
+	})
+}
+
+func globalConfigMap(content string) *corev1.ConfigMap {
+	return &corev1.ConfigMap{
+		TypeMeta:   metav1.TypeMeta{Kind: "ConfigMap", APIVersion: "v1"},
+		ObjectMeta: metav1.ObjectMeta{Name: "global-config", Namespace: testNamespace},
+		Data:       map[string]string{"config.yaml": content},
+	}
+}
+
+func globalConfigRequest(t *testing.T, operation admissionv1.Operation, configMap *corev1.ConfigMap, old *corev1.ConfigMap) admission.Request {
+	t.Helper()
+	raw, err := json.Marshal(configMap)
+	require.NoError(t, err)
+
+	req := admission.Request{AdmissionRequest: admissionv1.AdmissionRequest{
+		UID:       "5d3f0c9e-8f1b-4c1a-9e3d-2b7a6c5d4e3f",
+		Kind:      metav1.GroupVersionKind{Version: "v1", Kind: "ConfigMap"},
+		Name:      configMap.Name,
+		Namespace: testNamespace,
+		Operation: operation,
+		Object:    runtime.RawExtension{Raw: raw},
+	}}
+	if old != nil {
+		rawOld, err := json.Marshal(old)
+		require.NoError(t, err)
+		req.OldObject = runtime.RawExtension{Raw: rawOld}
+	}
+
+	return req
+}